@@ -36,11 +36,24 @@ func newAppCmd(cfg config, out io.Writer, packSvc *pack.Client, configDefaultBui
 	cmd.AddCommand(newAppDeployCmd(cfg, params, configDefaultBuilder))
 	cmd.AddCommand(newAppListCmd(cfg, out))
 	cmd.AddCommand(newAppLogCmd(cfg, out, appLog))
+	cmd.AddCommand(newAppRunCmd(cfg, out, appRun))
+	cmd.AddCommand(newAppExecCmd(cfg, out, appExec))
+	cmd.AddCommand(newAppShellCmd(cfg, out, appExec))
 	cmd.AddCommand(newAppRemoveCmd(cfg, out, appRemove))
 	cmd.AddCommand(newAppInfoCmd(cfg, out))
 	cmd.AddCommand(newAppStartCmd(cfg, out, appStart))
 	cmd.AddCommand(newAppStopCmd(cfg, out, appStop))
 	cmd.AddCommand(newAppExportCmd(cfg, exportApp))
+	cmd.AddCommand(newAppCanaryCmd(cfg, out))
+	cmd.AddCommand(newAppSwitchCmd(cfg, out, appSwitch))
+	cmd.AddCommand(newAppAutoscaleCmd(cfg, out))
+	cmd.AddCommand(newAppVolumeCmd(cfg, out))
+	cmd.AddCommand(newAppHistoryCmd(cfg, out, appHistory))
+	cmd.AddCommand(newAppRollbackCmd(cfg, out, appRollback))
+	cmd.AddCommand(newAppResourcesCmd(cfg, out, appResources))
+	cmd.AddCommand(newAppSchedulingCmd(cfg, out, appScheduling))
+	cmd.AddCommand(newAppRenderCmd(cfg, params, out, appRender))
+	cmd.AddCommand(newAppDiffCmd(cfg, params, out))
 	return cmd
 }
 
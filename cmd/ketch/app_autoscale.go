@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const appAutoscaleHelp = `
+Manage autoscaling of the processes of an application.
+`
+
+func newAppAutoscaleCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "autoscale",
+		Short: "Manage autoscaling of the processes of an application",
+		Long:  appAutoscaleHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppAutoscaleSetCmd(cfg, out, appAutoscaleSet))
+	cmd.AddCommand(newAppAutoscaleRemoveCmd(cfg, out, appAutoscaleRemove))
+	return cmd
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appAutoscaleRemoveHelp = `
+Disable autoscaling of an application, or one of the processes of the application.
+The processes are scaled according to their units again.
+`
+
+type appAutoscaleRemoveFn func(context.Context, config, appAutoscaleRemoveOptions, io.Writer) error
+
+func newAppAutoscaleRemoveCmd(cfg config, out io.Writer, appAutoscaleRemove appAutoscaleRemoveFn) *cobra.Command {
+	options := appAutoscaleRemoveOptions{}
+	cmd := &cobra.Command{
+		Use:   "remove APPNAME",
+		Short: "Disable autoscaling of an application, or one of the processes of the application.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appAutoscaleRemoveHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appAutoscaleRemove(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	return cmd
+}
+
+type appAutoscaleRemoveOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+}
+
+func appAutoscaleRemove(ctx context.Context, cfg config, options appAutoscaleRemoveOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	if err := app.SetAutoscaling(s, nil); err != nil {
+		return fmt.Errorf("failed to remove autoscaling: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully removed autoscaling!")
+	return nil
+}
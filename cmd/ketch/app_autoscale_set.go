@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appAutoscaleSetHelp = `
+Enable autoscaling of an application, or one of the processes of the application.
+A HorizontalPodAutoscaler scales the process between min and max replicas to keep
+the average CPU and memory utilization of its pods close to the targets.
+The targets are percentages of the CPU and memory requested by the pods.
+
+  ketch app autoscale set <app name> --process web --min 2 --max 10 --cpu 80
+`
+
+type appAutoscaleSetFn func(context.Context, config, appAutoscaleSetOptions, io.Writer) error
+
+func newAppAutoscaleSetCmd(cfg config, out io.Writer, appAutoscaleSet appAutoscaleSetFn) *cobra.Command {
+	options := appAutoscaleSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "set APPNAME",
+		Short: "Enable autoscaling of an application, or one of the processes of the application.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appAutoscaleSetHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appAutoscaleSet(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.Flags().IntVar(&options.minReplicas, "min", 1, "Minimum number of replicas.")
+	cmd.Flags().IntVar(&options.maxReplicas, "max", 0, "Maximum number of replicas.")
+	cmd.Flags().IntVar(&options.cpu, "cpu", 0, "Target average CPU utilization in percent of the requested CPU.")
+	cmd.Flags().IntVar(&options.memory, "memory", 0, "Target average memory utilization in percent of the requested memory.")
+	cmd.MarkFlagRequired("max")
+	return cmd
+}
+
+type appAutoscaleSetOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	minReplicas       int
+	maxReplicas       int
+	cpu               int
+	memory            int
+}
+
+func (o appAutoscaleSetOptions) autoscaling() (*ketchv1.AutoscalingSpec, error) {
+	if o.minReplicas < 1 || o.maxReplicas < o.minReplicas || o.cpu < 0 || o.memory < 0 {
+		return nil, ErrInvalidAutoscaling
+	}
+	autoscaling := ketchv1.AutoscalingSpec{
+		MinReplicas: o.minReplicas,
+		MaxReplicas: o.maxReplicas,
+	}
+	if o.cpu > 0 {
+		cpu := o.cpu
+		autoscaling.TargetCPUUtilization = &cpu
+	}
+	if o.memory > 0 {
+		memory := o.memory
+		autoscaling.TargetMemoryUtilization = &memory
+	}
+	return &autoscaling, nil
+}
+
+func appAutoscaleSet(ctx context.Context, cfg config, options appAutoscaleSetOptions, out io.Writer) error {
+	autoscaling, err := options.autoscaling()
+	if err != nil {
+		return err
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	if err := app.SetAutoscaling(s, autoscaling); err != nil {
+		return fmt.Errorf("failed to set autoscaling: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully set autoscaling!")
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func autoscaleApp() *ketchv1.App {
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Autoscaling: &ketchv1.AutoscalingSpec{MinReplicas: 1, MaxReplicas: 3}},
+						{Name: "worker"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestAppAutoscaleSet(t *testing.T) {
+	intRef := func(i int) *int { return &i }
+	tests := []struct {
+		name            string
+		options         appAutoscaleSetOptions
+		wantAutoscaling map[string]*ketchv1.AutoscalingSpec
+		wantErr         string
+	}{
+		{
+			name:    "set autoscaling of a process",
+			options: appAutoscaleSetOptions{appName: "go-app", processName: "worker", minReplicas: 2, maxReplicas: 10, cpu: 80, memory: 70},
+			wantAutoscaling: map[string]*ketchv1.AutoscalingSpec{
+				"web":    {MinReplicas: 1, MaxReplicas: 3},
+				"worker": {MinReplicas: 2, MaxReplicas: 10, TargetCPUUtilization: intRef(80), TargetMemoryUtilization: intRef(70)},
+			},
+		},
+		{
+			name:    "set autoscaling of all processes",
+			options: appAutoscaleSetOptions{appName: "go-app", minReplicas: 1, maxReplicas: 5, cpu: 60},
+			wantAutoscaling: map[string]*ketchv1.AutoscalingSpec{
+				"web":    {MinReplicas: 1, MaxReplicas: 5, TargetCPUUtilization: intRef(60)},
+				"worker": {MinReplicas: 1, MaxReplicas: 5, TargetCPUUtilization: intRef(60)},
+			},
+		},
+		{
+			name:    "error - max replicas less than min replicas",
+			options: appAutoscaleSetOptions{appName: "go-app", minReplicas: 3, maxReplicas: 2},
+			wantErr: ErrInvalidAutoscaling.Error(),
+		},
+		{
+			name:    "error - no such process",
+			options: appAutoscaleSetOptions{appName: "go-app", processName: "api", minReplicas: 1, maxReplicas: 2},
+			wantErr: "failed to set autoscaling: process not found",
+		},
+		{
+			name:    "error - no such deployment",
+			options: appAutoscaleSetOptions{appName: "go-app", deploymentVersion: 5, minReplicas: 1, maxReplicas: 2},
+			wantErr: "failed to set autoscaling: deployment not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{autoscaleApp()},
+			}
+			out := &bytes.Buffer{}
+			err := appAutoscaleSet(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully set autoscaling!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			gotAutoscaling := map[string]*ketchv1.AutoscalingSpec{}
+			for _, process := range gotApp.Spec.Deployments[0].Processes {
+				gotAutoscaling[process.Name] = process.Autoscaling
+			}
+			require.Equal(t, tt.wantAutoscaling, gotAutoscaling)
+		})
+	}
+}
+
+func TestAppAutoscaleRemove(t *testing.T) {
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{autoscaleApp()},
+	}
+	out := &bytes.Buffer{}
+	err := appAutoscaleRemove(context.Background(), cfg, appAutoscaleRemoveOptions{appName: "go-app", processName: "web"}, out)
+	require.Nil(t, err)
+	require.Equal(t, "Successfully removed autoscaling!\n", out.String())
+
+	gotApp := ketchv1.App{}
+	err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app"}, &gotApp)
+	require.Nil(t, err)
+	for _, process := range gotApp.Spec.Deployments[0].Processes {
+		require.Nil(t, process.Autoscaling)
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const appCanaryHelp = `
+Manage an active canary deployment of an application.
+`
+
+func newAppCanaryCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "canary",
+		Short: "Manage an active canary deployment of an application",
+		Long:  appCanaryHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppCanaryPromoteCmd(cfg, out, appCanaryPromote))
+	cmd.AddCommand(newAppCanaryPauseCmd(cfg, out, appCanaryPause))
+	cmd.AddCommand(newAppCanaryResumeCmd(cfg, out, appCanaryResume))
+	cmd.AddCommand(newAppCanaryAbortCmd(cfg, out, appCanaryAbort))
+	return cmd
+}
+
+type appCanaryOptions struct {
+	appName string
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appCanaryAbortHelp = `
+Abort the canary deployment of an application.
+All traffic is routed back to the primary deployment and the canary deployment is removed.
+`
+
+type appCanaryAbortFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+func newAppCanaryAbortCmd(cfg config, out io.Writer, appCanaryAbort appCanaryAbortFn) *cobra.Command {
+	options := appCanaryOptions{}
+	cmd := &cobra.Command{
+		Use:   "abort APPNAME",
+		Short: "Abort the canary deployment and roll back to the primary deployment.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appCanaryAbortHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appCanaryAbort(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func appCanaryAbort(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.AbortCanary(); err != nil {
+		return fmt.Errorf("failed to abort canary: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully aborted!")
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appCanaryPauseHelp = `
+Pause the canary deployment of an application.
+Traffic weights are not changed until the canary is resumed.
+`
+
+type appCanaryPauseFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+func newAppCanaryPauseCmd(cfg config, out io.Writer, appCanaryPause appCanaryPauseFn) *cobra.Command {
+	options := appCanaryOptions{}
+	cmd := &cobra.Command{
+		Use:   "pause APPNAME",
+		Short: "Pause the canary deployment at its current step.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appCanaryPauseHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appCanaryPause(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func appCanaryPause(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.PauseCanary(metav1.NewTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to pause canary: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully paused!")
+	return nil
+}
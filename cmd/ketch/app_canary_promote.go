@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appCanaryPromoteHelp = `
+Route all traffic to the canary deployment of an application immediately.
+The primary deployment is removed once the canary is promoted.
+`
+
+type appCanaryPromoteFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+func newAppCanaryPromoteCmd(cfg config, out io.Writer, appCanaryPromote appCanaryPromoteFn) *cobra.Command {
+	options := appCanaryOptions{}
+	cmd := &cobra.Command{
+		Use:   "promote APPNAME",
+		Short: "Route all traffic to the canary deployment immediately.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appCanaryPromoteHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appCanaryPromote(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func appCanaryPromote(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.PromoteCanary(); err != nil {
+		return fmt.Errorf("failed to promote canary: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully promoted!")
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appCanaryResumeHelp = `
+Resume a paused canary deployment of an application.
+The next step keeps the rest of its interval that was left when the canary was paused.
+`
+
+type appCanaryResumeFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+func newAppCanaryResumeCmd(cfg config, out io.Writer, appCanaryResume appCanaryResumeFn) *cobra.Command {
+	options := appCanaryOptions{}
+	cmd := &cobra.Command{
+		Use:   "resume APPNAME",
+		Short: "Resume a paused canary deployment.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appCanaryResumeHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appCanaryResume(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func appCanaryResume(ctx context.Context, cfg config, options appCanaryOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.ResumeCanary(metav1.NewTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to resume canary: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully resumed!")
+	return nil
+}
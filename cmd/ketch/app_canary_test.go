@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func canaryApp(active bool, paused bool) *ketchv1.App {
+	next := metav1.NewTime(time.Now().Add(10 * time.Minute))
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Canary: ketchv1.CanarySpec{
+				Steps:             3,
+				StepWeight:        33,
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: &next,
+				CurrentStep:       2,
+				Active:            active,
+				Paused:            paused,
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 2, RoutingSettings: ketchv1.RoutingSettings{Weight: 67}},
+				{Version: 3, RoutingSettings: ketchv1.RoutingSettings{Weight: 33}},
+			},
+		},
+	}
+	if paused {
+		pausedAt := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+		app.Spec.Canary.PausedAt = &pausedAt
+	}
+	return app
+}
+
+func TestAppCanary(t *testing.T) {
+	type canaryFn func(context.Context, config, appCanaryOptions, io.Writer) error
+
+	tests := []struct {
+		name      string
+		app       *ketchv1.App
+		fn        canaryFn
+		wantErr   string
+		wantOut   string
+		checkFunc func(t *testing.T, app ketchv1.App)
+	}{
+		{
+			name:    "promote",
+			app:     canaryApp(true, false),
+			fn:      appCanaryPromote,
+			wantOut: "Successfully promoted!\n",
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.False(t, app.Spec.Canary.Active)
+				require.Len(t, app.Spec.Deployments, 1)
+				require.Equal(t, ketchv1.DeploymentVersion(3), app.Spec.Deployments[0].Version)
+				require.Equal(t, uint8(100), app.Spec.Deployments[0].RoutingSettings.Weight)
+			},
+		},
+		{
+			name:    "promote a paused canary",
+			app:     canaryApp(true, true),
+			fn:      appCanaryPromote,
+			wantOut: "Successfully promoted!\n",
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.False(t, app.Spec.Canary.Active)
+				require.False(t, app.Spec.Canary.Paused)
+				require.Len(t, app.Spec.Deployments, 1)
+			},
+		},
+		{
+			name:    "abort",
+			app:     canaryApp(true, false),
+			fn:      appCanaryAbort,
+			wantOut: "Successfully aborted!\n",
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.False(t, app.Spec.Canary.Active)
+				require.Len(t, app.Spec.Deployments, 1)
+				require.Equal(t, ketchv1.DeploymentVersion(2), app.Spec.Deployments[0].Version)
+				require.Equal(t, uint8(100), app.Spec.Deployments[0].RoutingSettings.Weight)
+			},
+		},
+		{
+			name:    "pause",
+			app:     canaryApp(true, false),
+			fn:      appCanaryPause,
+			wantOut: "Successfully paused!\n",
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.True(t, app.Spec.Canary.Active)
+				require.True(t, app.Spec.Canary.Paused)
+				require.NotNil(t, app.Spec.Canary.PausedAt)
+			},
+		},
+		{
+			name:    "resume",
+			app:     canaryApp(true, true),
+			fn:      appCanaryResume,
+			wantOut: "Successfully resumed!\n",
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.True(t, app.Spec.Canary.Active)
+				require.False(t, app.Spec.Canary.Paused)
+				require.Nil(t, app.Spec.Canary.PausedAt)
+				// the next step is postponed by the time the canary has been paused.
+				require.True(t, app.Spec.Canary.NextScheduledTime.After(time.Now().Add(14*time.Minute)))
+			},
+		},
+		{
+			name:    "error - pause a paused canary",
+			app:     canaryApp(true, true),
+			fn:      appCanaryPause,
+			wantErr: "failed to pause canary: canary deployment is already paused",
+		},
+		{
+			name:    "error - resume a canary that is not paused",
+			app:     canaryApp(true, false),
+			fn:      appCanaryResume,
+			wantErr: "failed to resume canary: canary deployment is not paused",
+		},
+		{
+			name:    "error - promote an inactive canary",
+			app:     canaryApp(false, false),
+			fn:      appCanaryPromote,
+			wantErr: "failed to promote canary: canary deployment is not active",
+		},
+		{
+			name:    "error - abort an inactive canary",
+			app:     canaryApp(false, false),
+			fn:      appCanaryAbort,
+			wantErr: "failed to abort canary: canary deployment is not active",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app},
+			}
+			out := &bytes.Buffer{}
+			err := tt.fn(context.Background(), cfg, appCanaryOptions{appName: tt.app.Name}, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.app.Name}, &gotApp)
+			require.Nil(t, err)
+			tt.checkFunc(t, gotApp)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/deploy"
 	"github.com/shipa-corp/ketch/internal/validation"
 )
@@ -24,16 +25,24 @@ Details about Procfile conventions can be found here: https://devcenter.heroku.c
 Deploy from an image:
   ketch app deploy <app name> -i myregistry/myimage:latest
 
+Deploy with the blue-green strategy, the new deployment runs next to the current one without traffic
+and is accessible with a preview cname until the app is switched with "ketch app switch":
+  ketch app deploy <app name> -i myregistry/myimage:latest --strategy blue-green --keep-previous 30m
+
 Users can deploy from image or source code by passing a filename such as app.yaml containing fields like:
 	name: test
 	image: gcr.io/shipa-ci/sample-go-app:latest
 	framework: myframework
+
+Print Kubernetes manifests the deployment would produce without changing anything in the cluster:
+  ketch app deploy <app name> -i myregistry/myimage:latest --dry-run
 `
 )
 
 // NewCommand creates a command that will run the app deploy
 func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder string) *cobra.Command {
 	var options deploy.Options
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "deploy [APPNAME|FILENAME] [SOURCE DIRECTORY]",
@@ -48,6 +57,9 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 			if configDefaultBuilder != "" {
 				deploy.DefaultBuilder = configDefaultBuilder
 			}
+			if dryRun {
+				return appDeployDryRun(cmd, cfg, options, params)
+			}
 			return appDeploy(cmd, options, params)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -61,7 +73,17 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 	cmd.Flags().BoolVar(&options.StrictKetchYamlDecoding, deploy.FlagStrict, false, "Enforces strict decoding of ketch.yaml.")
 	cmd.Flags().IntVar(&options.Steps, deploy.FlagSteps, 0, "Number of steps for a canary deployment.")
 	cmd.Flags().StringVar(&options.StepTimeInterval, deploy.FlagStepInterval, "", "Time interval between canary deployment steps. Supported min: m, hour:h, second:s. ex. 1m, 60s, 1h.")
+	cmd.Flags().StringVar(&options.AnalysisPrometheusURL, deploy.FlagAnalysisPrometheusURL, "", "Address of a Prometheus server used to analyze a canary deployment before each step. Not supported by frameworks with the gateway-api ingress type, which have no default queries.")
+	cmd.Flags().IntVar(&options.AnalysisSuccessRate, deploy.FlagAnalysisSuccessRate, 0, "Minimum percentage of non-5xx responses of a canary deployment required to proceed to the next step.")
+	cmd.Flags().IntVar(&options.AnalysisMaxLatency, deploy.FlagAnalysisMaxLatency, 0, "Maximum 99th percentile latency in milliseconds of a canary deployment allowed to proceed to the next step.")
+	cmd.Flags().IntVar(&options.AnalysisFailureLimit, deploy.FlagAnalysisFailureLimit, ketchv1.DefaultCanaryFailureLimit, "Number of failed analysis checks after which a canary deployment is rolled back.")
+	cmd.Flags().StringSliceVar(&options.CanaryHeaders, deploy.FlagCanaryHeader, []string{}, "Route requests with the header to the canary deployment regardless of the traffic weights, ex. X-Canary=true. Frameworks with nginx support only one header or cookie.")
+	cmd.Flags().StringSliceVar(&options.CanaryCookies, deploy.FlagCanaryCookie, []string{}, "Route requests with the cookie to the canary deployment regardless of the traffic weights, ex. canary=always. Frameworks with nginx support only one header or cookie.")
+	cmd.Flags().StringVar(&options.Strategy, deploy.FlagStrategy, "", "Strategy used to roll out new deployments of the app, either replace or blue-green.")
+	cmd.Flags().StringVar(&options.KeepPrevious, deploy.FlagKeepPrevious, "", "Time the previous deployment is kept after a blue-green switch. ex. 30m, 1h.")
+	cmd.Flags().StringVar(&options.PreviewCname, deploy.FlagPreviewCname, "", "Cname to access the idle deployment of a blue-green deployment.")
 	cmd.Flags().BoolVar(&options.Wait, deploy.FlagWait, false, "If true blocks until deploy completes or a timeout occurs.")
+	cmd.Flags().BoolVar(&dryRun, deploy.FlagDryRun, false, "Print Kubernetes manifests of the app rendered with the deployment instead of deploying it.")
 	cmd.Flags().StringVar(&options.Timeout, deploy.FlagTimeout, "20s", "Defines the length of time to block waiting for deployment completion. Supported min: m, hour:h, second:s. ex. 1m, 60s, 1h.")
 
 	cmd.Flags().StringVarP(&options.Description, deploy.FlagDescription, deploy.FlagDescriptionShort, "", "App description.")
@@ -85,16 +107,29 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 }
 
 func appDeploy(cmd *cobra.Command, options deploy.Options, params *deploy.Services) error {
-	var changeSet *deploy.ChangeSet
-	var err error
-	switch {
-	case validation.ValidateYamlFilename(options.AppName):
-		changeSet, err = options.GetChangeSetFromYaml(options.AppName)
-		if err != nil {
-			return err
-		}
-	default:
-		changeSet = options.GetChangeSet(cmd.Flags())
+	changeSet, err := getChangeSet(cmd, options)
+	if err != nil {
+		return err
 	}
 	return deploy.New(changeSet).Run(cmd.Context(), params)
 }
+
+// appDeployDryRun prints manifests of the app as it would be after the deployment.
+func appDeployDryRun(cmd *cobra.Command, cfg config, options deploy.Options, params *deploy.Services) error {
+	changeSet, err := getChangeSet(cmd, options)
+	if err != nil {
+		return err
+	}
+	app, err := deploy.New(changeSet).DryRun(cmd.Context(), params)
+	if err != nil {
+		return err
+	}
+	return writeAppChart(cmd.Context(), cfg, app, "", params.Writer)
+}
+
+func getChangeSet(cmd *cobra.Command, options deploy.Options) (*deploy.ChangeSet, error) {
+	if validation.ValidateYamlFilename(options.AppName) {
+		return options.GetChangeSetFromYaml(options.AppName)
+	}
+	return options.GetChangeSet(cmd.Flags()), nil
+}
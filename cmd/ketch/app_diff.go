@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
+	"github.com/shipa-corp/ketch/internal/deploy"
+	"github.com/shipa-corp/ketch/internal/validation"
+)
+
+const appDiffHelp = `
+Show how Kubernetes resources of an application would change, compared to the resources of its current helm release.
+Every changed resource is shown as a unified diff, fields set by Kubernetes are ignored. Nothing is changed in the cluster.
+Helm releases are read from Secrets, set HELM_DRIVER=configmap if the ketch controller runs with HELM_DRIVER=configmap.
+
+Preview a deployment of a new image:
+  ketch app diff <app name> -i myregistry/myimage:v2
+
+Preview a change of environment variables, the image of the current deployment is deployed again:
+  ketch app diff <app name> --env LOG_LEVEL=debug
+
+Preview a deployment of an application.yaml:
+  ketch app diff app.yaml
+
+Show changes of the app which haven't been applied to its helm release yet, for example after its framework's templates were pushed:
+  ketch app diff <app name>
+`
+
+func newAppDiffCmd(cfg config, params *deploy.Services, out io.Writer) *cobra.Command {
+	var options deploy.Options
+	cmd := &cobra.Command{
+		Use:   "diff [APPNAME|FILENAME]",
+		Short: "Show changes a deployment would make to resources of an application.",
+		Long:  appDiffHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.AppName = args[0]
+			return appDiff(cmd, cfg, options, params, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Image, deploy.FlagImage, deploy.FlagImageShort, "", "Name of the image to be deployed. If not set, the image of the current deployment is used.")
+	cmd.Flags().StringVar(&options.KetchYamlFileName, deploy.FlagKetchYaml, "", "Path to ketch.yaml.")
+	cmd.Flags().StringVarP(&options.Description, deploy.FlagDescription, deploy.FlagDescriptionShort, "", "App description.")
+	cmd.Flags().StringSliceVarP(&options.Envs, deploy.FlagEnvironment, deploy.FlagEnvironmentShort, []string{}, "App env variables.")
+	cmd.Flags().IntVar(&options.Units, deploy.FlagUnits, 1, "Set number of units for deployment.")
+	cmd.Flags().IntVar(&options.Version, deploy.FlagVersion, 1, "Specify version whose units to update. Must be used with units flag!")
+	cmd.Flags().StringVar(&options.Process, deploy.FlagProcess, "", "Specify process whose units to update. Must be used with units flag!")
+	return cmd
+}
+
+func appDiff(cmd *cobra.Command, cfg config, options deploy.Options, params *deploy.Services, out io.Writer) error {
+	app, err := proposedApp(cmd, cfg, options, params)
+	if err != nil {
+		return err
+	}
+	appChrt, namespace, err := appChart(cmd.Context(), cfg, app)
+	if err != nil {
+		return err
+	}
+	// the chart is rendered for the cluster like the controller renders it, otherwise API versions differ from the release.
+	caps := chart.Capabilities(cfg.KubernetesClient().Discovery())
+	proposed, err := chart.RenderChart(*appChrt, chart.NewChartConfig(*app), namespace, caps)
+	if err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+	current, err := chart.DeployedManifest(cfg.KubernetesClient(), namespace, app.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get helm release: %w", err)
+	}
+	diff, err := chart.DiffManifests(current, proposed)
+	if err != nil {
+		return err
+	}
+	if len(diff) == 0 {
+		fmt.Fprintln(out, "No changes.")
+		return nil
+	}
+	fmt.Fprint(out, diff)
+	return nil
+}
+
+// proposedApp returns the app as it would be after a deployment of the application.yaml or the flags,
+// or the current app if neither is given.
+func proposedApp(cmd *cobra.Command, cfg config, options deploy.Options, params *deploy.Services) (*ketchv1.App, error) {
+	if validation.ValidateYamlFilename(options.AppName) {
+		changeSet, err := options.GetChangeSetFromYaml(options.AppName)
+		if err != nil {
+			return nil, err
+		}
+		return deploy.New(changeSet).DryRun(cmd.Context(), params)
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(cmd.Context(), types.NamespacedName{Name: options.AppName}, &app); err != nil {
+		return nil, fmt.Errorf("failed to get app: %w", err)
+	}
+	if cmd.Flags().NFlag() == 0 {
+		return &app, nil
+	}
+	if !cmd.Flags().Changed(deploy.FlagImage) {
+		if len(app.Spec.Deployments) == 0 {
+			return nil, fmt.Errorf("the app has no deployments, an image is required")
+		}
+		// the flag is marked as changed so the change set includes the image.
+		options.Image = app.Spec.Deployments[len(app.Spec.Deployments)-1].Image
+		if err := cmd.Flags().Set(deploy.FlagImage, options.Image); err != nil {
+			return nil, err
+		}
+	}
+	return deploy.New(options.GetChangeSet(cmd.Flags())).DryRun(cmd.Context(), params)
+}
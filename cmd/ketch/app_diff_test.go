@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+func Test_appDiff(t *testing.T) {
+	deployed, err := ioutil.ReadFile("./testdata/app-render/dashboard.output")
+	require.Nil(t, err)
+	disruptionBudget := `{{- if .Capabilities.APIVersions.Has "policy/v1/PodDisruptionBudget" }}
+apiVersion: policy/v1
+{{- else }}
+apiVersion: policy/v1beta1
+{{- end }}
+kind: PodDisruptionBudget
+metadata:
+  name: {{ .Values.app.name }}
+spec:
+  maxUnavailable: 1
+`
+	deployedWithDisruptionBudget := string(deployed) + `
+---
+# Source: dashboard/templates/pdb.yaml
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: dashboard
+spec:
+  maxUnavailable: 1`
+	policyV1 := []*metav1.APIResourceList{
+		{GroupVersion: "policy/v1", APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget"}}},
+	}
+
+	tests := []struct {
+		name         string
+		arguments    []string
+		noRelease    bool
+		application  string
+		templates    map[string]string
+		apiResources []*metav1.APIResourceList
+		deployed     string
+		wantOut      string
+		wantErr      string
+	}{
+		{
+			name:      "new image",
+			arguments: []string{"dashboard", "-i", "shipasoftware/go-app:v2"},
+			wantOut: `--- current/ConfigMap/dashboard-web-1
++++ /dev/null
+@@ -1,10 +0,0 @@
+-apiVersion: v1
+-data:
+-  LOG_LEVEL: debug
+-  cmd: /app
+-  image: shipasoftware/go-app:v1
+-kind: ConfigMap
+-metadata:
+-  annotations:
+-    theketch.io/config-checksum: dd853f51eeca5dd5cc4c3ca630dee63543cb4bd1d1514a4d1edb4578dc482770
+-  name: dashboard-web-1
+--- /dev/null
++++ proposed/ConfigMap/dashboard-web-2
+@@ -0,0 +1,10 @@
++apiVersion: v1
++data:
++  LOG_LEVEL: debug
++  cmd: /app --port 8080
++  image: shipasoftware/go-app:v2
++kind: ConfigMap
++metadata:
++  annotations:
++    theketch.io/config-checksum: dd853f51eeca5dd5cc4c3ca630dee63543cb4bd1d1514a4d1edb4578dc482770
++  name: dashboard-web-2
+`,
+		},
+		{
+			name:      "new environment variables with the current image",
+			arguments: []string{"dashboard", "--env", "LOG_LEVEL=info"},
+			wantOut: `--- current/ConfigMap/dashboard-web-1
++++ proposed/ConfigMap/dashboard-web-1
+@@ -1,7 +1,7 @@
+ apiVersion: v1
+ data:
+-  LOG_LEVEL: debug
+-  cmd: /app
++  LOG_LEVEL: info
++  cmd: /app --port 8080
+   image: shipasoftware/go-app:v1
+ kind: ConfigMap
+ metadata:
+`,
+		},
+		{
+			name:      "no changes",
+			arguments: []string{"dashboard"},
+			wantOut:   "No changes.\n",
+		},
+		{
+			name:      "app without a release",
+			arguments: []string{"dashboard"},
+			noRelease: true,
+			wantOut: `--- /dev/null
++++ proposed/ConfigMap/dashboard-web-1
+@@ -0,0 +1,10 @@
++apiVersion: v1
++data:
++  LOG_LEVEL: debug
++  cmd: /app
++  image: shipasoftware/go-app:v1
++kind: ConfigMap
++metadata:
++  annotations:
++    theketch.io/config-checksum: dd853f51eeca5dd5cc4c3ca630dee63543cb4bd1d1514a4d1edb4578dc482770
++  name: dashboard-web-1
+`,
+		},
+		{
+			name:         "no changes with API versions of the cluster",
+			arguments:    []string{"dashboard"},
+			templates:    map[string]string{"configmap.yaml": renderTemplate, "pdb.yaml": disruptionBudget},
+			apiResources: policyV1,
+			deployed:     deployedWithDisruptionBudget,
+			wantOut:      "No changes.\n",
+		},
+		{
+			name:      "no app",
+			arguments: []string{"go-app", "--env", "LOG_LEVEL=info"},
+			wantErr:   `failed to get app: apps.theketch.io "go-app" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, params := renderTestConfig(t)
+			if tt.templates != nil {
+				cfg.StorageInstance = &mockStorage{
+					OnGet: func(name string) (*templates.Templates, error) {
+						return &templates.Templates{Yamls: tt.templates}, nil
+					},
+				}
+			}
+			cfg.KubernetesClient().Discovery().(*fakediscovery.FakeDiscovery).Resources = tt.apiResources
+			manifest := string(deployed)
+			if len(tt.deployed) > 0 {
+				manifest = tt.deployed
+			}
+			if !tt.noRelease {
+				secrets := driver.NewSecrets(cfg.KubernetesClient().CoreV1().Secrets("ketch-gke"))
+				rel := &release.Release{
+					Name:      "dashboard",
+					Namespace: "ketch-gke",
+					Version:   1,
+					Info:      &release.Info{Status: release.StatusDeployed},
+					Manifest:  manifest,
+				}
+				require.Nil(t, secrets.Create("sh.helm.release.v1.dashboard.v1", rel))
+			}
+			out := &bytes.Buffer{}
+			cmd := newAppDiffCmd(cfg, params, out)
+			cmd.SetArgs(tt.arguments)
+			err := cmd.Execute()
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, "shipasoftware/go-app:v1", app.Spec.Deployments[0].Image)
+		})
+	}
+}
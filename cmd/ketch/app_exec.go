@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+const appExecHelp = `
+Run a command in a running unit of an application.
+The first running unit is used unless --unit is set, units can be narrowed down with --process and --version.
+
+  ketch app exec myapp -- ls /app
+
+Run an interactive command with a terminal:
+  ketch app exec myapp -it -- /bin/bash
+`
+
+type appExecFn func(context.Context, config, appExecOptions, io.Reader, io.Writer, execFn) error
+
+// execFn runs a command in a container of a pod.
+type execFn func(cfg config, pod corev1.Pod, containerName string, options appExecOptions, in io.Reader, out io.Writer) error
+
+func newAppExecCmd(cfg config, out io.Writer, appExec appExecFn) *cobra.Command {
+	options := appExecOptions{}
+	cmd := &cobra.Command{
+		Use:   "exec APPNAME -- COMMAND [ARGS...]",
+		Short: "Run a command in a running unit of an application.",
+		Long:  appExecHelp,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.command = args[1:]
+			return appExec(cmd.Context(), cfg, options, cmd.InOrStdin(), out, execInContainer)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	addUnitFlags(cmd, &options)
+	cmd.Flags().BoolVarP(&options.stdin, "stdin", "i", false, "Pass stdin to the command")
+	cmd.Flags().BoolVarP(&options.tty, "tty", "t", false, "Allocate a terminal for the command")
+	return cmd
+}
+
+func addUnitFlags(cmd *cobra.Command, options *appExecOptions) {
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version")
+	cmd.Flags().StringVarP(&options.unit, "unit", "u", "", "Name of the unit's pod, running units are listed if there is no such unit")
+}
+
+type appExecOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	unit              string
+	command           []string
+	stdin             bool
+	tty               bool
+}
+
+func appExec(ctx context.Context, cfg config, options appExecOptions, in io.Reader, out io.Writer, exec execFn) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	set := appPodsLabels(options.appName, options.processName, options.deploymentVersion)
+	// units of the app, pods of "ketch app run" are left out.
+	set[utils.KetchIsolatedRunLabel] = "false"
+	pods, err := cfg.KubernetesClient().CoreV1().Pods(framework.Spec.NamespaceName).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(set).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list units: %w", err)
+	}
+	pod, err := selectUnit(pods.Items, options.unit)
+	if err != nil {
+		return err
+	}
+	containerName, err := ketchContainerName(*pod)
+	if err != nil {
+		return err
+	}
+	return exec(cfg, *pod, *containerName, options, in, out)
+}
+
+// selectUnit returns the running pod with the given name or the first running pod if the name is empty.
+func selectUnit(pods []corev1.Pod, name string) (*corev1.Pod, error) {
+	var running []corev1.Pod
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	if len(running) == 0 {
+		return nil, ErrNoRunningUnits
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].Name < running[j].Name
+	})
+	if len(name) == 0 {
+		return &running[0], nil
+	}
+	names := make([]string, 0, len(running))
+	for i, pod := range running {
+		if pod.Name == name {
+			return &running[i], nil
+		}
+		names = append(names, pod.Name)
+	}
+	return nil, fmt.Errorf("%w, running units: %s", ErrUnitNotFound, strings.Join(names, ", "))
+}
+
+func execInContainer(cfg config, pod corev1.Pod, containerName string, options appExecOptions, in io.Reader, out io.Writer) error {
+	req := cfg.KubernetesClient().CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: containerName,
+			Command:   options.command,
+			Stdin:     options.stdin || options.tty,
+			Stdout:    true,
+			Stderr:    !options.tty,
+			TTY:       options.tty,
+		}, scheme.ParameterCodec)
+	if options.tty {
+		return streamTTY(cfg.RESTConfig(), req.URL(), in, out)
+	}
+	executor, err := remotecommand.NewSPDYExecutor(cfg.RESTConfig(), "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	streamOptions := remotecommand.StreamOptions{
+		Stdout: out,
+		Stderr: out,
+	}
+	if options.stdin {
+		streamOptions.Stdin = in
+	}
+	return executor.Stream(streamOptions)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+func TestAppExec(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	pod := func(name, process string, phase corev1.PodPhase, isolatedRun string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ketch-gke",
+				Labels: map[string]string{
+					utils.KetchAppNameLabel:           "dashboard",
+					utils.KetchProcessNameLabel:       process,
+					utils.KetchDeploymentVersionLabel: "3",
+					utils.KetchIsolatedRunLabel:       isolatedRun,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "istio-proxy"}, {Name: "dashboard-" + process + "-3"}},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []runtime.Object{
+		pod("dashboard-web-3-7d4f-b2x9k", "web", corev1.PodRunning, "false"),
+		pod("dashboard-web-3-7d4f-a1c8z", "web", corev1.PodPending, "false"),
+		pod("dashboard-worker-3-5c6d-k8m2p", "worker", corev1.PodRunning, "false"),
+		pod("dashboard-worker-3-5c6d-z9q4w", "worker", corev1.PodRunning, "false"),
+		pod("dashboard-run-a1b2c", "", corev1.PodRunning, "true"),
+	}
+	tests := []struct {
+		name          string
+		options       appExecOptions
+		wantPod       string
+		wantContainer string
+		wantErr       string
+	}{
+		{
+			name:          "first running unit of a process",
+			options:       appExecOptions{appName: "dashboard", processName: "worker", command: []string{"ls"}},
+			wantPod:       "dashboard-worker-3-5c6d-k8m2p",
+			wantContainer: "dashboard-worker-3",
+		},
+		{
+			name:          "selected unit",
+			options:       appExecOptions{appName: "dashboard", unit: "dashboard-worker-3-5c6d-z9q4w", command: []string{"ls"}},
+			wantPod:       "dashboard-worker-3-5c6d-z9q4w",
+			wantContainer: "dashboard-worker-3",
+		},
+		{
+			name:    "error - unit not found",
+			options: appExecOptions{appName: "dashboard", processName: "web", unit: "dashboard-web-3-7d4f-a1c8z", command: []string{"ls"}},
+			wantErr: "unit not found, running units: dashboard-web-3-7d4f-b2x9k",
+		},
+		{
+			name:    "error - no running units",
+			options: appExecOptions{appName: "dashboard", deploymentVersion: 2, command: []string{"ls"}},
+			wantErr: "app has no running units",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard, gke},
+				KubeClientObjects: pods,
+			}
+			called := false
+			exec := func(cfg config, pod corev1.Pod, containerName string, options appExecOptions, in io.Reader, out io.Writer) error {
+				called = true
+				require.Equal(t, tt.wantPod, pod.Name)
+				require.Equal(t, tt.wantContainer, containerName)
+				require.Equal(t, tt.options.command, options.command)
+				return nil
+			}
+			err := appExec(context.Background(), cfg, tt.options, &bytes.Buffer{}, &bytes.Buffer{}, exec)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				require.False(t, called)
+				return
+			}
+			require.Nil(t, err)
+			require.True(t, called)
+		})
+	}
+}
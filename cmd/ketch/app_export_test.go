@@ -55,15 +55,16 @@ func Test_newAppExportCmd(t *testing.T) {
 }
 
 type mockStorage struct {
-	OnGet func(name string) (*templates.Templates, error)
+	OnGet    func(name string) (*templates.Templates, error)
+	OnUpdate func(name string, templates templates.Templates, owners ...metav1.OwnerReference) error
 }
 
 func (m mockStorage) Get(name string) (*templates.Templates, error) {
 	return m.OnGet(name)
 }
 
-func (m mockStorage) Update(name string, templates templates.Templates) error {
-	panic("implement me")
+func (m mockStorage) Update(name string, templates templates.Templates, owners ...metav1.OwnerReference) error {
+	return m.OnUpdate(name, templates, owners...)
 }
 
 var _ templates.Client = &mockStorage{}
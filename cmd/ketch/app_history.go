@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/shipa-corp/ketch/cmd/ketch/output"
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appHistoryHelp = `
+List deployments of an application that can be rolled back to with "ketch app rollback".
+The number of kept deployments is limited by the app's revisionHistoryLimit, 10 by default.
+`
+
+type appHistoryOutput struct {
+	Version    string `json:"version" yaml:"version"`
+	Image      string `json:"image" yaml:"image"`
+	Processes  string `json:"processes" yaml:"processes"`
+	DeployedAt string `json:"deployedAt" yaml:"deployedAt"`
+	Status     string `json:"status" yaml:"status"`
+}
+
+type appHistoryFn func(context.Context, config, string, io.Writer) error
+
+func newAppHistoryCmd(cfg config, out io.Writer, appHistory appHistoryFn) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history APPNAME",
+		Short: "List deployments of an application.",
+		Long:  appHistoryHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return appHistory(cmd.Context(), cfg, args[0], out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func appHistory(ctx context.Context, cfg config, appName string, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	return output.Write(generateAppHistoryOutput(app), out, "column")
+}
+
+func generateAppHistoryOutput(app ketchv1.App) []appHistoryOutput {
+	running := make(map[ketchv1.DeploymentVersion]ketchv1.RoutingSettings, len(app.Spec.Deployments))
+	for _, deployment := range app.Spec.Deployments {
+		running[deployment.Version] = deployment.RoutingSettings
+	}
+	var outputs []appHistoryOutput
+	for _, revision := range app.Status.Revisions {
+		processes := make([]string, 0, len(revision.Deployment.Processes))
+		for _, process := range revision.Deployment.Processes {
+			processes = append(processes, process.Name)
+		}
+		status := ""
+		if routingSettings, ok := running[revision.Deployment.Version]; ok {
+			status = fmt.Sprintf("running (%d%%)", routingSettings.Weight)
+		}
+		outputs = append(outputs, appHistoryOutput{
+			Version:    revision.Deployment.Version.String(),
+			Image:      revision.Deployment.Image,
+			Processes:  strings.Join(processes, " "),
+			DeployedAt: revision.DeployedAt.Format(time.RFC3339),
+			Status:     status,
+		})
+	}
+	return outputs
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func historyApp() *ketchv1.App {
+	deployedAt := func(day int) metav1.Time {
+		return metav1.NewTime(time.Date(2021, 3, day, 10, 0, 0, 0, time.UTC))
+	}
+	deployment := func(version int, image string, weight uint8) ketchv1.AppDeploymentSpec {
+		return ketchv1.AppDeploymentSpec{
+			Version: ketchv1.DeploymentVersion(version),
+			Image:   image,
+			Processes: []ketchv1.ProcessSpec{
+				{Name: "web", Cmd: []string{"./web"}},
+				{Name: "worker", Cmd: []string{"./worker"}},
+			},
+			RoutingSettings: ketchv1.RoutingSettings{Weight: weight},
+		}
+	}
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			DeploymentsCount: 3,
+			Deployments:      []ketchv1.AppDeploymentSpec{deployment(3, "go-app:v3", 100)},
+			Env:              []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}},
+		},
+		Status: ketchv1.AppStatus{
+			Revisions: []ketchv1.AppRevision{
+				{Deployment: deployment(1, "go-app:v1", 100), DeployedAt: deployedAt(1), Env: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "info"}}},
+				{Deployment: deployment(2, "go-app:v2", 100), DeployedAt: deployedAt(2), Env: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}}},
+				{Deployment: deployment(3, "go-app:v3", 100), DeployedAt: deployedAt(3), Env: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}}},
+			},
+		},
+	}
+}
+
+func TestAppHistory(t *testing.T) {
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{historyApp()},
+	}
+	out := &bytes.Buffer{}
+	err := appHistory(context.Background(), cfg, "go-app", out)
+	require.Nil(t, err)
+	wantOut := `VERSION    IMAGE        PROCESSES     DEPLOYED AT             STATUS
+1          go-app:v1    web worker    2021-03-01T10:00:00Z    
+2          go-app:v2    web worker    2021-03-02T10:00:00Z    
+3          go-app:v3    web worker    2021-03-03T10:00:00Z    running (100%)
+`
+	require.Equal(t, wantOut, out.String())
+}
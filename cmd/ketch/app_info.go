@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 
 	"github.com/shipa-corp/ketch/cmd/ketch/output"
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
@@ -38,17 +43,23 @@ Address: {{ $address }}
 {{- else }}
 The default cname hasn't assigned yet because "{{ .App.Spec.Framework }}" framework doesn't have ingress service endpoint.
 {{- end }}
+{{- if .Preview }}
+Preview address: {{ .Preview }}
+{{- end }}
 {{- if .App.Spec.DockerRegistry.SecretName }}
 Secret name to pull application's images: {{ .App.Spec.DockerRegistry.SecretName }}
 {{- end }}
 {{ if .App.Spec.Env }}
 Environment variables:
 {{- range .App.Spec.Env }}
-{{ .Name }}={{ .Value }}
+{{ .Name }}={{ .DisplayValue }}
 {{- end }}
 {{- else }}
 No environment variables.
 {{- end }}
+{{- range .Warnings }}
+WARNING: {{ . }}
+{{- end }}
 `
 )
 
@@ -56,6 +67,8 @@ type appInfoContext struct {
 	App         ketchv1.App `json:"app" yaml:"app"`
 	Cnames      []string    `json:"cnames" yaml:"cnames"`
 	NoProcesses bool        `json:"noProcesses" yaml:"noProcesses"`
+	Preview     string      `json:"preview,omitempty" yaml:"preview,omitempty"`
+	Warnings    []string    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 }
 
 type appInfoOutput struct {
@@ -116,6 +129,12 @@ func appInfo(ctx context.Context, cfg config, options appInfoOptions, out io.Wri
 	}
 
 	data := generateAppInfoOutput(app, appPods, framework)
+	warnings, err := disruptionBudgetWarnings(ctx, cfg.DynamicClient(), app, framework.Spec.NamespaceName)
+	if err != nil {
+		// warnings are optional, e.g. the user may not be allowed to list disruption budgets.
+		warnings = []string{fmt.Sprintf("failed to get disruption budgets: %v", err)}
+	}
+	data.AppInfoContext.Warnings = append(deployHookWarnings(app), warnings...)
 
 	buf := bytes.Buffer{}
 	t := template.Must(template.New("app-info").Parse(appInfoTemplate))
@@ -149,12 +168,67 @@ func generateAppInfoOutput(app ketchv1.App, appPods *v1.PodList, framework *ketc
 		Cnames:      app.CNames(framework),
 		NoProcesses: noProcesses,
 	}
+	if preview := app.PreviewCname(framework); preview != nil {
+		infoContext.Preview = fmt.Sprintf("http://%s", *preview)
+	}
 
 	return appInfoOutput{
 		infoContext, deployments,
 	}
 }
 
+// deployHookWarnings returns a warning if a deploy hook of the app failed, a failed pre-deploy hook holds off the rollout of its deployment.
+func deployHookWarnings(app ketchv1.App) []string {
+	condition := app.Status.Condition(ketchv1.DeployHooksCompleted)
+	if condition == nil || condition.Status != v1.ConditionFalse {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s, delete the job to run the hook again or deploy a new version", condition.Message)}
+}
+
+// disruptionBudgetWarnings returns a warning for every PodDisruptionBudget of the app that currently allows no evictions,
+// so draining a node running units of the process would be blocked until more units are healthy.
+func disruptionBudgetWarnings(ctx context.Context, iface dynamic.Interface, app ketchv1.App, namespace string) ([]string, error) {
+	var budgets *unstructured.UnstructuredList
+	var err error
+	// policy/v1 is served by kubernetes 1.21 and later, budgets are rendered as policy/v1beta1 to older clusters.
+	for _, version := range []string{"v1", "v1beta1"} {
+		gvr := schema.GroupVersionResource{
+			Group:    "policy",
+			Version:  version,
+			Resource: "poddisruptionbudgets",
+		}
+		budgets, err = iface.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: fmt.Sprintf(`%s=%s`, utils.KetchAppNameLabel, app.Name),
+		})
+		if !apierrors.IsNotFound(err) {
+			break
+		}
+	}
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(budgets.Items, func(i, j int) bool {
+		return budgets.Items[i].GetName() < budgets.Items[j].GetName()
+	})
+	var warnings []string
+	for _, budget := range budgets.Items {
+		expectedPods, _, _ := unstructured.NestedInt64(budget.Object, "status", "expectedPods")
+		disruptionsAllowed, _, _ := unstructured.NestedInt64(budget.Object, "status", "disruptionsAllowed")
+		if expectedPods == 0 || disruptionsAllowed > 0 {
+			continue
+		}
+		currentHealthy, _, _ := unstructured.NestedInt64(budget.Object, "status", "currentHealthy")
+		desiredHealthy, _, _ := unstructured.NestedInt64(budget.Object, "status", "desiredHealthy")
+		warnings = append(warnings, fmt.Sprintf("process %s: disruption budget %s allows no evictions, draining nodes with its units is blocked (%d of %d units healthy, %d required)",
+			budget.GetLabels()[utils.KetchProcessNameLabel], budget.GetName(), currentHealthy, expectedPods, desiredHealthy))
+	}
+	return warnings, nil
+}
+
 func filterProcessDeploymentPods(appPods []corev1.Pod, version, process string) []corev1.Pod {
 	var pods []corev1.Pod
 	for _, pod := range appPods {
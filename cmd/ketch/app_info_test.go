@@ -3,17 +3,55 @@ package main
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io/ioutil"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/mocks"
 )
 
+// forbiddenDynamicClientConfig is a configuration whose dynamic client isn't allowed to list resources.
+type forbiddenDynamicClientConfig struct {
+	*mocks.Configuration
+}
+
+func (cfg forbiddenDynamicClientConfig) DynamicClient() dynamic.Interface {
+	client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+	client.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gr := schema.GroupResource{Group: action.GetResource().Group, Resource: action.GetResource().Resource}
+		return true, nil, apierrors.NewForbidden(gr, "", errors.New("access denied"))
+	})
+	return client
+}
+
+// policyV1NotServedConfig is a configuration of a cluster older than kubernetes 1.21 which serves disruption budgets only as policy/v1beta1.
+type policyV1NotServedConfig struct {
+	*mocks.Configuration
+}
+
+func (cfg policyV1NotServedConfig) DynamicClient() dynamic.Interface {
+	client := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), cfg.DynamicClientObjects...)
+	client.PrependReactor("list", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetResource().Version != "v1" {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewNotFound(schema.GroupResource{Group: "policy", Resource: "poddisruptionbudgets"}, "")
+	})
+	return client
+}
+
 func Test_appInfo(t *testing.T) {
 	dashboard := &ketchv1.App{
 		ObjectMeta: metav1.ObjectMeta{
@@ -62,6 +100,7 @@ func Test_appInfo(t *testing.T) {
 			Env: []ketchv1.Env{
 				{Name: "API_KEY", Value: "public_key"},
 				{Name: "VAR1", Value: "VALUE"},
+				{Name: "DB_PASSWORD", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "DB_PASSWORD"}}},
 			},
 			Framework: "aws",
 			Ingress: ketchv1.IngressSpec{
@@ -69,6 +108,8 @@ func Test_appInfo(t *testing.T) {
 			},
 		},
 	}
+	goAppWithFailedHook := goApp.DeepCopy()
+	goAppWithFailedHook.SetCondition(ketchv1.DeployHooksCompleted, corev1.ConditionFalse, "pre-deploy hook of version 1 failed, see logs of job go-app-pre-deploy-1", metav1.Now())
 	goAppWithSecretName := &ketchv1.App{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "go-app",
@@ -119,6 +160,34 @@ func Test_appInfo(t *testing.T) {
 			},
 		},
 	}
+	disruptionBudgetWithVersion := func(apiVersion, process string, expectedPods, disruptionsAllowed int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": apiVersion,
+				"kind":       "PodDisruptionBudget",
+				"metadata": map[string]interface{}{
+					"name":      "go-app-" + process + "-1",
+					"namespace": "ketch-aws",
+					"labels": map[string]interface{}{
+						"theketch.io/app-name":    "go-app",
+						"theketch.io/app-process": process,
+					},
+				},
+				"spec": map[string]interface{}{
+					"maxUnavailable": int64(1),
+				},
+				"status": map[string]interface{}{
+					"currentHealthy":     expectedPods - 1,
+					"desiredHealthy":     expectedPods - 1,
+					"expectedPods":       expectedPods,
+					"disruptionsAllowed": disruptionsAllowed,
+				},
+			},
+		}
+	}
+	disruptionBudget := func(process string, expectedPods, disruptionsAllowed int64) *unstructured.Unstructured {
+		return disruptionBudgetWithVersion("policy/v1", process, expectedPods, disruptionsAllowed)
+	}
 	tests := []struct {
 		name               string
 		cfg                config
@@ -146,6 +215,48 @@ func Test_appInfo(t *testing.T) {
 			},
 			wantOutputFilename: "./testdata/app-info/go-app.output",
 		},
+		{
+			name: "disruption budget blocking a drain",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{aws, goApp},
+				DynamicClientObjects: []runtime.Object{disruptionBudget("web", 3, 1), disruptionBudget("worker", 2, 0)},
+			},
+			options: appInfoOptions{
+				name: "go-app",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-disruption-budget.output",
+		},
+		{
+			name: "policy/v1beta1 disruption budget blocking a drain",
+			cfg: policyV1NotServedConfig{&mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{aws, goApp},
+				DynamicClientObjects: []runtime.Object{disruptionBudgetWithVersion("policy/v1beta1", "web", 3, 1), disruptionBudgetWithVersion("policy/v1beta1", "worker", 2, 0)},
+			}},
+			options: appInfoOptions{
+				name: "go-app",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-disruption-budget.output",
+		},
+		{
+			name: "disruption budgets aren't allowed to be listed",
+			cfg: forbiddenDynamicClientConfig{&mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{aws, goApp},
+			}},
+			options: appInfoOptions{
+				name: "go-app",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-forbidden-disruption-budgets.output",
+		},
+		{
+			name: "failed pre-deploy hook",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{aws, goAppWithFailedHook},
+			},
+			options: appInfoOptions{
+				name: "go-app",
+			},
+			wantOutputFilename: "./testdata/app-info/go-app-failed-deploy-hook.output",
+		},
 		{
 			name: "cnames, env variables, processes + secret name",
 			cfg: &mocks.Configuration{
@@ -55,6 +55,7 @@ func newAppLogCmd(cfg config, out io.Writer, appLog appLogFn) *cobra.Command {
 	cmd.Flags().BoolVar(&options.ignoreErrors, "ignore-errors", false, "If watching / following pod logs, allow for any errors that occur to be non-fatal")
 	cmd.Flags().BoolVar(&options.prefix, "prefix", false, "Prefix each log line with the log source (pod name and container name)")
 	cmd.Flags().BoolVar(&options.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	cmd.Flags().BoolVar(&options.sidecars, "sidecars", false, "Include logs of the other containers of the pods, like sidecars of the processes")
 
 	return cmd
 }
@@ -67,6 +68,7 @@ type appLogOptions struct {
 	ignoreErrors      bool
 	timestamps        bool
 	prefix            bool
+	sidecars          bool
 }
 
 type watchLogsFn func(client kubernetes.Interface, options watchOptions, readLogs readLogsFn, streamLogs streamLogsFn) error
@@ -80,28 +82,33 @@ func appLog(ctx context.Context, cfg config, options appLogOptions, out io.Write
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
 		return fmt.Errorf("failed to get framework instance: %w", err)
 	}
-	set := map[string]string{
-		utils.KetchAppNameLabel: options.appName,
-	}
-	if len(options.processName) > 0 {
-		set[utils.KetchProcessNameLabel] = options.processName
-	}
-	if options.deploymentVersion > 0 {
-		set[utils.KetchDeploymentVersionLabel] = fmt.Sprintf("%d", options.deploymentVersion)
-	}
-	s := labels.SelectorFromSet(set)
 	opts := watchOptions{
 		namespace:    framework.Spec.NamespaceName,
-		selector:     s,
+		selector:     labels.SelectorFromSet(appPodsLabels(options.appName, options.processName, options.deploymentVersion)),
 		follow:       options.follow,
 		ignoreErrors: options.ignoreErrors,
 		timestamps:   options.timestamps,
 		prefix:       options.prefix,
+		sidecars:     options.sidecars,
 		out:          out,
 	}
 	return watchLogs(cfg.KubernetesClient(), opts, readLogs, streamLogs)
 }
 
+// appPodsLabels returns labels of pods of an application, optionally of the given process and deployment version.
+func appPodsLabels(appName string, processName string, deploymentVersion int) labels.Set {
+	set := labels.Set{
+		utils.KetchAppNameLabel: appName,
+	}
+	if len(processName) > 0 {
+		set[utils.KetchProcessNameLabel] = processName
+	}
+	if deploymentVersion > 0 {
+		set[utils.KetchDeploymentVersionLabel] = fmt.Sprintf("%d", deploymentVersion)
+	}
+	return set
+}
+
 type watchOptions struct {
 	namespace    string
 	selector     labels.Selector
@@ -109,21 +116,59 @@ type watchOptions struct {
 	ignoreErrors bool
 	timestamps   bool
 	prefix       bool
+	sidecars     bool
 	out          io.Writer
 }
 
 // ketchContainerName returns a name of an application container.
 // A pod can have several containers, one of them is defined and created by ketch, it's an application container.
-// The others can be injected by istio, vault, etc.
+// The others are sidecars of the process or can be injected by istio, vault, etc.
 func ketchContainerName(pod corev1.Pod) (*string, error) {
 	// this is an application pod.
-	// it has one app container, we don't care about the other containers.
+	// the name of its app container is a prefix of the pod's name.
+	// a sidecar's name can be a prefix as well, so the longest one is picked.
+	var name *string
+	for i, c := range pod.Spec.Containers {
+		if strings.HasPrefix(pod.Name, c.Name) && (name == nil || len(c.Name) > len(*name)) {
+			name = &pod.Spec.Containers[i].Name
+		}
+	}
+	if name == nil {
+		return nil, fmt.Errorf("pod %s doesn't have an app container", pod.Name)
+	}
+	return name, nil
+}
+
+// logContainerNames returns names of containers of the pod to show logs of.
+// The app container goes first, the other containers are included if sidecars is set.
+func logContainerNames(pod corev1.Pod, sidecars bool) ([]string, error) {
+	containerName, err := ketchContainerName(pod)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{*containerName}
+	if !sidecars {
+		return names, nil
+	}
 	for _, c := range pod.Spec.Containers {
-		if strings.HasPrefix(pod.Name, c.Name) {
-			return &c.Name, nil
+		if c.Name != *containerName {
+			names = append(names, c.Name)
 		}
 	}
-	return nil, fmt.Errorf("pod %s doesn't have an app container", pod.Name)
+	return names, nil
+}
+
+// logSource is a container of a pod logs are read from.
+type logSource struct {
+	podUID        types.UID
+	containerName string
+}
+
+func (s logSource) less(other logSource) bool {
+	if s.podUID != other.podUID {
+		return s.podUID < other.podUID
+	}
+	return s.containerName < other.containerName
 }
 
 func isContainerRunning(pod corev1.Pod, containerName string) bool {
@@ -144,53 +189,57 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 		return err
 	}
 	// we are going to read logs from all running pods, just read without streaming.
-	msgChs := make(map[types.UID]chan logMessage, len(pods.Items))
+	msgChs := make(map[logSource]chan logMessage, len(pods.Items))
 	for _, pod := range pods.Items {
-		containerName, err := ketchContainerName(pod)
+		containerNames, err := logContainerNames(pod, options.sidecars)
 		if err != nil {
 			return err
 		}
-		msgChs[pod.UID] = readLogs(cli.CoreV1().Pods(pod.Namespace).GetLogs, pod, *containerName, options.out)
+		for _, containerName := range containerNames {
+			source := logSource{podUID: pod.UID, containerName: containerName}
+			msgChs[source] = readLogs(cli.CoreV1().Pods(pod.Namespace).GetLogs, pod, containerName, options.out)
+		}
 	}
 
 	// we want to show the logs sorted by timestamp.
-	// lets store one message per pod and then in a loop below we will select one message with minimal time on each iteration.
-	messages := make(map[types.UID]logMessage, len(msgChs))
-	for podUID, msg := range msgChs {
+	// lets store one message per container and then in a loop below we will select one message with minimal time on each iteration.
+	messages := make(map[logSource]logMessage, len(msgChs))
+	for source, msg := range msgChs {
 		if m, ok := <-msg; ok {
-			messages[podUID] = m
+			messages[source] = m
 			continue
 		}
 		// the channel is closed - no logs
-		delete(msgChs, podUID)
+		delete(msgChs, source)
 	}
 
-	// we need a timestamp of the last message of each pod, so later we will stream logs from this time.
+	// we need a timestamp of the last message of each container, so later we will stream logs from this time.
 	// we avoid using sort.Sort because downloading all logs and keeping them in memory can be resource-consuming operation.
-	timeOfLastMessage := make(map[types.UID]time.Time)
+	timeOfLastMessage := make(map[logSource]time.Time)
 	for {
 		// on each iteration we are looking for a message with minimal time
 		if len(messages) == 0 {
 			break
 		}
-		var target types.UID
-		for name, m := range messages {
-			if len(target) == 0 || messages[target].time.After(m.time) {
-				target = name
+		var target *logSource
+		for source, m := range messages {
+			if target == nil || messages[*target].time.After(m.time) || messages[*target].time.Equal(m.time) && source.less(*target) {
+				source := source
+				target = &source
 			}
 		}
-		m := messages[target]
-		timeOfLastMessage[target] = m.time
+		m := messages[*target]
+		timeOfLastMessage[*target] = m.time
 
 		fmt.Fprintf(options.out, "%s", m.Format(options.prefix, options.timestamps))
 
-		m, ok := <-msgChs[target]
+		m, ok := <-msgChs[*target]
 		if !ok {
-			delete(msgChs, target)
-			delete(messages, target)
+			delete(msgChs, *target)
+			delete(messages, *target)
 			continue
 		}
-		messages[target] = m
+		messages[*target] = m
 	}
 
 	if !options.follow {
@@ -207,7 +256,7 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 	}
 
 	msgCh := make(chan logMessage)
-	doneChannels := make(map[types.UID]chan struct{})
+	doneChannels := make(map[logSource]chan struct{})
 
 	for {
 		select {
@@ -219,26 +268,31 @@ func watchLogs(cli kubernetes.Interface, options watchOptions, readLogs readLogs
 			pod := e.Object.(*corev1.Pod)
 			switch e.Type {
 			case watch.Added, watch.Modified:
-				if _, ok := doneChannels[pod.UID]; ok {
-					continue
-				}
-				containerName, err := ketchContainerName(*pod)
+				containerNames, err := logContainerNames(*pod, options.sidecars)
 				if err != nil {
 					if !options.ignoreErrors {
 						return err
 					}
 					continue
 				}
-				if !isContainerRunning(*pod, *containerName) {
-					continue
+				for _, containerName := range containerNames {
+					source := logSource{podUID: pod.UID, containerName: containerName}
+					if _, ok := doneChannels[source]; ok {
+						continue
+					}
+					if !isContainerRunning(*pod, containerName) {
+						continue
+					}
+					logs := cli.CoreV1().Pods(pod.Namespace).GetLogs
+					doneChannels[source] = streamLogs(logs, *pod, containerName, options.out, timeOfLastMessage[source], msgCh)
 				}
-				logs := cli.CoreV1().Pods(pod.Namespace).GetLogs
-				doneChannels[pod.UID] = streamLogs(logs, *pod, *containerName, options.out, timeOfLastMessage[pod.UID], msgCh)
 
 			case watch.Deleted:
-				if doneCh, ok := doneChannels[pod.UID]; ok {
-					doneCh <- struct{}{}
-					delete(doneChannels, pod.UID)
+				for source, doneCh := range doneChannels {
+					if source.podUID == pod.UID {
+						doneCh <- struct{}{}
+						delete(doneChannels, source)
+					}
 				}
 			}
 		case m := <-msgCh:
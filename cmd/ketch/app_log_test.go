@@ -184,6 +184,53 @@ func Test_watchLogs(t *testing.T) {
 			},
 			wantErr: "pod hello-web-1-random doesn't have an app container",
 		},
+		{
+			description: "happy path - sidecar with a name that is a prefix of the pod's name, + prefix",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				prefix:    true,
+			},
+			pods: []*corev1.Pod{
+				createPod("default", "hello-web-1-random", map[string]bool{"hello": true, "hello-web-1": true}, startDate),
+			},
+			wantOutputFilename: "./testdata/app-log/6.output",
+		},
+		{
+			description: "happy path - logs from sidecars, + prefix",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				prefix:    true,
+				sidecars:  true,
+			},
+			pods: []*corev1.Pod{
+				createPod("default", "hello-web-1-random", map[string]bool{"hello": true, "hello-web-1": true, "istio-proxy": false}, startDate),
+			},
+			wantOutputFilename: "./testdata/app-log/7.output",
+		},
+		{
+			description: "happy path with streaming: sidecars, the app container is not running",
+			options: watchOptions{
+				namespace: "default",
+				selector:  labels.Everything(),
+				follow:    true,
+				sidecars:  true,
+			},
+			pods: []*corev1.Pod{
+				createPod("default", "hello-web-1-random", map[string]bool{"hello-web-1": false, "cloudsql-proxy": true}, startDate),
+			},
+			watcherHelper: func(watcher *watch.FakeWatcher, pods []*corev1.Pod) {
+				for _, pod := range pods {
+					watcher.Add(pod)
+					watcher.Modify(pod)
+					time.Sleep(1 * time.Second)
+					watcher.Delete(pod)
+				}
+				watcher.Stop()
+			},
+			wantOutputFilename: "./testdata/app-log/stream-3.output",
+		},
 		{
 			description: "happy path with streaming: prefix + timestamps",
 			options: watchOptions{
@@ -462,6 +509,14 @@ func Test_newAppLogCmd(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			description: "happy path: sidecars",
+			args:        []string{"ketch", "foo-bar", "--sidecars"},
+			appLog: func(ctx context.Context, c config, options appLogOptions, writer io.Writer, fn watchLogsFn) error {
+				require.Equal(t, appLogOptions{sidecars: true, appName: "foo-bar"}, options)
+				return nil
+			},
+		},
 		{
 			description: "happy path: deployment version",
 			args:        []string{"ketch", "dashboard", "--version=8"},
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
+	"github.com/shipa-corp/ketch/internal/controllers"
+	"github.com/shipa-corp/ketch/internal/deploy"
+	"github.com/shipa-corp/ketch/internal/validation"
+)
+
+const appRenderHelp = `
+Render Kubernetes manifests of an application with the chart templates of its framework, the same way ketch does.
+Manifests use the API versions served by the cluster, or the defaults of helm if the cluster is unreachable.
+Nothing is changed in the cluster.
+
+Render the manifests of a deployed application:
+  ketch app render <app name>
+
+Render the manifests an application.yaml would produce once deployed:
+  ketch app render app.yaml
+
+Write the helm chart of the application to a directory instead:
+  ketch app render <app name> --chart-dir ./chart
+`
+
+type appRenderFn func(context.Context, config, *deploy.Services, appRenderOptions, io.Writer) error
+
+func newAppRenderCmd(cfg config, params *deploy.Services, out io.Writer, appRender appRenderFn) *cobra.Command {
+	options := appRenderOptions{}
+	cmd := &cobra.Command{
+		Use:   "render [APPNAME|FILENAME]",
+		Short: "Render Kubernetes manifests of an application.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appRenderHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appRender(cmd.Context(), cfg, params, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.chartDirectory, "chart-dir", "", "Directory to write the helm chart of the application to instead of printing the manifests.")
+	return cmd
+}
+
+type appRenderOptions struct {
+	appName        string
+	chartDirectory string
+}
+
+func appRender(ctx context.Context, cfg config, params *deploy.Services, options appRenderOptions, out io.Writer) error {
+	app := &ketchv1.App{}
+	if validation.ValidateYamlFilename(options.appName) {
+		deployOptions := deploy.Options{AppName: options.appName}
+		changeSet, err := deployOptions.GetChangeSetFromYaml(options.appName)
+		if err != nil {
+			return err
+		}
+		if app, err = deploy.New(changeSet).DryRun(ctx, params); err != nil {
+			return err
+		}
+	} else if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	return writeAppChart(ctx, cfg, app, options.chartDirectory, out)
+}
+
+// writeAppChart renders the app's manifests to out or writes its helm chart to the directory if it's set.
+func writeAppChart(ctx context.Context, cfg config, app *ketchv1.App, chartDirectory string, out io.Writer) error {
+	appChrt, namespace, err := appChart(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	chartConfig := chart.NewChartConfig(*app)
+	if len(chartDirectory) > 0 {
+		if err := appChrt.WriteToDirectory(chartDirectory, chartConfig); err != nil {
+			return fmt.Errorf("failed to write chart: %w", err)
+		}
+		fmt.Fprintf(out, "Successfully wrote chart to %s\n", chartDirectory)
+		return nil
+	}
+	manifest, err := chart.RenderChart(*appChrt, chartConfig, namespace, chart.Capabilities(cfg.KubernetesClient().Discovery()))
+	if err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+	fmt.Fprint(out, manifest)
+	return nil
+}
+
+// appChart returns a helm chart of the app built with the templates of its framework like the app controller builds it,
+// and a namespace the chart is installed to.
+func appChart(ctx context.Context, cfg config, app *ketchv1.App) (*chart.ApplicationChart, string, error) {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return nil, "", fmt.Errorf("failed to get framework: %w", err)
+	}
+	namespace := framework.Spec.NamespaceName
+	if framework.Status.Namespace != nil {
+		namespace = framework.Status.Namespace.Name
+	}
+	tpls, err := cfg.Storage().Get(framework.TemplatesConfigMapName())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get templates: %w", err)
+	}
+	checksum, err := controllers.ConfigChecksum(ctx, cfg.Client(), app, namespace)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to calculate checksum of the app's configuration: %w", err)
+	}
+	appChrt, err := chart.New(app, &framework,
+		chart.WithExposedPorts(app.ExposedPorts()),
+		chart.WithTemplates(*tpls),
+		chart.WithConfigChecksum(checksum))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create chart: %w", err)
+	}
+	return appChrt, namespace, nil
+}
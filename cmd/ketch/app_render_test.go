@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/deploy"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/templates"
+	"github.com/shipa-corp/ketch/internal/utils/conversions"
+)
+
+const renderTemplate = `{{- range $deployment := .Values.app.deployments }}
+{{- range $process := $deployment.processes }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ $.Values.app.name }}-{{ $process.name }}-{{ $deployment.version }}
+  annotations:
+    theketch.io/config-checksum: {{ $.Values.app.configChecksum | quote }}
+data:
+  image: {{ $deployment.image }}
+  cmd: {{ $process.cmd | join " " | quote }}
+  {{- range $.Values.app.env }}
+  {{ .name }}: {{ .value | quote }}
+  {{- end }}
+---
+{{- end }}
+{{- end }}
+`
+
+func renderTestConfig(t *testing.T) (*mocks.Configuration, *deploy.Services) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dashboard",
+			Generation: 2,
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipasoftware/go-app:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+			DeploymentsCount: 1,
+			Env:              []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}},
+			EnvFrom: []v1.EnvFromSource{
+				{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+			},
+			Framework: "gke",
+		},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gke",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			Templates:     "framework-gke-templates",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.IstioIngressControllerType,
+			},
+		},
+		Status: ketchv1.FrameworkStatus{
+			Namespace: &v1.ObjectReference{Name: "ketch-gke"},
+		},
+	}
+	settings := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "ketch-gke"},
+		Data:       map[string]string{"FEATURE": "on"},
+	}
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{dashboard, gke, settings},
+		StorageInstance: &mockStorage{
+			OnGet: func(name string) (*templates.Templates, error) {
+				require.Equal(t, "framework-gke-templates", name)
+				return &templates.Templates{Yamls: map[string]string{"configmap.yaml": renderTemplate}}, nil
+			},
+		},
+	}
+	params := &deploy.Services{
+		Client: cfg.Client(),
+		GetImageConfig: func(ctx context.Context, args deploy.ImageConfigRequest) (*registryv1.ConfigFile, error) {
+			return &registryv1.ConfigFile{
+				Config: registryv1.Config{
+					Cmd: []string{"/app", "--port", "8080"},
+				},
+			}, nil
+		},
+		Writer: &bytes.Buffer{},
+	}
+	return cfg, params
+}
+
+func Test_appRender(t *testing.T) {
+	application := `name: dashboard
+image: shipasoftware/go-app:v2
+framework: gke
+environment:
+  - LOG_LEVEL=info
+`
+	tests := []struct {
+		name               string
+		options            appRenderOptions
+		application        string
+		wantOutputFilename string
+		wantChartFiles     []string
+		wantErr            string
+	}{
+		{
+			name:               "deployed app",
+			options:            appRenderOptions{appName: "dashboard"},
+			wantOutputFilename: "./testdata/app-render/dashboard.output",
+		},
+		{
+			name:               "app from application.yaml",
+			options:            appRenderOptions{appName: "app.yaml"},
+			application:        application,
+			wantOutputFilename: "./testdata/app-render/dashboard-application.output",
+		},
+		{
+			name:           "chart directory",
+			options:        appRenderOptions{appName: "dashboard", chartDirectory: "chart"},
+			wantChartFiles: []string{"Chart.yaml", "templates", "values.yaml"},
+		},
+		{
+			name:    "no app",
+			options: appRenderOptions{appName: "go-app"},
+			wantErr: `failed to get app: apps.theketch.io "go-app" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, params := renderTestConfig(t)
+			dir := t.TempDir()
+			options := tt.options
+			if len(tt.application) > 0 {
+				options.appName = filepath.Join(dir, tt.options.appName)
+				require.Nil(t, ioutil.WriteFile(options.appName, []byte(tt.application), 0644))
+			}
+			if len(options.chartDirectory) > 0 {
+				options.chartDirectory = filepath.Join(dir, options.chartDirectory)
+			}
+			out := &bytes.Buffer{}
+			err := appRender(context.Background(), cfg, params, options, out)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+
+			// the app in the cluster is never changed.
+			app := ketchv1.App{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, "shipasoftware/go-app:v1", app.Spec.Deployments[0].Image)
+
+			if len(tt.wantChartFiles) > 0 {
+				infos, err := ioutil.ReadDir(options.chartDirectory)
+				require.Nil(t, err)
+				var names []string
+				for _, info := range infos {
+					names = append(names, info.Name())
+				}
+				require.Equal(t, tt.wantChartFiles, names)
+				require.FileExists(t, filepath.Join(options.chartDirectory, "templates", "configmap.yaml"))
+				return
+			}
+			wantOut, err := ioutil.ReadFile(tt.wantOutputFilename)
+			require.Nil(t, err)
+			require.Equal(t, string(wantOut), out.String())
+		})
+	}
+}
+
+func Test_appDeployDryRun(t *testing.T) {
+	cfg, params := renderTestConfig(t)
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--image", "shipasoftware/go-app:v2", "--env", "LOG_LEVEL=info", "--dry-run"})
+	require.Nil(t, cmd.Execute())
+
+	wantOut, err := ioutil.ReadFile("./testdata/app-render/dashboard-application.output")
+	require.Nil(t, err)
+	require.Equal(t, string(wantOut), params.Writer.(*bytes.Buffer).String())
+
+	app := ketchv1.App{}
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Equal(t, "shipasoftware/go-app:v1", app.Spec.Deployments[0].Image)
+	require.Equal(t, []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}}, app.Spec.Env)
+}
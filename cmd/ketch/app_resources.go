@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appResourcesHelp = `
+Set CPU and memory requests and limits of an application, or one of the processes of the application.
+The specified values replace the current resources of the processes.
+Processes without resources use the default resources of the framework.
+
+  ketch app resources <app name> --process web --cpu-request 250m --cpu-limit 500m --memory-request 128Mi --memory-limit 256Mi
+
+Remove the resources of the processes to use the framework's defaults again:
+  ketch app resources <app name> --process web --reset
+`
+
+type appResourcesFn func(context.Context, config, appResourcesOptions, io.Writer) error
+
+func newAppResourcesCmd(cfg config, out io.Writer, appResources appResourcesFn) *cobra.Command {
+	options := appResourcesOptions{}
+	cmd := &cobra.Command{
+		Use:   "resources APPNAME",
+		Short: "Set CPU and memory requests and limits of an application, or one of the processes of the application.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appResourcesHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appResources(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.Flags().StringVar(&options.cpuRequest, "cpu-request", "", "Amount of CPU requested by each unit, ex. 250m.")
+	cmd.Flags().StringVar(&options.cpuLimit, "cpu-limit", "", "Maximum amount of CPU each unit can use, ex. 1.")
+	cmd.Flags().StringVar(&options.memoryRequest, "memory-request", "", "Amount of memory requested by each unit, ex. 128Mi.")
+	cmd.Flags().StringVar(&options.memoryLimit, "memory-limit", "", "Maximum amount of memory each unit can use, ex. 256Mi.")
+	cmd.Flags().BoolVar(&options.reset, "reset", false, "Remove the resources of the processes to use the framework's defaults.")
+	return cmd
+}
+
+type appResourcesOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	cpuRequest        string
+	cpuLimit          string
+	memoryRequest     string
+	memoryLimit       string
+	reset             bool
+}
+
+// resources returns resource requirements built from the options or nil if the resources should be reset.
+func (o appResourcesOptions) resources() (*v1.ResourceRequirements, error) {
+	if o.reset {
+		return nil, nil
+	}
+	requests := v1.ResourceList{}
+	limits := v1.ResourceList{}
+	values := []struct {
+		flag  string
+		value string
+		name  v1.ResourceName
+		list  v1.ResourceList
+	}{
+		{flag: "cpu-request", value: o.cpuRequest, name: v1.ResourceCPU, list: requests},
+		{flag: "cpu-limit", value: o.cpuLimit, name: v1.ResourceCPU, list: limits},
+		{flag: "memory-request", value: o.memoryRequest, name: v1.ResourceMemory, list: requests},
+		{flag: "memory-limit", value: o.memoryLimit, name: v1.ResourceMemory, list: limits},
+	}
+	for _, v := range values {
+		if len(v.value) == 0 {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(v.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", v.flag, v.value, err)
+		}
+		v.list[v.name] = quantity
+	}
+	if len(requests) == 0 && len(limits) == 0 {
+		return nil, ErrNoResources
+	}
+	resources := v1.ResourceRequirements{}
+	if len(requests) > 0 {
+		resources.Requests = requests
+	}
+	if len(limits) > 0 {
+		resources.Limits = limits
+	}
+	return &resources, nil
+}
+
+func appResources(ctx context.Context, cfg config, options appResourcesOptions, out io.Writer) error {
+	resources, err := options.resources()
+	if err != nil {
+		return err
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	if err := app.SetResources(s, resources); err != nil {
+		return fmt.Errorf("failed to set resources: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated resources!")
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestAppResources(t *testing.T) {
+	webResources := &v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Resources: webResources},
+						{Name: "worker"},
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		options       appResourcesOptions
+		wantResources map[string]*v1.ResourceRequirements
+		wantErr       string
+	}{
+		{
+			name:    "set resources of a process",
+			options: appResourcesOptions{appName: "go-app", processName: "worker", cpuRequest: "250m", memoryRequest: "128Mi", memoryLimit: "256Mi"},
+			wantResources: map[string]*v1.ResourceRequirements{
+				"web": webResources,
+				"worker": {
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			},
+		},
+		{
+			name:    "set resources of all processes",
+			options: appResourcesOptions{appName: "go-app", cpuLimit: "1"},
+			wantResources: map[string]*v1.ResourceRequirements{
+				"web":    {Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+				"worker": {Limits: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}},
+			},
+		},
+		{
+			name:    "reset resources of a process",
+			options: appResourcesOptions{appName: "go-app", processName: "web", reset: true},
+			wantResources: map[string]*v1.ResourceRequirements{
+				"web":    nil,
+				"worker": nil,
+			},
+		},
+		{
+			name:    "error - no resources",
+			options: appResourcesOptions{appName: "go-app", processName: "web"},
+			wantErr: ErrNoResources.Error(),
+		},
+		{
+			name:    "error - invalid quantity",
+			options: appResourcesOptions{appName: "go-app", memoryLimit: "lots"},
+			wantErr: `invalid memory-limit value "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`,
+		},
+		{
+			name:    "error - no such process",
+			options: appResourcesOptions{appName: "go-app", processName: "api", cpuRequest: "1"},
+			wantErr: "failed to set resources: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{app.DeepCopy()},
+			}
+			out := &bytes.Buffer{}
+			err := appResources(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully updated resources!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			gotResources := map[string]*v1.ResourceRequirements{}
+			for _, process := range gotApp.Spec.Deployments[0].Processes {
+				gotResources[process.Name] = process.Resources
+			}
+			require.Equal(t, tt.wantResources, gotResources)
+		})
+	}
+}
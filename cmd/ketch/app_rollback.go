@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appRollbackHelp = `
+Roll out a new deployment of an application with the exact spec of a deployment from the app's history.
+The versions of the deployments are listed by "ketch app history", only deployments which finished their rollout are kept there.
+The app's environment variables are restored to the ones the deployment ran with.
+
+  ketch app rollback <app name> --to-version 3
+
+Roll back gradually as a canary deployment:
+  ketch app rollback <app name> --to-version 3 --steps 4 --step-interval 5m
+`
+
+type appRollbackFn func(context.Context, config, appRollbackOptions, io.Writer) error
+
+func newAppRollbackCmd(cfg config, out io.Writer, appRollback appRollbackFn) *cobra.Command {
+	options := appRollbackOptions{}
+	cmd := &cobra.Command{
+		Use:   "rollback APPNAME",
+		Short: "Roll back an application to a previous deployment.",
+		Long:  appRollbackHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appRollback(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().IntVar(&options.version, "to-version", 0, "Version of the deployment to roll back to.")
+	cmd.Flags().IntVar(&options.steps, "steps", 0, "Number of steps to roll back as a canary deployment.")
+	cmd.Flags().StringVar(&options.stepInterval, "step-interval", "", "Time interval between canary deployment steps. Supported min: m, hour:h, second:s. ex. 1m, 60s, 1h.")
+	cmd.MarkFlagRequired("to-version")
+	return cmd
+}
+
+type appRollbackOptions struct {
+	appName      string
+	version      int
+	steps        int
+	stepInterval string
+}
+
+func appRollback(ctx context.Context, cfg config, options appRollbackOptions, out io.Writer) error {
+	var stepInterval time.Duration
+	if options.steps > 1 {
+		if options.steps > 100 || len(options.stepInterval) == 0 {
+			return ErrInvalidRollbackCanary
+		}
+		var err error
+		if stepInterval, err = time.ParseDuration(options.stepInterval); err != nil {
+			return fmt.Errorf("failed to parse step interval: %w", err)
+		}
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.RollbackTo(ketchv1.DeploymentVersion(options.version), options.steps, stepInterval, metav1.Now()); err != nil {
+		return fmt.Errorf("failed to roll back: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully rolled back!")
+	return nil
+}
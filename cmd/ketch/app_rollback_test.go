@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestAppRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		app       *ketchv1.App
+		options   appRollbackOptions
+		wantErr   string
+		checkFunc func(t *testing.T, app ketchv1.App)
+	}{
+		{
+			name:    "roll back to a previous deployment",
+			app:     historyApp(),
+			options: appRollbackOptions{appName: "go-app", version: 1},
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.Equal(t, 4, app.Spec.DeploymentsCount)
+				require.Len(t, app.Spec.Deployments, 1)
+				require.Equal(t, ketchv1.DeploymentVersion(4), app.Spec.Deployments[0].Version)
+				require.Equal(t, "go-app:v1", app.Spec.Deployments[0].Image)
+				require.Equal(t, uint8(100), app.Spec.Deployments[0].RoutingSettings.Weight)
+				require.Equal(t, []ketchv1.Env{{Name: "LOG_LEVEL", Value: "info"}}, app.Spec.Env)
+				require.False(t, app.Spec.Canary.Active)
+			},
+		},
+		{
+			name:    "roll back as a canary deployment",
+			app:     historyApp(),
+			options: appRollbackOptions{appName: "go-app", version: 2, steps: 4, stepInterval: "5m"},
+			checkFunc: func(t *testing.T, app ketchv1.App) {
+				require.Len(t, app.Spec.Deployments, 2)
+				require.Equal(t, "go-app:v3", app.Spec.Deployments[0].Image)
+				require.Equal(t, "go-app:v2", app.Spec.Deployments[1].Image)
+				require.Equal(t, ketchv1.DeploymentVersion(4), app.Spec.Deployments[1].Version)
+				require.Equal(t, uint8(0), app.Spec.Deployments[1].RoutingSettings.Weight)
+				require.True(t, app.Spec.Canary.Active)
+				require.Equal(t, 4, app.Spec.Canary.Steps)
+				require.Equal(t, uint8(25), app.Spec.Canary.StepWeight)
+				require.Equal(t, 5*time.Minute, app.Spec.Canary.StepTimeInteval)
+			},
+		},
+		{
+			name:    "error - version not found",
+			app:     historyApp(),
+			options: appRollbackOptions{appName: "go-app", version: 7},
+			wantErr: "failed to roll back: revision not found",
+		},
+		{
+			name:    "error - canary without step interval",
+			app:     historyApp(),
+			options: appRollbackOptions{appName: "go-app", version: 1, steps: 4},
+			wantErr: ErrInvalidRollbackCanary.Error(),
+		},
+		{
+			name: "error - active canary",
+			app: func() *ketchv1.App {
+				app := canaryApp(true, false)
+				app.Status.Revisions = []ketchv1.AppRevision{{Deployment: app.Spec.Deployments[0]}}
+				return app
+			}(),
+			options: appRollbackOptions{appName: "go-app", version: 2},
+			wantErr: "failed to roll back: app has an active canary or blue-green deployment",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app},
+			}
+			out := &bytes.Buffer{}
+			err := appRollback(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully rolled back!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.app.Name}, &gotApp)
+			require.Nil(t, err)
+			tt.checkFunc(t, gotApp)
+		})
+	}
+}
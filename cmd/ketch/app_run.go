@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/util/term"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+const (
+	appRunHelp = `
+Run a one-off command in the environment of an application.
+The command runs in a new pod with the image, environment variables, volumes and registry secret
+of the deployment receiving traffic, on the same nodes as the process.
+The output of the command is streamed and the pod is removed once the command exits.
+The pod is stopped after the timeout and removed with the app, in case ketch is killed before removing it.
+
+  ketch app run myapp -- rails db:migrate
+
+Run an interactive command with a terminal:
+  ketch app run myapp --tty -- /bin/sh
+`
+	podPollInterval = time.Second
+
+	defaultAppRunTimeout = time.Hour
+)
+
+type appRunFn func(context.Context, config, appRunOptions, io.Reader, io.Writer, runSessionFn) error
+
+// runSessionFn waits for a pod of "ketch app run" to start, streams it and returns an error if the command fails.
+type runSessionFn func(context.Context, config, *corev1.Pod, appRunOptions, io.Reader, io.Writer) error
+
+func newAppRunCmd(cfg config, out io.Writer, appRun appRunFn) *cobra.Command {
+	options := appRunOptions{}
+	cmd := &cobra.Command{
+		Use:   "run APPNAME -- COMMAND [ARGS...]",
+		Short: "Run a one-off command in the environment of an application.",
+		Long:  appRunHelp,
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.command = args[1:]
+			return appRun(cmd.Context(), cfg, options, cmd.InOrStdin(), out, runSession)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process whose environment and volumes are used, the first process by default")
+	cmd.Flags().BoolVarP(&options.tty, "tty", "t", false, "Attach stdin and a terminal to the command")
+	cmd.Flags().DurationVar(&options.timeout, "timeout", defaultAppRunTimeout, "Maximum duration of the command, its pod is stopped after it")
+	return cmd
+}
+
+type appRunOptions struct {
+	appName     string
+	processName string
+	command     []string
+	tty         bool
+	timeout     time.Duration
+}
+
+func appRun(ctx context.Context, cfg config, options appRunOptions, in io.Reader, out io.Writer, session runSessionFn) (err error) {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	pod, err := newRunPod(app, framework, options)
+	if err != nil {
+		return fmt.Errorf("failed to run command: %w", err)
+	}
+	pods := cfg.KubernetesClient().CoreV1().Pods(framework.Spec.NamespaceName)
+	pod, err = pods.Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to create pod: %w", err)
+	}
+	defer func() {
+		// the command can be interrupted, the pod is removed with a fresh context.
+		e := pods.Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+		if e != nil && !apierrors.IsNotFound(e) && err == nil {
+			err = fmt.Errorf("failed to delete pod: %w", e)
+		}
+	}()
+	return session(ctx, cfg, pod, options, in, out)
+}
+
+// newRunPod returns a pod running the command with the image of the deployment receiving traffic.
+// The pod is assigned to the same nodes as units of the process.
+func newRunPod(app ketchv1.App, framework ketchv1.Framework, options appRunOptions) (*corev1.Pod, error) {
+	deployment := currentDeployment(app)
+	if deployment == nil || len(deployment.Processes) == 0 {
+		return nil, ketchv1.ErrDeploymentNotFound
+	}
+	process := &deployment.Processes[0]
+	if len(options.processName) > 0 {
+		process = nil
+		for i := range deployment.Processes {
+			if deployment.Processes[i].Name == options.processName {
+				process = &deployment.Processes[i]
+			}
+		}
+		if process == nil {
+			return nil, ketchv1.ErrProcessNotFound
+		}
+	}
+	var env []corev1.EnvVar
+	for _, e := range append(append([]ketchv1.Env{}, process.Env...), app.Spec.Env...) {
+		envVar := corev1.EnvVar{Name: e.Name, Value: e.Value}
+		if e.IsSecret() {
+			envVar.ValueFrom = &corev1.EnvVarSource{SecretKeyRef: e.ValueFrom.SecretKeyRef}
+		}
+		env = append(env, envVar)
+	}
+	var envFrom []corev1.EnvFromSource
+	envFrom = append(envFrom, process.EnvFrom...)
+	envFrom = append(envFrom, app.Spec.EnvFrom...)
+	var volumes []corev1.Volume
+	for _, volume := range app.PodVolumes() {
+		for _, mount := range process.VolumeMounts {
+			if mount.Name == volume.Name {
+				volumes = append(volumes, volume)
+				break
+			}
+		}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-run-", app.Name),
+			Labels: map[string]string{
+				utils.KetchAppNameLabel:     app.Name,
+				utils.KetchIsolatedRunLabel: "true",
+			},
+			// the pod is removed with the app if ketch doesn't remove it.
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&app, ketchv1.GroupVersion.WithKind("App"))},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{
+				{
+					// the container's name is a prefix of the pod's name, see ketchContainerName.
+					Name:            fmt.Sprintf("%s-run", app.Name),
+					Image:           deployment.Image,
+					Command:         options.command,
+					Env:             env,
+					EnvFrom:         envFrom,
+					VolumeMounts:    process.VolumeMounts,
+					SecurityContext: process.SecurityContext,
+					Stdin:           options.tty,
+					StdinOnce:       options.tty,
+					TTY:             options.tty,
+				},
+			},
+			Volumes: volumes,
+		},
+	}
+	if options.timeout > 0 {
+		deadline := int64(math.Ceil(options.timeout.Seconds()))
+		pod.Spec.ActiveDeadlineSeconds = &deadline
+	}
+	if process.Resources != nil {
+		pod.Spec.Containers[0].Resources = *process.Resources
+	}
+	// topology spread constraints are left out, they describe how units of the process are spread.
+	if scheduling := process.Scheduling.WithDefaults(framework.Spec.DefaultScheduling); scheduling != nil {
+		pod.Spec.NodeSelector = scheduling.NodeSelector
+		pod.Spec.Affinity = scheduling.Affinity
+		pod.Spec.Tolerations = scheduling.Tolerations
+		pod.Spec.PriorityClassName = scheduling.PriorityClassName
+	}
+	if len(app.Spec.DockerRegistry.SecretName) > 0 {
+		pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: app.Spec.DockerRegistry.SecretName}}
+	}
+	return pod, nil
+}
+
+// currentDeployment returns the deployment receiving the most traffic, the latest one if several receive the same traffic.
+func currentDeployment(app ketchv1.App) *ketchv1.AppDeploymentSpec {
+	var current *ketchv1.AppDeploymentSpec
+	for i, deployment := range app.Spec.Deployments {
+		if current == nil || deployment.RoutingSettings.Weight > current.RoutingSettings.Weight ||
+			deployment.RoutingSettings.Weight == current.RoutingSettings.Weight && deployment.Version > current.Version {
+			current = &app.Spec.Deployments[i]
+		}
+	}
+	return current
+}
+
+func runSession(ctx context.Context, cfg config, pod *corev1.Pod, options appRunOptions, in io.Reader, out io.Writer) error {
+	cli := cfg.KubernetesClient()
+	containerName := pod.Spec.Containers[0].Name
+	if _, err := waitForPod(ctx, cli, pod, podStarted); err != nil {
+		return err
+	}
+	if options.tty {
+		req := cli.CoreV1().RESTClient().Post().
+			Resource("pods").
+			Namespace(pod.Namespace).
+			Name(pod.Name).
+			SubResource("attach").
+			VersionedParams(&corev1.PodAttachOptions{
+				Container: containerName,
+				Stdin:     true,
+				Stdout:    true,
+				TTY:       true,
+			}, scheme.ParameterCodec)
+		if err := streamTTY(cfg.RESTConfig(), req.URL(), in, out); err != nil {
+			return fmt.Errorf("failed to attach to pod: %w", err)
+		}
+	} else {
+		logs, err := cli.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: containerName, Follow: true}).Stream(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+		defer logs.Close()
+		if _, err := io.Copy(out, logs); err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+	}
+	finished, err := waitForPod(ctx, cli, pod, podFinished)
+	if err != nil {
+		return err
+	}
+	for _, status := range finished.Status.ContainerStatuses {
+		if status.Name == containerName && status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return fmt.Errorf("command exited with code %d", status.State.Terminated.ExitCode)
+		}
+	}
+	if finished.Status.Phase == corev1.PodFailed {
+		return fmt.Errorf("pod %s failed: %s", finished.Name, finished.Status.Message)
+	}
+	return nil
+}
+
+// streamTTY connects stdin and stdout of the terminal to a container with a SPDY session,
+// the container's terminal is resized along with the local one.
+func streamTTY(config *rest.Config, url *url.URL, in io.Reader, out io.Writer) error {
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", url)
+	if err != nil {
+		return err
+	}
+	tty := term.TTY{In: in, Out: out, Raw: true}
+	sizeQueue := tty.MonitorSize(tty.GetSize())
+	return tty.Safe(func() error {
+		return executor.Stream(remotecommand.StreamOptions{
+			Stdin:             tty.In,
+			Stdout:            tty.Out,
+			Tty:               true,
+			TerminalSizeQueue: sizeQueue,
+		})
+	})
+}
+
+type podConditionFn func(pod *corev1.Pod) (bool, error)
+
+func waitForPod(ctx context.Context, cli kubernetes.Interface, pod *corev1.Pod, condition podConditionFn) (*corev1.Pod, error) {
+	var current *corev1.Pod
+	err := wait.PollImmediateUntil(podPollInterval, func() (bool, error) {
+		var err error
+		current, err = cli.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to get pod: %w", err)
+		}
+		return condition(current)
+	}, ctx.Done())
+	return current, err
+}
+
+// podStarted returns true once the pod's containers have started and an error if they can't start.
+func podStarted(pod *corev1.Pod) (bool, error) {
+	if pod.Status.Phase != corev1.PodPending {
+		return true, nil
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		switch status.State.Waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError":
+			return false, fmt.Errorf("pod %s failed to start: %s %s", pod.Name, status.State.Waiting.Reason, status.State.Waiting.Message)
+		}
+	}
+	return false, nil
+}
+
+func podFinished(pod *corev1.Pod) (bool, error) {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+}
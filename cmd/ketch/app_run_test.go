@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/utils"
+	"github.com/shipa-corp/ketch/internal/utils/conversions"
+)
+
+func TestAppRun(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			DefaultScheduling: &ketchv1.SchedulingSpec{
+				NodeSelector: map[string]string{"cloud.google.com/gke-nodepool": "apps"},
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:      "gke",
+			Env:            []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}},
+			DockerRegistry: ketchv1.DockerRegistrySpec{SecretName: "registry"},
+			Volumes: []ketchv1.Volume{
+				{Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				{Name: "uploads", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version:         2,
+					Image:           "dashboard:v2",
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web"},
+						{
+							Name:         "worker",
+							VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+							Scheduling: &ketchv1.SchedulingSpec{
+								Tolerations:               []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+								TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.ScheduleAnyway}},
+							},
+						},
+					},
+				},
+				{
+					Version:   3,
+					Image:     "dashboard:v3",
+					Processes: []ketchv1.ProcessSpec{{Name: "web"}},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		options    appRunOptions
+		sessionErr error
+		wantPod    corev1.PodSpec
+		wantErr    string
+	}{
+		{
+			name:    "run a command with a process's volumes and scheduling",
+			options: appRunOptions{appName: "dashboard", processName: "worker", command: []string{"rake", "db:migrate"}, timeout: 90 * time.Second},
+			wantPod: corev1.PodSpec{
+				RestartPolicy:         corev1.RestartPolicyNever,
+				ActiveDeadlineSeconds: conversions.Int64Ptr(90),
+				Containers: []corev1.Container{
+					{
+						Name:         "dashboard-run",
+						Image:        "dashboard:v2",
+						Command:      []string{"rake", "db:migrate"},
+						Env:          []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+					},
+				},
+				Volumes:          []corev1.Volume{{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+				NodeSelector:     map[string]string{"cloud.google.com/gke-nodepool": "apps"},
+				Tolerations:      []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			},
+		},
+		{
+			name:    "run a shell with a terminal",
+			options: appRunOptions{appName: "dashboard", command: []string{"sh"}, tty: true},
+			wantPod: corev1.PodSpec{
+				RestartPolicy: corev1.RestartPolicyNever,
+				Containers: []corev1.Container{
+					{
+						Name:      "dashboard-run",
+						Image:     "dashboard:v2",
+						Command:   []string{"sh"},
+						Env:       []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}},
+						Stdin:     true,
+						StdinOnce: true,
+						TTY:       true,
+					},
+				},
+				ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+				NodeSelector:     map[string]string{"cloud.google.com/gke-nodepool": "apps"},
+			},
+		},
+		{
+			name:       "command fails",
+			options:    appRunOptions{appName: "dashboard", command: []string{"false"}},
+			sessionErr: errors.New("command exited with code 1"),
+			wantErr:    "command exited with code 1",
+		},
+		{
+			name:    "error - process not found",
+			options: appRunOptions{appName: "dashboard", processName: "cron", command: []string{"ls"}},
+			wantErr: "failed to run command: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{dashboard, gke},
+			}
+			called := false
+			session := func(ctx context.Context, cfg config, pod *corev1.Pod, options appRunOptions, in io.Reader, out io.Writer) error {
+				called = true
+				require.Equal(t, "ketch-gke", pod.Namespace)
+				require.Equal(t, "dashboard-run-", pod.GenerateName)
+				require.Equal(t, map[string]string{utils.KetchAppNameLabel: "dashboard", utils.KetchIsolatedRunLabel: "true"}, pod.Labels)
+				require.Len(t, pod.OwnerReferences, 1)
+				require.Equal(t, "App", pod.OwnerReferences[0].Kind)
+				require.Equal(t, "dashboard", pod.OwnerReferences[0].Name)
+				if tt.sessionErr != nil {
+					return tt.sessionErr
+				}
+				require.Equal(t, tt.wantPod, pod.Spec)
+				return nil
+			}
+			err := appRun(context.Background(), cfg, tt.options, &bytes.Buffer{}, &bytes.Buffer{}, session)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+			} else {
+				require.Nil(t, err)
+				require.True(t, called)
+			}
+			pods, err := cfg.KubernetesClient().CoreV1().Pods("ketch-gke").List(context.Background(), metav1.ListOptions{})
+			require.Nil(t, err)
+			require.Empty(t, pods.Items)
+		})
+	}
+}
+
+func Test_podStarted(t *testing.T) {
+	waiting := func(reason string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard-run-x7k2p"},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: "not found"}}},
+				},
+			},
+		}
+	}
+	started, err := podStarted(waiting("ContainerCreating"))
+	require.Nil(t, err)
+	require.False(t, started)
+
+	_, err = podStarted(waiting("ErrImagePull"))
+	require.NotNil(t, err)
+	require.Equal(t, "pod dashboard-run-x7k2p failed to start: ErrImagePull not found", err.Error())
+
+	started, err = podStarted(&corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}})
+	require.Nil(t, err)
+	require.True(t, started)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appSchedulingHelp = `
+Set how units of an application, or one of the processes of the application, are assigned to nodes.
+The specified settings replace the current scheduling of the processes,
+they are complemented with the default scheduling of the framework.
+
+Run units on a node pool and tolerate the taint of spot nodes:
+  ketch app scheduling <app name> --process worker --node-selector pool=batch --toleration spot=true:NoSchedule
+
+Spread units across zones and give them a priority class:
+  ketch app scheduling <app name> --process web --spread-by topology.kubernetes.io/zone --priority-class high
+
+Affinity and anti-affinity rules are read from a file with the same fields as "scheduling" of a process in application.yaml:
+  ketch app scheduling <app name> --file scheduling.yaml
+
+Remove the scheduling of the processes to use the framework's defaults only:
+  ketch app scheduling <app name> --reset
+`
+
+type appSchedulingFn func(context.Context, config, appSchedulingOptions, io.Writer) error
+
+func newAppSchedulingCmd(cfg config, out io.Writer, appScheduling appSchedulingFn) *cobra.Command {
+	options := appSchedulingOptions{}
+	cmd := &cobra.Command{
+		Use:   "scheduling APPNAME",
+		Short: "Set how units of an application, or one of the processes of the application, are assigned to nodes.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appSchedulingHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appScheduling(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name.")
+	cmd.Flags().IntVarP(&options.deploymentVersion, "version", "v", 0, "Deployment version.")
+	cmd.Flags().StringVarP(&options.filename, "file", "f", "", "File with scheduling settings, the flags below are added to them.")
+	cmd.Flags().StringToStringVar(&options.nodeSelector, "node-selector", nil, "Labels a node must have to run units, ex. pool=batch.")
+	cmd.Flags().StringArrayVar(&options.tolerations, "toleration", nil, "Taint units tolerate in the key[=value]:effect format, ex. spot=true:NoSchedule.")
+	cmd.Flags().StringArrayVar(&options.spreadBy, "spread-by", nil, "Topology key of nodes to spread units across, ex. topology.kubernetes.io/zone.")
+	cmd.Flags().Int32Var(&options.maxSkew, "max-skew", 1, "Maximum difference between numbers of units in any two topology domains.")
+	cmd.Flags().StringVar(&options.priorityClass, "priority-class", "", "Name of a PriorityClass of units.")
+	cmd.Flags().BoolVar(&options.reset, "reset", false, "Remove the scheduling of the processes to use the framework's defaults.")
+	return cmd
+}
+
+type appSchedulingOptions struct {
+	appName           string
+	processName       string
+	deploymentVersion int
+	filename          string
+	nodeSelector      map[string]string
+	tolerations       []string
+	spreadBy          []string
+	maxSkew           int32
+	priorityClass     string
+	reset             bool
+}
+
+// scheduling returns scheduling settings built from the options or nil if the scheduling should be reset.
+func (o appSchedulingOptions) scheduling() (*ketchv1.SchedulingSpec, error) {
+	if o.reset {
+		return nil, nil
+	}
+	scheduling := ketchv1.SchedulingSpec{}
+	if len(o.filename) > 0 {
+		b, err := os.ReadFile(o.filename)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(b, &scheduling); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", o.filename, err)
+		}
+	}
+	if len(o.nodeSelector) > 0 && scheduling.NodeSelector == nil {
+		scheduling.NodeSelector = make(map[string]string, len(o.nodeSelector))
+	}
+	for key, value := range o.nodeSelector {
+		scheduling.NodeSelector[key] = value
+	}
+	for _, t := range o.tolerations {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return nil, err
+		}
+		scheduling.Tolerations = append(scheduling.Tolerations, *toleration)
+	}
+	for _, topologyKey := range o.spreadBy {
+		scheduling.TopologySpreadConstraints = append(scheduling.TopologySpreadConstraints, v1.TopologySpreadConstraint{
+			MaxSkew:           o.maxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: v1.ScheduleAnyway,
+		})
+	}
+	if len(o.priorityClass) > 0 {
+		scheduling.PriorityClassName = o.priorityClass
+	}
+	if scheduling.NodeSelector == nil && scheduling.Affinity == nil && len(scheduling.Tolerations) == 0 &&
+		len(scheduling.TopologySpreadConstraints) == 0 && len(scheduling.PriorityClassName) == 0 {
+		return nil, ErrNoScheduling
+	}
+	return &scheduling, nil
+}
+
+// parseToleration returns a toleration of a taint in the key[=value]:effect format used by "kubectl taint".
+// A toleration without a value tolerates a taint with the key and any value.
+func parseToleration(value string) (*v1.Toleration, error) {
+	i := strings.LastIndex(value, ":")
+	if i < 0 {
+		return nil, fmt.Errorf("invalid toleration %q, the format is key[=value]:effect", value)
+	}
+	toleration := v1.Toleration{
+		Key:      value[:i],
+		Operator: v1.TolerationOpExists,
+		Effect:   v1.TaintEffect(value[i+1:]),
+	}
+	if parts := strings.SplitN(toleration.Key, "=", 2); len(parts) == 2 {
+		toleration.Key = parts[0]
+		toleration.Operator = v1.TolerationOpEqual
+		toleration.Value = parts[1]
+	}
+	if len(toleration.Key) == 0 {
+		return nil, fmt.Errorf("invalid toleration %q, the format is key[=value]:effect", value)
+	}
+	switch toleration.Effect {
+	case v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+	default:
+		return nil, fmt.Errorf("invalid toleration %q, the effect should be one of NoSchedule, PreferNoSchedule and NoExecute", value)
+	}
+	return &toleration, nil
+}
+
+func appScheduling(ctx context.Context, cfg config, options appSchedulingOptions, out io.Writer) error {
+	scheduling, err := options.scheduling()
+	if err != nil {
+		return err
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	s := ketchv1.NewSelector(options.deploymentVersion, options.processName)
+	if err := app.SetScheduling(s, scheduling); err != nil {
+		return fmt.Errorf("failed to set scheduling: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully updated scheduling!")
+	return nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestAppScheduling(t *testing.T) {
+	webScheduling := &ketchv1.SchedulingSpec{PriorityClassName: "high"}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Scheduling: webScheduling},
+						{Name: "worker"},
+					},
+				},
+			},
+		},
+	}
+	affinityFile := filepath.Join(t.TempDir(), "scheduling.yaml")
+	err := os.WriteFile(affinityFile, []byte(`
+nodeSelector:
+  pool: apps
+affinity:
+  podAntiAffinity:
+    requiredDuringSchedulingIgnoredDuringExecution:
+      - topologyKey: kubernetes.io/hostname
+        labelSelector:
+          matchLabels:
+            theketch.io/app-name: go-app
+`), 0644)
+	require.Nil(t, err)
+	tests := []struct {
+		name           string
+		options        appSchedulingOptions
+		wantScheduling map[string]*ketchv1.SchedulingSpec
+		wantErr        string
+	}{
+		{
+			name: "set scheduling of a process",
+			options: appSchedulingOptions{
+				appName:       "go-app",
+				processName:   "worker",
+				nodeSelector:  map[string]string{"pool": "batch"},
+				tolerations:   []string{"spot:NoSchedule", "batch=true:NoExecute"},
+				spreadBy:      []string{"topology.kubernetes.io/zone"},
+				maxSkew:       2,
+				priorityClass: "low",
+			},
+			wantScheduling: map[string]*ketchv1.SchedulingSpec{
+				"web": webScheduling,
+				"worker": {
+					NodeSelector: map[string]string{"pool": "batch"},
+					Tolerations: []v1.Toleration{
+						{Key: "spot", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule},
+						{Key: "batch", Operator: v1.TolerationOpEqual, Value: "true", Effect: v1.TaintEffectNoExecute},
+					},
+					TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+						{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway},
+					},
+					PriorityClassName: "low",
+				},
+			},
+		},
+		{
+			name:    "set scheduling of all processes from a file and flags",
+			options: appSchedulingOptions{appName: "go-app", filename: affinityFile, nodeSelector: map[string]string{"disktype": "ssd"}},
+			wantScheduling: map[string]*ketchv1.SchedulingSpec{
+				"web": {
+					NodeSelector: map[string]string{"pool": "apps", "disktype": "ssd"},
+					Affinity: &v1.Affinity{
+						PodAntiAffinity: &v1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+								{
+									TopologyKey:   "kubernetes.io/hostname",
+									LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"theketch.io/app-name": "go-app"}},
+								},
+							},
+						},
+					},
+				},
+				"worker": {
+					NodeSelector: map[string]string{"pool": "apps", "disktype": "ssd"},
+					Affinity: &v1.Affinity{
+						PodAntiAffinity: &v1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []v1.PodAffinityTerm{
+								{
+									TopologyKey:   "kubernetes.io/hostname",
+									LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"theketch.io/app-name": "go-app"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "reset scheduling of a process",
+			options: appSchedulingOptions{appName: "go-app", processName: "web", reset: true},
+			wantScheduling: map[string]*ketchv1.SchedulingSpec{
+				"web":    nil,
+				"worker": nil,
+			},
+		},
+		{
+			name:    "error - no scheduling",
+			options: appSchedulingOptions{appName: "go-app", processName: "web"},
+			wantErr: ErrNoScheduling.Error(),
+		},
+		{
+			name:    "error - toleration without an effect",
+			options: appSchedulingOptions{appName: "go-app", tolerations: []string{"spot=true"}},
+			wantErr: `invalid toleration "spot=true", the format is key[=value]:effect`,
+		},
+		{
+			name:    "error - invalid effect",
+			options: appSchedulingOptions{appName: "go-app", tolerations: []string{"spot:NoRun"}},
+			wantErr: `invalid toleration "spot:NoRun", the effect should be one of NoSchedule, PreferNoSchedule and NoExecute`,
+		},
+		{
+			name:    "error - no such process",
+			options: appSchedulingOptions{appName: "go-app", processName: "api", priorityClass: "high"},
+			wantErr: "failed to set scheduling: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{app.DeepCopy()},
+			}
+			out := &bytes.Buffer{}
+			err := appScheduling(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully updated scheduling!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			gotScheduling := map[string]*ketchv1.SchedulingSpec{}
+			for _, process := range gotApp.Spec.Deployments[0].Processes {
+				gotScheduling[process.Name] = process.Scheduling
+			}
+			require.Equal(t, tt.wantScheduling, gotScheduling)
+		})
+	}
+}
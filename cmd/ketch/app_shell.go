@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	appShellHelp = `
+Open a shell in a running unit of an application.
+The first running unit is used unless --unit is set, units can be narrowed down with --process and --version.
+bash is started if the image has it, sh otherwise.
+`
+	shellCommand = "command -v bash > /dev/null && exec bash || exec sh"
+)
+
+func newAppShellCmd(cfg config, out io.Writer, appExec appExecFn) *cobra.Command {
+	options := appExecOptions{}
+	cmd := &cobra.Command{
+		Use:   "shell APPNAME",
+		Short: "Open a shell in a running unit of an application.",
+		Long:  appShellHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.command = []string{"sh", "-c", shellCommand}
+			options.stdin = true
+			options.tty = true
+			return appExec(cmd.Context(), cfg, options, cmd.InOrStdin(), out, execInContainer)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	addUnitFlags(cmd, &options)
+	return cmd
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appSwitchHelp = `
+Switch traffic of an application with an active blue-green deployment.
+All traffic is moved to the idle deployment at once. The deployment that received traffic before the switch
+becomes idle and is kept for the time set by --keep-previous of "ketch app deploy", so switching again moves traffic back to it.
+`
+
+type appSwitchFn func(context.Context, config, appSwitchOptions, io.Writer) error
+
+func newAppSwitchCmd(cfg config, out io.Writer, appSwitch appSwitchFn) *cobra.Command {
+	options := appSwitchOptions{}
+	cmd := &cobra.Command{
+		Use:   "switch APPNAME",
+		Short: "Move all traffic to the idle deployment of a blue-green deployment.",
+		Args:  cobra.ExactArgs(1),
+		Long:  appSwitchHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appSwitch(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type appSwitchOptions struct {
+	appName string
+}
+
+func appSwitch(ctx context.Context, cfg config, options appSwitchOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.SwitchBlueGreen(metav1.NewTime(time.Now())); err != nil {
+		return fmt.Errorf("failed to switch app: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully switched!")
+	return nil
+}
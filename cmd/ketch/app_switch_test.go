@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestAppSwitch(t *testing.T) {
+	blueGreenApp := func(active bool) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "go-app",
+			},
+			Spec: ketchv1.AppSpec{
+				Strategy: ketchv1.StrategySpec{
+					Type:      ketchv1.BlueGreenStrategy,
+					BlueGreen: ketchv1.BlueGreenSpec{Active: active},
+				},
+				Deployments: []ketchv1.AppDeploymentSpec{
+					{Version: 2, RoutingSettings: ketchv1.RoutingSettings{Weight: 100}},
+					{Version: 3, RoutingSettings: ketchv1.RoutingSettings{Weight: 0}},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		app         *ketchv1.App
+		wantWeights []uint8
+		wantErr     string
+	}{
+		{
+			name:        "switch traffic to the idle deployment",
+			app:         blueGreenApp(true),
+			wantWeights: []uint8{0, 100},
+		},
+		{
+			name:    "error - blue-green deployment is not active",
+			app:     blueGreenApp(false),
+			wantErr: "failed to switch app: blue-green deployment is not active",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app},
+			}
+			out := &bytes.Buffer{}
+			err := appSwitch(context.Background(), cfg, appSwitchOptions{appName: tt.app.Name}, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully switched!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.app.Name}, &gotApp)
+			require.Nil(t, err)
+			require.NotNil(t, gotApp.Spec.Strategy.BlueGreen.SwitchedAt)
+			var weights []uint8
+			for _, deployment := range gotApp.Spec.Deployments {
+				weights = append(weights, deployment.RoutingSettings.Weight)
+			}
+			require.Equal(t, tt.wantWeights, weights)
+		})
+	}
+}
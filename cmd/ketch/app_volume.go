@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const appVolumeHelp = `
+Manage volumes of an application.
+`
+
+func newAppVolumeCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "volume",
+		Short: "Manage volumes of an application",
+		Long:  appVolumeHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppVolumeAttachCmd(cfg, out, appVolumeAttach))
+	cmd.AddCommand(newAppVolumeDetachCmd(cfg, out, appVolumeDetach))
+	return cmd
+}
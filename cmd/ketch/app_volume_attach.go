@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appVolumeAttachHelp = `
+Attach a volume to an application and mount it to containers of all processes, or one of the processes of the application.
+ConfigMaps, Secrets and PersistentVolumeClaims must be created in the namespace of the app's framework.
+
+Mount an existing PersistentVolumeClaim:
+  ketch app volume attach <app name> uploads --pvc uploads-claim --mount-path /uploads --process web
+
+Create a PersistentVolumeClaim with a storage class and size, the claim is kept when the volume is detached:
+  ketch app volume attach <app name> uploads --storage-class standard --size 10Gi --mount-path /uploads
+
+Mount keys of a ConfigMap or a Secret as files:
+  ketch app volume attach <app name> settings --configmap settings --mount-path /etc/settings --read-only
+  ketch app volume attach <app name> certs --secret certs --mount-path /etc/certs --read-only
+
+Mount a temporary directory that shares a pod's lifetime:
+  ketch app volume attach <app name> cache --empty-dir --mount-path /cache
+`
+
+type appVolumeAttachFn func(context.Context, config, appVolumeAttachOptions, io.Writer) error
+
+func newAppVolumeAttachCmd(cfg config, out io.Writer, appVolumeAttach appVolumeAttachFn) *cobra.Command {
+	options := appVolumeAttachOptions{}
+	cmd := &cobra.Command{
+		Use:   "attach APPNAME VOLUME",
+		Short: "Attach a volume to an application.",
+		Args:  cobra.ExactArgs(2),
+		Long:  appVolumeAttachHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.volumeName = args[1]
+			return appVolumeAttach(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name. If not set, the volume is mounted to all processes.")
+	cmd.Flags().StringVar(&options.mountPath, "mount-path", "", "Path within the container at which the volume is mounted.")
+	cmd.Flags().StringVar(&options.subPath, "sub-path", "", "Path within the volume from which the container's volume is mounted.")
+	cmd.Flags().BoolVar(&options.readOnly, "read-only", false, "Mount the volume read-only.")
+	cmd.Flags().StringVar(&options.pvc, "pvc", "", "Name of an existing PersistentVolumeClaim.")
+	cmd.Flags().StringVar(&options.size, "size", "", "Size of a PersistentVolumeClaim to create, ex. 10Gi.")
+	cmd.Flags().StringVar(&options.storageClass, "storage-class", "", "StorageClass of a PersistentVolumeClaim to create. If not set, the default StorageClass is used.")
+	cmd.Flags().StringVar(&options.configMap, "configmap", "", "Name of a ConfigMap.")
+	cmd.Flags().StringVar(&options.secret, "secret", "", "Name of a Secret.")
+	cmd.Flags().BoolVar(&options.emptyDir, "empty-dir", false, "Mount a temporary directory.")
+	cmd.MarkFlagRequired("mount-path")
+	return cmd
+}
+
+type appVolumeAttachOptions struct {
+	appName      string
+	volumeName   string
+	processName  string
+	mountPath    string
+	subPath      string
+	readOnly     bool
+	pvc          string
+	size         string
+	storageClass string
+	configMap    string
+	secret       string
+	emptyDir     bool
+}
+
+func (o appVolumeAttachOptions) volume() (*ketchv1.Volume, error) {
+	volume := ketchv1.Volume{Name: o.volumeName}
+	if len(o.pvc) > 0 {
+		volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: o.pvc, ReadOnly: o.readOnly}
+	}
+	if len(o.size) > 0 {
+		size, err := resource.ParseQuantity(o.size)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse size: %w", err)
+		}
+		volume.Claim = &ketchv1.VolumeClaimSpec{Size: size}
+		if len(o.storageClass) > 0 {
+			storageClass := o.storageClass
+			volume.Claim.StorageClassName = &storageClass
+		}
+	}
+	if len(o.configMap) > 0 {
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: o.configMap}}
+	}
+	if len(o.secret) > 0 {
+		volume.Secret = &corev1.SecretVolumeSource{SecretName: o.secret}
+	}
+	if o.emptyDir {
+		volume.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	if err := volume.Validate(); err != nil {
+		return nil, ErrInvalidVolume
+	}
+	return &volume, nil
+}
+
+func appVolumeAttach(ctx context.Context, cfg config, options appVolumeAttachOptions, out io.Writer) error {
+	volume, err := options.volume()
+	if err != nil {
+		return err
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	mount := corev1.VolumeMount{
+		MountPath: options.mountPath,
+		SubPath:   options.subPath,
+		ReadOnly:  options.readOnly,
+	}
+	if err := app.AttachVolume(*volume, mount, options.processName); err != nil {
+		return fmt.Errorf("failed to attach volume: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully attached!")
+	return nil
+}
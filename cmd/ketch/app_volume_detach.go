@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const appVolumeDetachHelp = `
+Unmount a volume from containers of all processes, or one of the processes of an application.
+The volume is removed from the application once no process mounts it.
+PersistentVolumeClaims created by ketch are kept and must be deleted manually.
+
+  ketch app volume detach <app name> uploads --process web
+`
+
+type appVolumeDetachFn func(context.Context, config, appVolumeDetachOptions, io.Writer) error
+
+func newAppVolumeDetachCmd(cfg config, out io.Writer, appVolumeDetach appVolumeDetachFn) *cobra.Command {
+	options := appVolumeDetachOptions{}
+	cmd := &cobra.Command{
+		Use:   "detach APPNAME VOLUME",
+		Short: "Detach a volume from an application.",
+		Args:  cobra.ExactArgs(2),
+		Long:  appVolumeDetachHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.volumeName = args[1]
+			return appVolumeDetach(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name. If not set, the volume is unmounted from all processes.")
+	return cmd
+}
+
+type appVolumeDetachOptions struct {
+	appName     string
+	volumeName  string
+	processName string
+}
+
+func appVolumeDetach(ctx context.Context, cfg config, options appVolumeDetachOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.DetachVolume(options.volumeName, options.processName); err != nil {
+		return fmt.Errorf("failed to detach volume: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully detached!")
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func volumeApp() *ketchv1.App {
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+		},
+		Spec: ketchv1.AppSpec{
+			Volumes: []ketchv1.Volume{
+				{Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Version: 2,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}},
+						{Name: "worker", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestAppVolumeAttach(t *testing.T) {
+	storageClass := "standard"
+	tests := []struct {
+		name       string
+		options    appVolumeAttachOptions
+		wantVolume ketchv1.Volume
+		wantMounts map[string][]corev1.VolumeMount
+		wantErr    string
+	}{
+		{
+			name:       "create a claim and mount it to a process",
+			options:    appVolumeAttachOptions{appName: "go-app", volumeName: "uploads", processName: "web", mountPath: "/uploads", size: "10Gi", storageClass: "standard"},
+			wantVolume: ketchv1.Volume{Name: "uploads", Claim: &ketchv1.VolumeClaimSpec{Size: resource.MustParse("10Gi"), StorageClassName: &storageClass}},
+			wantMounts: map[string][]corev1.VolumeMount{
+				"web":    {{Name: "cache", MountPath: "/cache"}, {Name: "uploads", MountPath: "/uploads"}},
+				"worker": {{Name: "cache", MountPath: "/cache"}},
+			},
+		},
+		{
+			name:       "mount a secret to all processes",
+			options:    appVolumeAttachOptions{appName: "go-app", volumeName: "certs", mountPath: "/etc/certs", secret: "certs", readOnly: true},
+			wantVolume: ketchv1.Volume{Name: "certs", Secret: &corev1.SecretVolumeSource{SecretName: "certs"}},
+			wantMounts: map[string][]corev1.VolumeMount{
+				"web":    {{Name: "cache", MountPath: "/cache"}, {Name: "certs", MountPath: "/etc/certs", ReadOnly: true}},
+				"worker": {{Name: "cache", MountPath: "/cache"}, {Name: "certs", MountPath: "/etc/certs", ReadOnly: true}},
+			},
+		},
+		{
+			name:       "replace a volume",
+			options:    appVolumeAttachOptions{appName: "go-app", volumeName: "cache", processName: "worker", mountPath: "/tmp/cache", pvc: "cache-claim"},
+			wantVolume: ketchv1.Volume{Name: "cache", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "cache-claim"}},
+			wantMounts: map[string][]corev1.VolumeMount{
+				"web":    {{Name: "cache", MountPath: "/cache"}},
+				"worker": {{Name: "cache", MountPath: "/tmp/cache"}},
+			},
+		},
+		{
+			name:    "error - no source",
+			options: appVolumeAttachOptions{appName: "go-app", volumeName: "uploads", mountPath: "/uploads"},
+			wantErr: ErrInvalidVolume.Error(),
+		},
+		{
+			name:    "error - several sources",
+			options: appVolumeAttachOptions{appName: "go-app", volumeName: "uploads", mountPath: "/uploads", pvc: "uploads", emptyDir: true},
+			wantErr: ErrInvalidVolume.Error(),
+		},
+		{
+			name:    "error - process not found",
+			options: appVolumeAttachOptions{appName: "go-app", volumeName: "uploads", processName: "scheduler", mountPath: "/uploads", emptyDir: true},
+			wantErr: "failed to attach volume: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{volumeApp()},
+			}
+			out := &bytes.Buffer{}
+			err := appVolumeAttach(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully attached!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app"}, &gotApp)
+			require.Nil(t, err)
+			var gotVolume *ketchv1.Volume
+			for i, volume := range gotApp.Spec.Volumes {
+				if volume.Name == tt.wantVolume.Name {
+					gotVolume = &gotApp.Spec.Volumes[i]
+				}
+			}
+			require.NotNil(t, gotVolume)
+			if tt.wantVolume.Claim != nil {
+				require.NotNil(t, gotVolume.Claim)
+				require.Equal(t, tt.wantVolume.Claim.Size.String(), gotVolume.Claim.Size.String())
+				require.Equal(t, tt.wantVolume.Claim.StorageClassName, gotVolume.Claim.StorageClassName)
+				gotVolume.Claim, tt.wantVolume.Claim = nil, nil
+			}
+			require.Equal(t, tt.wantVolume, *gotVolume)
+			for _, process := range gotApp.Spec.Deployments[0].Processes {
+				require.Equal(t, tt.wantMounts[process.Name], process.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestAppVolumeDetach(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     appVolumeDetachOptions
+		wantVolumes int
+		wantMounts  map[string][]corev1.VolumeMount
+		wantErr     string
+	}{
+		{
+			name:        "unmount a volume from a process",
+			options:     appVolumeDetachOptions{appName: "go-app", volumeName: "cache", processName: "worker"},
+			wantVolumes: 1,
+			wantMounts: map[string][]corev1.VolumeMount{
+				"web": {{Name: "cache", MountPath: "/cache"}},
+			},
+		},
+		{
+			name:        "remove a volume",
+			options:     appVolumeDetachOptions{appName: "go-app", volumeName: "cache"},
+			wantVolumes: 0,
+			wantMounts:  map[string][]corev1.VolumeMount{},
+		},
+		{
+			name:    "error - volume not found",
+			options: appVolumeDetachOptions{appName: "go-app", volumeName: "uploads"},
+			wantErr: "failed to detach volume: volume not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{volumeApp()},
+			}
+			out := &bytes.Buffer{}
+			err := appVolumeDetach(context.Background(), cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully detached!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app"}, &gotApp)
+			require.Nil(t, err)
+			require.Len(t, gotApp.Spec.Volumes, tt.wantVolumes)
+			for _, process := range gotApp.Spec.Deployments[0].Processes {
+				require.Equal(t, tt.wantMounts[process.Name], process.VolumeMounts)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -80,6 +81,17 @@ func (cfg *Configuration) KubernetesClient() kubernetes.Interface {
 	return clientset
 }
 
+// RESTConfig returns a config to connect to the kubernetes API server. It's used to stream to and from containers.
+func (cfg *Configuration) RESTConfig() *rest.Config {
+	configFlags := genericclioptions.NewConfigFlags(true)
+	factory := cmdutil.NewFactory(configFlags)
+	kubeCfg, err := factory.ToRESTConfig()
+	if err != nil {
+		log.Fatalf("failed to create kubernetes client: %v", err)
+	}
+	return kubeCfg
+}
+
 // Client returns initialized templates.Client to perform CRUD operations on templates.
 func (cfg *Configuration) Storage() templates.Client {
 	if cfg.storage != nil {
@@ -23,5 +23,7 @@ func newEnvCmd(cfg config, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newEnvSetCmd(cfg, out))
 	cmd.AddCommand(newEnvGetCmd(cfg, out))
 	cmd.AddCommand(newEnvUnsetCmd(cfg, out))
+	cmd.AddCommand(newEnvFromConfigMapCmd(cfg, out))
+	cmd.AddCommand(newEnvFromSecretCmd(cfg, out))
 	return cmd
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/deploy"
+)
+
+const envFromConfigMapHelp = `
+Set all keys of a ConfigMap as environment variables of an application, or one of the processes of the application.
+The ConfigMap must be created in the namespace of the app's framework.
+Pods of the application are restarted when the ConfigMap changes.
+
+  ketch env from-configmap <configmap name> -a <app name> [-p process] [--prefix CONFIG_]
+
+Stop using the ConfigMap:
+  ketch env from-configmap <configmap name> -a <app name> [-p process] --remove
+`
+
+func newEnvFromConfigMapCmd(cfg config, out io.Writer) *cobra.Command {
+	options := envFromOptions{}
+	cmd := &cobra.Command{
+		Use:   "from-configmap CONFIGMAP",
+		Args:  cobra.ExactArgs(1),
+		Short: "Set all keys of a ConfigMap as environment variables of an application.",
+		Long:  envFromConfigMapHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := corev1.EnvFromSource{
+				Prefix:       options.prefix,
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: args[0]}},
+			}
+			return envFrom(cmd.Context(), cfg, options, source, out)
+		},
+	}
+	addEnvFromFlags(cmd, cfg, &options)
+	return cmd
+}
+
+type envFromOptions struct {
+	appName     string
+	processName string
+	prefix      string
+	remove      bool
+}
+
+func addEnvFromFlags(cmd *cobra.Command, cfg config, options *envFromOptions) {
+	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name. If not set, all processes of the app get the environment variables.")
+	cmd.Flags().StringVar(&options.prefix, "prefix", "", "A prefix added to names of the environment variables.")
+	cmd.Flags().BoolVar(&options.remove, "remove", false, "Stop getting environment variables from the source.")
+	cmd.MarkFlagRequired(deploy.FlagApp)
+	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return autoCompleteAppNames(cfg, toComplete)
+	})
+}
+
+func envFrom(ctx context.Context, cfg config, options envFromOptions, source corev1.EnvFromSource, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	update := app.AddEnvFrom
+	if options.remove {
+		update = app.RemoveEnvFrom
+	}
+	if err := update(options.processName, source); err != nil {
+		return fmt.Errorf("failed to update environment variables: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update the app: %w", err)
+	}
+	if options.remove {
+		fmt.Fprintln(out, "Successfully removed!")
+		return nil
+	}
+	fmt.Fprintln(out, "Successfully added!")
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const envFromSecretHelp = `
+Set all keys of a Secret as environment variables of an application, or one of the processes of the application.
+The Secret must be created in the namespace of the app's framework.
+Pods of the application are restarted when the Secret changes.
+
+  ketch env from-secret <secret name> -a <app name> [-p process] [--prefix DB_]
+
+Stop using the Secret:
+  ketch env from-secret <secret name> -a <app name> [-p process] --remove
+`
+
+func newEnvFromSecretCmd(cfg config, out io.Writer) *cobra.Command {
+	options := envFromOptions{}
+	cmd := &cobra.Command{
+		Use:   "from-secret SECRET",
+		Args:  cobra.ExactArgs(1),
+		Short: "Set all keys of a Secret as environment variables of an application.",
+		Long:  envFromSecretHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source := corev1.EnvFromSource{
+				Prefix:    options.prefix,
+				SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: args[0]}},
+			}
+			return envFrom(cmd.Context(), cfg, options, source, out)
+		},
+	}
+	addEnvFromFlags(cmd, cfg, &options)
+	return cmd
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestEnvFrom(t *testing.T) {
+	configMapSource := func(name, prefix string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{
+			Prefix:       prefix,
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+		}
+	}
+	secretSource := func(name string) corev1.EnvFromSource {
+		return corev1.EnvFromSource{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+		}
+	}
+	appWithProcess := func(appEnvFrom []corev1.EnvFromSource, processEnvFrom []corev1.EnvFromSource) *ketchv1.App {
+		app := envApp()
+		app.Spec.EnvFrom = appEnvFrom
+		app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+			{
+				Version: 1,
+				Processes: []ketchv1.ProcessSpec{
+					{Name: "web", EnvFrom: processEnvFrom},
+					{Name: "worker"},
+				},
+			},
+		}
+		return app
+	}
+	tests := []struct {
+		name               string
+		app                *ketchv1.App
+		options            envFromOptions
+		source             corev1.EnvFromSource
+		wantAppEnvFrom     []corev1.EnvFromSource
+		wantProcessEnvFrom []corev1.EnvFromSource
+		wantOut            string
+		wantErr            string
+	}{
+		{
+			name:           "add a configmap to the app",
+			app:            appWithProcess(nil, nil),
+			options:        envFromOptions{appName: "go-app"},
+			source:         configMapSource("settings", ""),
+			wantAppEnvFrom: []corev1.EnvFromSource{configMapSource("settings", "")},
+			wantOut:        "Successfully added!\n",
+		},
+		{
+			name:               "add a secret to a process",
+			app:                appWithProcess(nil, nil),
+			options:            envFromOptions{appName: "go-app", processName: "web"},
+			source:             secretSource("credentials"),
+			wantProcessEnvFrom: []corev1.EnvFromSource{secretSource("credentials")},
+			wantOut:            "Successfully added!\n",
+		},
+		{
+			name:           "update the prefix of a configmap",
+			app:            appWithProcess([]corev1.EnvFromSource{configMapSource("settings", "")}, nil),
+			options:        envFromOptions{appName: "go-app", prefix: "CONFIG_"},
+			source:         configMapSource("settings", "CONFIG_"),
+			wantAppEnvFrom: []corev1.EnvFromSource{configMapSource("settings", "CONFIG_")},
+			wantOut:        "Successfully added!\n",
+		},
+		{
+			name:               "remove a secret from a process",
+			app:                appWithProcess(nil, []corev1.EnvFromSource{secretSource("credentials"), configMapSource("settings", "")}),
+			options:            envFromOptions{appName: "go-app", processName: "web", remove: true},
+			source:             secretSource("credentials"),
+			wantProcessEnvFrom: []corev1.EnvFromSource{configMapSource("settings", "")},
+			wantOut:            "Successfully removed!\n",
+		},
+		{
+			name:    "error - process not found",
+			app:     appWithProcess(nil, nil),
+			options: envFromOptions{appName: "go-app", processName: "scheduler"},
+			source:  configMapSource("settings", ""),
+			wantErr: "failed to update environment variables: process not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app},
+			}
+			out := &bytes.Buffer{}
+			err := envFrom(context.Background(), cfg, tt.options, tt.source, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.app.Name}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantAppEnvFrom, gotApp.Spec.EnvFrom)
+			require.Equal(t, tt.wantProcessEnvFrom, gotApp.Spec.Deployments[0].Processes[0].EnvFrom)
+			require.Nil(t, gotApp.Spec.Deployments[0].Processes[1].EnvFrom)
+		})
+	}
+}
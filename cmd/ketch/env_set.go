@@ -7,6 +7,9 @@ import (
 	"log"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
@@ -16,6 +19,12 @@ import (
 
 const envSetHelp = `
 Set environment variables for an application.
+
+ketch env set [-a/--app appname] NAME1=VALUE1 NAME2=VALUE2 ...
+
+Private values such as passwords can be stored in a Secret in the framework's namespace,
+the application keeps only a reference to the Secret and the values are masked when displayed:
+  ketch env set -a <app name> --secret DATABASE_PASSWORD=secret
 `
 
 func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
@@ -32,6 +41,7 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
+	cmd.Flags().BoolVar(&options.secret, "secret", false, "Store the values in a Secret in the framework's namespace.")
 	cmd.MarkFlagRequired(deploy.FlagApp)
 	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return autoCompleteAppNames(cfg, toComplete)
@@ -42,6 +52,7 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 type envSetOptions struct {
 	appName string
 	envs    []string
+	secret  bool
 }
 
 func envSet(ctx context.Context, cfg config, options envSetOptions, out io.Writer) error {
@@ -53,9 +64,59 @@ func envSet(ctx context.Context, cfg config, options envSetOptions, out io.Write
 	if err = cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
 		log.Fatalf("failed to get the app: %v", err)
 	}
-	app.SetEnvs(envs)
+	if options.secret {
+		if err := setEnvSecret(ctx, cfg, app, envs); err != nil {
+			return err
+		}
+		names := make([]string, 0, len(envs))
+		for _, env := range envs {
+			names = append(names, env.Name)
+		}
+		app.SetSecretEnvs(names)
+	} else {
+		app.SetEnvs(envs)
+	}
 	if err := cfg.Client().Update(ctx, &app); err != nil {
 		return fmt.Errorf("failed to update the app: %w", err)
 	}
 	return nil
 }
+
+// setEnvSecret stores the values of the environment variables in the app's env secret.
+// The secret is owned by the app, so it is removed together with the app.
+func setEnvSecret(ctx context.Context, cfg config, app ketchv1.App, envs []ketchv1.Env) error {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	secret := corev1.Secret{}
+	err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.EnvSecretName(), Namespace: framework.Spec.NamespaceName}, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+	create := apierrors.IsNotFound(err)
+	if create {
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            app.EnvSecretName(),
+				Namespace:       framework.Spec.NamespaceName,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&app, ketchv1.GroupVersion.WithKind("App"))},
+			},
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	for _, env := range envs {
+		secret.Data[env.Name] = []byte(env.Value)
+	}
+	if create {
+		err = cfg.Client().Create(ctx, &secret)
+	} else {
+		err = cfg.Client().Update(ctx, &secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func envApp() *ketchv1.App {
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "go-app",
+			UID:  "go-app-uid",
+		},
+		Spec: ketchv1.AppSpec{
+			Framework: "myframework",
+			Env: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "debug"},
+			},
+		},
+	}
+}
+
+func envFramework() *ketchv1.Framework {
+	return &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "myframework",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-myframework",
+		},
+	}
+}
+
+func TestEnvSet(t *testing.T) {
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		options        envSetOptions
+		wantEnvs       []ketchv1.Env
+		wantSecretData map[string][]byte
+	}{
+		{
+			name:    "plain values",
+			objects: []runtime.Object{envApp(), envFramework()},
+			options: envSetOptions{appName: "go-app", envs: []string{"LOG_LEVEL=info", "FOO=bar"}},
+			wantEnvs: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "info"},
+				{Name: "FOO", Value: "bar"},
+			},
+		},
+		{
+			name:    "secret values",
+			objects: []runtime.Object{envApp(), envFramework()},
+			options: envSetOptions{appName: "go-app", envs: []string{"DB_PASSWORD=secret"}, secret: true},
+			wantEnvs: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{
+					Name: "DB_PASSWORD",
+					ValueFrom: &ketchv1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "go-app-env"},
+							Key:                  "DB_PASSWORD",
+						},
+					},
+				},
+			},
+			wantSecretData: map[string][]byte{"DB_PASSWORD": []byte("secret")},
+		},
+		{
+			name: "secret values, existing secret",
+			objects: []runtime.Object{envApp(), envFramework(), &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "go-app-env", Namespace: "ketch-myframework"},
+				Data:       map[string][]byte{"API_KEY": []byte("key")},
+			}},
+			options: envSetOptions{appName: "go-app", envs: []string{"LOG_LEVEL=warn"}, secret: true},
+			wantEnvs: []ketchv1.Env{
+				{
+					Name: "LOG_LEVEL",
+					ValueFrom: &ketchv1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "go-app-env"},
+							Key:                  "LOG_LEVEL",
+						},
+					},
+				},
+			},
+			wantSecretData: map[string][]byte{"API_KEY": []byte("key"), "LOG_LEVEL": []byte("warn")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: tt.objects,
+			}
+			err := envSet(context.Background(), cfg, tt.options, &bytes.Buffer{})
+			require.Nil(t, err)
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantEnvs, gotApp.Spec.Env)
+
+			if tt.wantSecretData == nil {
+				return
+			}
+			gotSecret := corev1.Secret{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app-env", Namespace: "ketch-myframework"}, &gotSecret)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantSecretData, gotSecret.Data)
+			if len(gotSecret.OwnerReferences) > 0 {
+				require.Equal(t, "go-app", gotSecret.OwnerReferences[0].Name)
+			}
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/deploy"
@@ -49,9 +51,41 @@ func envUnset(ctx context.Context, cfg config, options envUnsetOptions, out io.W
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
 		return fmt.Errorf("failed to get the app: %w", err)
 	}
+	names := make(map[string]struct{}, len(options.envs))
+	for _, name := range options.envs {
+		names[name] = struct{}{}
+	}
+	var secretKeys []string
+	for _, env := range app.Spec.Env {
+		if _, ok := names[env.Name]; ok && env.IsSecret() && env.ValueFrom.SecretKeyRef.Name == app.EnvSecretName() {
+			secretKeys = append(secretKeys, env.ValueFrom.SecretKeyRef.Key)
+		}
+	}
 	app.UnsetEnvs(options.envs)
 	if err := cfg.Client().Update(ctx, &app); err != nil {
 		return fmt.Errorf("failed to update the app: %w", err)
 	}
+	if len(secretKeys) > 0 {
+		return unsetEnvSecret(ctx, cfg, app, secretKeys)
+	}
+	return nil
+}
+
+// unsetEnvSecret removes the keys from the app's env secret.
+func unsetEnvSecret(ctx context.Context, cfg config, app ketchv1.App, keys []string) error {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	secret := corev1.Secret{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.EnvSecretName(), Namespace: framework.Spec.NamespaceName}, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	for _, key := range keys {
+		delete(secret.Data, key)
+	}
+	if err := cfg.Client().Update(ctx, &secret); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+)
+
+func TestEnvUnset(t *testing.T) {
+	app := envApp()
+	app.SetSecretEnvs([]string{"DB_PASSWORD", "API_KEY"})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-app-env", Namespace: "ketch-myframework"},
+		Data:       map[string][]byte{"DB_PASSWORD": []byte("secret"), "API_KEY": []byte("key")},
+	}
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{app, envFramework(), secret},
+	}
+	err := envUnset(context.Background(), cfg, envUnsetOptions{appName: "go-app", envs: []string{"LOG_LEVEL", "DB_PASSWORD"}}, &bytes.Buffer{})
+	require.Nil(t, err)
+
+	gotApp := ketchv1.App{}
+	err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app"}, &gotApp)
+	require.Nil(t, err)
+	require.Len(t, gotApp.Spec.Env, 1)
+	require.Equal(t, "API_KEY", gotApp.Spec.Env[0].Name)
+
+	gotSecret := corev1.Secret{}
+	err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: "go-app-env", Namespace: "ketch-myframework"}, &gotSecret)
+	require.Nil(t, err)
+	require.Equal(t, map[string][]byte{"API_KEY": []byte("key")}, gotSecret.Data)
+}
@@ -19,6 +19,20 @@ const (
 	ErrLogUnknownTimeFormat cliError = "unknown time format"
 
 	ErrClusterIssuerNotFound cliError = "cluster issuer not found"
+
+	ErrInvalidAutoscaling cliError = "invalid autoscaling, min replicas should be at least 1, max replicas should not be less than min replicas " +
+		"and utilization targets should not be negative"
+
+	ErrNoResources cliError = "either resources or --reset should be specified"
+
+	ErrNoScheduling cliError = "either scheduling settings or --reset should be specified"
+
+	ErrInvalidRollbackCanary cliError = "a canary rollback requires --step-interval and at most 100 --steps"
+
+	ErrInvalidVolume cliError = "exactly one of --pvc, --size, --configmap, --secret and --empty-dir should be specified"
+
+	ErrNoRunningUnits cliError = "app has no running units"
+	ErrUnitNotFound   cliError = "unit not found"
 )
 
 func unwrappedError(err error) error {
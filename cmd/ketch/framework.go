@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
 
 	"github.com/spf13/cobra"
 )
@@ -18,6 +20,7 @@ NOTE: "pool" has been deprecated and replaced with "framework". The functionalit
 const (
 	defaultIstioIngressClassName   = "istio"
 	defaultTraefikIngressClassName = "traefik"
+	defaultNginxIngressClassName   = "nginx"
 	defaultVersion                 = "v1"
 )
 
@@ -40,6 +43,7 @@ func newFrameworkCmd(cfg config, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newFrameworkRemoveCmd(cfg, out))
 	cmd.AddCommand(newFrameworkUpdateCmd(cfg, out))
 	cmd.AddCommand(newFrameworkExportCmd(cfg))
+	cmd.AddCommand(newFrameworkTemplatesCmd(cfg, out))
 	return cmd
 }
 
@@ -61,10 +65,42 @@ func assignDefaultsToFramework(framework *ketchv1.Framework) {
 		framework.Spec.IngressController.IngressType = ketchv1.TraefikIngressControllerType
 	}
 	if len(framework.Spec.IngressController.ClassName) == 0 {
-		if framework.Spec.IngressController.IngressType.String() == defaultIstioIngressClassName {
+		switch framework.Spec.IngressController.IngressType {
+		case ketchv1.IstioIngressControllerType:
 			framework.Spec.IngressController.ClassName = defaultIstioIngressClassName
-		} else {
+		case ketchv1.NginxIngressControllerType:
+			framework.Spec.IngressController.ClassName = defaultNginxIngressClassName
+		case ketchv1.GatewayAPIIngressControllerType:
+			// routes are attached to a gateway rather than picked up by an ingress class.
+		default:
 			framework.Spec.IngressController.ClassName = defaultTraefikIngressClassName
 		}
 	}
 }
+
+// validateFrameworkTemplates checks that custom templates of the framework exist and render a chart of a sample app,
+// like "ketch framework templates push" does before it sets them.
+func validateFrameworkTemplates(cfg config, framework ketchv1.Framework) error {
+	if len(framework.Spec.Templates) == 0 {
+		return nil
+	}
+	tpls, err := cfg.Storage().Get(framework.Spec.Templates)
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	if err := chart.ValidateTemplates(*tpls, framework); err != nil {
+		return fmt.Errorf("failed to render templates: %w", err)
+	}
+	return nil
+}
+
+// parseGateway returns a reference to a Gateway in the [namespace/]name format or nil if the value is empty.
+func parseGateway(value string) *ketchv1.GatewayReference {
+	if len(value) == 0 {
+		return nil
+	}
+	if parts := strings.SplitN(value, "/", 2); len(parts) == 2 {
+		return &ketchv1.GatewayReference{Namespace: parts[0], Name: parts[1]}
+	}
+	return &ketchv1.GatewayReference{Name: value}
+}
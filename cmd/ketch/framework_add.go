@@ -24,6 +24,30 @@ by passing a filename such as framework.yaml containing fields like:
 	  name: istio
 	  endpoint: 10.10.10.20 # load balancer ingress ip
 	  type: istio
+	defaultResources: # used by processes without their own resources
+	  requests:
+	    cpu: 100m
+	    memory: 128Mi
+	  limits:
+	    memory: 256Mi
+	resourceQuota: # total resources requested by all apps of the framework, cpu and memory require defaultResources
+	  cpu: "4"
+	  memory: 8Gi
+	  pods: 20
+	defaultScheduling: # combined with the scheduling of every process
+	  nodeSelector:
+	    pool: apps
+	  tolerations:
+	    - key: spot
+	      operator: Exists
+	      effect: NoSchedule
+
+Frameworks with the gateway-api ingress type attach routes of apps to an existing Gateway:
+	ingressController:
+	  type: gateway-api
+	  gateway:
+	    name: public
+	    namespace: gateways # the framework's namespace if not set
 `
 
 type ingressType enumflag.Flag
@@ -31,11 +55,15 @@ type ingressType enumflag.Flag
 const (
 	traefik ingressType = iota
 	istio
+	nginx
+	gatewayAPI
 )
 
 var ingressTypeIds = map[ingressType][]string{
-	traefik: {ketchv1.TraefikIngressControllerType.String()},
-	istio:   {ketchv1.IstioIngressControllerType.String()},
+	traefik:    {ketchv1.TraefikIngressControllerType.String()},
+	istio:      {ketchv1.IstioIngressControllerType.String()},
+	nginx:      {ketchv1.NginxIngressControllerType.String()},
+	gatewayAPI: {ketchv1.GatewayAPIIngressControllerType.String()},
 }
 
 type addFrameworkFn func(ctx context.Context, cfg config, options frameworkAddOptions, out io.Writer) error
@@ -56,12 +84,13 @@ func newFrameworkAddCmd(cfg config, out io.Writer, addFramework addFrameworkFn)
 	cmd.Flags().StringVar(&options.version, "version", defaultVersion, "Version for this framework")
 	cmd.Flags().StringVar(&options.namespace, "namespace", "", "Kubernetes namespace for this framework")
 	cmd.Flags().IntVar(&options.appQuotaLimit, "app-quota-limit", defaultAppQuotaLimit, "Quota limit for app when adding it to this framework")
-	cmd.Flags().StringVar(&options.ingressClassName, "ingress-class-name", "", `if set, it is used as kubernetes.io/ingress.class annotations. Ketch uses "istio" class name for istio ingress controller, if class name is not specified`)
+	cmd.Flags().StringVar(&options.ingressClassName, "ingress-class-name", "", `if set, it is used as kubernetes.io/ingress.class annotations. Ketch uses "istio" class name for istio ingress controller and "nginx" for nginx, if class name is not specified`)
 	cmd.Flags().StringVar(&options.ingressClusterIssuer, "cluster-issuer", "", "ClusterIssuer to obtain SSL certificates")
 	cmd.Flags().StringVar(&options.ingressServiceEndpoint, "ingress-service-endpoint", "", "an IP address or dns name of the ingress controller's Service")
-	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik or istio")
+	cmd.Flags().StringVar(&options.gateway, "gateway", "", "a Gateway in the [namespace/]name format to attach routes of apps to, required by the gateway-api ingress type")
+	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik, istio, nginx or gateway-api")
 	cmd.RegisterFlagCompletionFunc("ingress-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName}, cobra.ShellCompDirectiveDefault
+		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName, defaultNginxIngressClassName, ketchv1.GatewayAPIIngressControllerType.String()}, cobra.ShellCompDirectiveDefault
 	})
 	return cmd
 }
@@ -78,6 +107,7 @@ type frameworkAddOptions struct {
 	ingressClusterIssuer   string
 	ingressServiceEndpoint string
 	ingressType            ingressType
+	gateway                string
 }
 
 func addFramework(ctx context.Context, cfg config, options frameworkAddOptions, out io.Writer) error {
@@ -96,6 +126,16 @@ func addFramework(ctx context.Context, cfg config, options frameworkAddOptions,
 		return ErrInvalidFrameworkName
 	}
 
+	if err := framework.Spec.IngressController.Validate(); err != nil {
+		return err
+	}
+	if err := framework.Spec.ValidateResourceQuota(); err != nil {
+		return err
+	}
+	if err := validateFrameworkTemplates(cfg, *framework); err != nil {
+		return err
+	}
+
 	if len(framework.Spec.IngressController.ClusterIssuer) > 0 {
 		exists, err := clusterIssuerExist(cfg.DynamicClient(), ctx, framework.Spec.IngressController.ClusterIssuer)
 		if err != nil {
@@ -157,6 +197,7 @@ func newFrameworkFromArgs(options frameworkAddOptions) *ketchv1.Framework {
 				ServiceEndpoint: options.ingressServiceEndpoint,
 				ClusterIssuer:   options.ingressClusterIssuer,
 				IngressType:     options.ingressType.ingressControllerType(),
+				Gateway:         parseGateway(options.gateway),
 			},
 		},
 		Status: ketchv1.FrameworkStatus{},
@@ -173,6 +214,10 @@ func (o frameworkAddOptions) IngressClassName() string {
 		return defaultTraefikIngressClassName
 	}
 
+	if !o.ingressClassNameSet && o.ingressType.ingressControllerType() == ketchv1.NginxIngressControllerType {
+		return defaultNginxIngressClassName
+	}
+
 	return o.ingressClassName
 }
 
@@ -180,6 +225,10 @@ func (t ingressType) ingressControllerType() ketchv1.IngressControllerType {
 	switch t {
 	case istio:
 		return ketchv1.IstioIngressControllerType
+	case nginx:
+		return ketchv1.NginxIngressControllerType
+	case gatewayAPI:
+		return ketchv1.GatewayAPIIngressControllerType
 	default:
 		return ketchv1.TraefikIngressControllerType
 	}
@@ -117,6 +117,33 @@ ingressController:
 			},
 			wantOut: "Successfully added!\n",
 		},
+		{
+			name:          "default class name for nginx is nginx",
+			frameworkName: "hello",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{},
+				DynamicClientObjects: []runtime.Object{clusterIssuerLe},
+			},
+			options: frameworkAddOptions{
+				name:                   "hello",
+				appQuotaLimit:          5,
+				namespace:              "gke",
+				ingressServiceEndpoint: "10.10.20.30",
+				ingressType:            nginx,
+				ingressClusterIssuer:   "le-production",
+			},
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "gke",
+				AppQuotaLimit: conversions.IntPtr(5),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "nginx",
+					ServiceEndpoint: "10.10.20.30",
+					IngressType:     ketchv1.NginxIngressControllerType,
+					ClusterIssuer:   "le-production",
+				},
+			},
+			wantOut: "Successfully added!\n",
+		},
 		{
 			name:          "successfully added with istio",
 			frameworkName: "hello",
@@ -182,6 +209,41 @@ ingressController:
 			},
 			wantErr: ErrClusterIssuerNotFound.Error(),
 		},
+		{
+			name:          "gateway-api with a gateway in another namespace",
+			frameworkName: "hello",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{},
+				DynamicClientObjects: []runtime.Object{},
+			},
+			options: frameworkAddOptions{
+				name:          "hello",
+				appQuotaLimit: 5,
+				ingressType:   gatewayAPI,
+				gateway:       "gateways/public",
+			},
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "ketch-hello",
+				AppQuotaLimit: conversions.IntPtr(5),
+				IngressController: ketchv1.IngressControllerSpec{
+					IngressType: ketchv1.GatewayAPIIngressControllerType,
+					Gateway:     &ketchv1.GatewayReference{Name: "public", Namespace: "gateways"},
+				},
+			},
+			wantOut: "Successfully added!\n",
+		},
+		{
+			name: "error - gateway-api without a gateway",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{},
+				DynamicClientObjects: []runtime.Object{},
+			},
+			options: frameworkAddOptions{
+				name:        "hello",
+				ingressType: gatewayAPI,
+			},
+			wantErr: ketchv1.ErrGatewayRequired.Error(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -228,6 +290,15 @@ func Test_newFrameworkAddCmd(t *testing.T) {
 				return nil
 			},
 		},
+		{
+			name: "nginx ingress type",
+			args: []string{"ketch", "gke", "--ingress-type", "nginx"},
+			addFramework: func(ctx context.Context, cfg config, options frameworkAddOptions, out io.Writer) error {
+				require.Equal(t, nginx, options.ingressType)
+				require.Equal(t, "nginx", options.IngressClassName())
+				return nil
+			},
+		},
 		{
 			name: "class name is set",
 			args: []string{"ketch", "gke", "--ingress-type", "istio", "--ingress-class-name", "custom-istio"},
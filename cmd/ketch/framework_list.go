@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
 
 	"github.com/shipa-corp/ketch/cmd/ketch/output"
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
@@ -24,6 +25,9 @@ type frameworkListOutput struct {
 	IngressClassName string `json:"ingressClassName" yaml:"ingressClassName"`
 	ClusterIssuer    string `json:"clusterIssuer" yaml:"clusterIssuer"`
 	Apps             string `json:"apps" yaml:"apps"`
+	CPU              string `json:"cpu" yaml:"cpu" column:"CPU"`
+	Memory           string `json:"memory" yaml:"memory"`
+	Pods             string `json:"pods" yaml:"pods"`
 }
 
 func newFrameworkListCmd(cfg config, out io.Writer) *cobra.Command {
@@ -61,11 +65,27 @@ func generateFrameworkListOutput(frameworks ketchv1.FrameworkList) []frameworkLi
 			IngressClassName: item.Spec.IngressController.ClassName,
 			ClusterIssuer:    item.Spec.IngressController.ClusterIssuer,
 			Apps:             apps,
+			CPU:              quotaUsage(item.Status.ResourceQuota, v1.ResourceRequestsCPU),
+			Memory:           quotaUsage(item.Status.ResourceQuota, v1.ResourceRequestsMemory),
+			Pods:             quotaUsage(item.Status.ResourceQuota, v1.ResourcePods),
 		})
 	}
 	return output
 }
 
+// quotaUsage returns the used and hard amounts of a resource of the framework's quota formatted as "used/hard".
+func quotaUsage(status *v1.ResourceQuotaStatus, name v1.ResourceName) string {
+	if status == nil {
+		return ""
+	}
+	hard, ok := status.Hard[name]
+	if !ok {
+		return ""
+	}
+	used := status.Used[name]
+	return fmt.Sprintf("%s/%s", used.String(), hard.String())
+}
+
 func frameworkListNames(cfg config, nameFilter ...string) ([]string, error) {
 	frameworks := ketchv1.FrameworkList{}
 	if err := cfg.Client().List(context.TODO(), &frameworks); err != nil {
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -30,6 +32,20 @@ func Test_frameworkList(t *testing.T) {
 				IngressType:     ketchv1.IstioIngressControllerType,
 			},
 		},
+		Status: ketchv1.FrameworkStatus{
+			ResourceQuota: &v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{
+					v1.ResourceRequestsCPU:    resource.MustParse("4"),
+					v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+					v1.ResourcePods:           resource.MustParse("20"),
+				},
+				Used: v1.ResourceList{
+					v1.ResourceRequestsCPU:    resource.MustParse("1500m"),
+					v1.ResourceRequestsMemory: resource.MustParse("2Gi"),
+					v1.ResourcePods:           resource.MustParse("3"),
+				},
+			},
+		},
 	}
 	frameworkB := &ketchv1.Framework{
 		TypeMeta: metav1.TypeMeta{},
@@ -46,6 +62,11 @@ func Test_frameworkList(t *testing.T) {
 				IngressType:     ketchv1.TraefikIngressControllerType,
 			},
 		},
+		Status: ketchv1.FrameworkStatus{
+			ResourceQuota: &v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("10")},
+			},
+		},
 	}
 	tests := []struct {
 		name string
@@ -59,9 +80,9 @@ func Test_frameworkList(t *testing.T) {
 			cfg: &mocks.Configuration{
 				CtrlClientObjects: []runtime.Object{frameworkA, frameworkB},
 			},
-			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS
-framework-a              a            istio           istio                 letsencrypt       0/30
-framework-b              b            traefik         classname-b           letsencrypt       0/30
+			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS    CPU        MEMORY     PODS
+framework-a              a            istio           istio                 letsencrypt       0/30    1500m/4    2Gi/8Gi    3/20
+framework-b              b            traefik         classname-b           letsencrypt       0/30                          0/10
 `,
 		},
 	}
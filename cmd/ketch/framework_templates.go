@@ -0,0 +1,27 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const frameworkTemplatesHelp = `
+Manage templates used to render helm charts of a framework's apps.
+A framework uses the default templates of its ingress controller type until custom templates are pushed.
+`
+
+func newFrameworkTemplatesCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "Manage chart templates of a framework",
+		Long:  frameworkTemplatesHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newFrameworkTemplatesPushCmd(cfg, out, frameworkTemplatesPush))
+	cmd.AddCommand(newFrameworkTemplatesPullCmd(cfg, out, frameworkTemplatesPull))
+	return cmd
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const frameworkTemplatesPullHelp = `
+Download templates used to render helm charts of the framework's apps to a directory.
+If the framework has no custom templates, the default templates of its ingress controller type are downloaded.
+Existing files in the directory are not overwritten.
+`
+
+type frameworkTemplatesPullFn func(context.Context, config, frameworkTemplatesPullOptions, io.Writer) error
+
+func newFrameworkTemplatesPullCmd(cfg config, out io.Writer, frameworkTemplatesPull frameworkTemplatesPullFn) *cobra.Command {
+	options := frameworkTemplatesPullOptions{}
+	cmd := &cobra.Command{
+		Use:   "pull FRAMEWORK DIRECTORY",
+		Short: "Download chart templates of a framework.",
+		Args:  cobra.ExactArgs(2),
+		Long:  frameworkTemplatesPullHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.frameworkName = args[0]
+			options.directory = args[1]
+			return frameworkTemplatesPull(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteFrameworkNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type frameworkTemplatesPullOptions struct {
+	frameworkName string
+	directory     string
+}
+
+func frameworkTemplatesPull(ctx context.Context, cfg config, options frameworkTemplatesPullOptions, out io.Writer) error {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.frameworkName}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	tpls, err := cfg.Storage().Get(framework.TemplatesConfigMapName())
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	for name := range tpls.Yamls {
+		if _, err := os.Stat(filepath.Join(options.directory, name)); !os.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", name, errFileExists)
+		}
+	}
+	if err := os.MkdirAll(options.directory, 0755); err != nil {
+		return err
+	}
+	for name, content := range tpls.Yamls {
+		if err := os.WriteFile(filepath.Join(options.directory, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "Successfully pulled %d templates to %s\n", len(tpls.Yamls), options.directory)
+	return nil
+}
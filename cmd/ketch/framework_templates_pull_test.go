@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+func Test_frameworkTemplatesPull(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gke",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.IstioIngressControllerType,
+			},
+		},
+	}
+	aws := gke.DeepCopy()
+	aws.Name = "aws"
+	aws.Spec.Templates = "framework-aws-templates"
+
+	yamls := map[string]string{
+		"deployment.yaml": "kind: Deployment",
+		"service.yaml":    "kind: Service",
+	}
+	tests := []struct {
+		name          string
+		frameworkName string
+		existingFile  string
+
+		wantConfigMap string
+		wantOut       string
+		wantErr       string
+	}{
+		{
+			name:          "default templates of the ingress controller",
+			frameworkName: "gke",
+			wantConfigMap: "ingress-istio-templates",
+			wantOut:       "Successfully pulled 2 templates to ",
+		},
+		{
+			name:          "custom templates",
+			frameworkName: "aws",
+			wantConfigMap: "framework-aws-templates",
+			wantOut:       "Successfully pulled 2 templates to ",
+		},
+		{
+			name:          "file exists",
+			frameworkName: "aws",
+			existingFile:  "service.yaml",
+			wantConfigMap: "framework-aws-templates",
+			wantErr:       "service.yaml: file already exists",
+		},
+		{
+			name:          "no framework",
+			frameworkName: "azure",
+			wantErr:       `failed to get framework: frameworks.theketch.io "azure" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, aws},
+				StorageInstance: &mockStorage{
+					OnGet: func(name string) (*templates.Templates, error) {
+						require.Equal(t, tt.wantConfigMap, name)
+						return &templates.Templates{Yamls: yamls}, nil
+					},
+				},
+			}
+			dir := filepath.Join(t.TempDir(), "templates")
+			if len(tt.existingFile) > 0 {
+				require.Nil(t, os.MkdirAll(dir, 0755))
+				require.Nil(t, os.WriteFile(filepath.Join(dir, tt.existingFile), []byte("kind: ConfigMap"), 0644))
+			}
+			options := frameworkTemplatesPullOptions{
+				frameworkName: tt.frameworkName,
+				directory:     dir,
+			}
+			out := &bytes.Buffer{}
+			err := frameworkTemplatesPull(context.Background(), cfg, options, out)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut+dir+"\n", out.String())
+			got, err := templates.ReadDirectory(dir)
+			require.Nil(t, err)
+			require.Equal(t, yamls, got.Yamls)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+const frameworkTemplatesPushHelp = `
+Upload templates from a directory and use them to render helm charts of the framework's apps.
+The templates are validated by rendering a chart of a sample app before they are accepted.
+Apps of the framework pick up the new templates on their next reconciliation.
+The templates are deleted with the framework.
+
+Start from the templates currently used by the framework:
+  ketch framework templates pull <framework name> ./templates
+  ketch framework templates push <framework name> ./templates
+`
+
+type frameworkTemplatesPushFn func(context.Context, config, frameworkTemplatesPushOptions, io.Writer) error
+
+func newFrameworkTemplatesPushCmd(cfg config, out io.Writer, frameworkTemplatesPush frameworkTemplatesPushFn) *cobra.Command {
+	options := frameworkTemplatesPushOptions{}
+	cmd := &cobra.Command{
+		Use:   "push FRAMEWORK DIRECTORY",
+		Short: "Upload custom chart templates of a framework.",
+		Args:  cobra.ExactArgs(2),
+		Long:  frameworkTemplatesPushHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.frameworkName = args[0]
+			options.directory = args[1]
+			return frameworkTemplatesPush(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteFrameworkNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type frameworkTemplatesPushOptions struct {
+	frameworkName string
+	directory     string
+}
+
+func frameworkTemplatesPush(ctx context.Context, cfg config, options frameworkTemplatesPushOptions, out io.Writer) error {
+	tpls, err := templates.ReadDirectory(options.directory)
+	if err != nil {
+		return fmt.Errorf("failed to read templates: %w", err)
+	}
+	if len(tpls.Yamls) == 0 {
+		return fmt.Errorf("no templates found in %s", options.directory)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.frameworkName}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	if err := chart.ValidateTemplates(*tpls, framework); err != nil {
+		return fmt.Errorf("failed to render templates: %w", err)
+	}
+	name := templates.FrameworkConfigMapName(framework.Name)
+	// the framework owns its templates, so they are deleted with the framework.
+	owner := metav1.NewControllerRef(&framework, ketchv1.GroupVersion.WithKind("Framework"))
+	if err := cfg.Storage().Update(name, *tpls, *owner); err != nil {
+		return fmt.Errorf("failed to store templates: %w", err)
+	}
+	if framework.Spec.Templates != name {
+		framework.Spec.Templates = name
+		if err := cfg.Client().Update(ctx, &framework); err != nil {
+			return fmt.Errorf("failed to update framework: %w", err)
+		}
+	}
+	fmt.Fprintln(out, "Successfully pushed templates!")
+	return nil
+}
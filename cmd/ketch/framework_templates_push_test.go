@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+func Test_frameworkTemplatesPush(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "gke",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				ClassName:   "istio",
+				IngressType: ketchv1.IstioIngressControllerType,
+			},
+		},
+	}
+	writeTemplates := func(t *testing.T, yamls map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range yamls {
+			require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+		return dir
+	}
+	brokenYamls := map[string]string{}
+	for name, content := range templates.IstioDefaultTemplates.Yamls {
+		brokenYamls[name] = content
+	}
+	brokenYamls["service.yaml"] = "{{ if $.Values.app.name }}"
+
+	tests := []struct {
+		name          string
+		frameworkName string
+		yamls         map[string]string
+
+		wantTemplates string
+		wantOut       string
+		wantErr       string
+	}{
+		{
+			name:          "push templates",
+			frameworkName: "gke",
+			yamls:         templates.IstioDefaultTemplates.Yamls,
+			wantTemplates: "framework-gke-templates",
+			wantOut:       "Successfully pushed templates!\n",
+		},
+		{
+			name:          "templates fail to render",
+			frameworkName: "gke",
+			yamls:         brokenYamls,
+			wantErr:       "failed to render templates: parse error at (sample/templates/service.yaml:1): unexpected EOF",
+		},
+		{
+			name:          "empty directory",
+			frameworkName: "gke",
+			wantErr:       "no templates found in",
+		},
+		{
+			name:          "no framework",
+			frameworkName: "aws",
+			yamls:         templates.IstioDefaultTemplates.Yamls,
+			wantErr:       `failed to get framework: frameworks.theketch.io "aws" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored *templates.Templates
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework.DeepCopy()},
+				StorageInstance: &mockStorage{
+					OnUpdate: func(name string, tpls templates.Templates, owners ...metav1.OwnerReference) error {
+						require.Equal(t, "framework-gke-templates", name)
+						require.Len(t, owners, 1)
+						require.Equal(t, "Framework", owners[0].Kind)
+						require.Equal(t, "gke", owners[0].Name)
+						stored = &tpls
+						return nil
+					},
+				},
+			}
+			options := frameworkTemplatesPushOptions{
+				frameworkName: tt.frameworkName,
+				directory:     writeTemplates(t, tt.yamls),
+			}
+			out := &bytes.Buffer{}
+			err := frameworkTemplatesPush(context.Background(), cfg, options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				require.Nil(t, stored)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+			require.Equal(t, tt.yamls, stored.Yamls)
+
+			gotFramework := ketchv1.Framework{}
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.frameworkName}, &gotFramework))
+			require.Equal(t, tt.wantTemplates, gotFramework.Spec.Templates)
+		})
+	}
+}
@@ -41,6 +41,7 @@ func newFrameworkUpdateCmd(cfg config, out io.Writer) *cobra.Command {
 			options.ingressServiceEndpointSet = cmd.Flags().Changed("ingress-service-endpoint")
 			options.ingressTypeSet = cmd.Flags().Changed("ingress-type")
 			options.ingressClusterIssuerSet = cmd.Flags().Changed("cluster-issuer")
+			options.gatewaySet = cmd.Flags().Changed("gateway")
 			return frameworkUpdate(cmd.Context(), cfg, options, out)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -52,9 +53,10 @@ func newFrameworkUpdateCmd(cfg config, out io.Writer) *cobra.Command {
 	cmd.Flags().StringVar(&options.ingressClassName, "ingress-class-name", "", "if set, it is used as kubernetes.io/ingress.class annotations")
 	cmd.Flags().StringVar(&options.ingressServiceEndpoint, "ingress-service-endpoint", "", "an IP address or dns name of the ingress controller's Service")
 	cmd.Flags().StringVar(&options.ingressClusterIssuer, "cluster-issuer", "", "ClusterIssuer to obtain SSL certificates")
-	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik or istio")
+	cmd.Flags().StringVar(&options.gateway, "gateway", "", "a Gateway in the [namespace/]name format to attach routes of apps to, required by the gateway-api ingress type")
+	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik, istio, nginx or gateway-api, changing it removes custom templates of the framework")
 	cmd.RegisterFlagCompletionFunc("ingress-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName}, cobra.ShellCompDirectiveDefault
+		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName, defaultNginxIngressClassName, ketchv1.GatewayAPIIngressControllerType.String()}, cobra.ShellCompDirectiveDefault
 	})
 	return cmd
 }
@@ -74,6 +76,8 @@ type frameworkUpdateOptions struct {
 	ingressServiceEndpoint    string
 	ingressTypeSet            bool
 	ingressType               ingressType
+	gatewaySet                bool
+	gateway                   string
 }
 
 func frameworkUpdate(ctx context.Context, cfg config, options frameworkUpdateOptions, out io.Writer) error {
@@ -94,6 +98,16 @@ func frameworkUpdate(ctx context.Context, cfg config, options frameworkUpdateOpt
 		return ErrInvalidFrameworkName
 	}
 
+	if err := framework.Spec.IngressController.Validate(); err != nil {
+		return err
+	}
+	if err := framework.Spec.ValidateResourceQuota(); err != nil {
+		return err
+	}
+	if err := validateFrameworkTemplates(cfg, *framework); err != nil {
+		return err
+	}
+
 	if len(framework.Spec.IngressController.ClusterIssuer) > 0 {
 		exists, err := clusterIssuerExist(cfg.DynamicClient(), ctx, framework.Spec.IngressController.ClusterIssuer)
 		if err != nil {
@@ -134,9 +148,13 @@ func updateFrameworkFromYaml(ctx context.Context, cfg config, options frameworkU
 	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: spec.Name}, &framework); err != nil {
 		return nil, fmt.Errorf("failed to get the framework: %w", err)
 	}
+	oldSpec := framework.Spec
 	framework.Spec = spec
 
 	assignDefaultsToFramework(&framework)
+	if err := framework.Spec.ValidateTemplatesUpdate(oldSpec); err != nil {
+		return nil, err
+	}
 
 	return &framework, nil
 }
@@ -160,10 +178,18 @@ func updateFrameworkFromArgs(ctx context.Context, cfg config, options frameworkU
 		framework.Spec.IngressController.ServiceEndpoint = options.ingressServiceEndpoint
 	}
 	if options.ingressTypeSet {
-		framework.Spec.IngressController.IngressType = options.ingressType.ingressControllerType()
+		ingressType := options.ingressType.ingressControllerType()
+		if framework.Spec.IngressController.IngressType != ingressType {
+			// custom templates are written for the previous ingress controller type.
+			framework.Spec.Templates = ""
+		}
+		framework.Spec.IngressController.IngressType = ingressType
 	}
 	if options.ingressClusterIssuerSet {
 		framework.Spec.IngressController.ClusterIssuer = options.ingressClusterIssuer
 	}
+	if options.gatewaySet {
+		framework.Spec.IngressController.Gateway = parseGateway(options.gateway)
+	}
 	return &framework, nil
 }
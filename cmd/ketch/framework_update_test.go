@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/templates"
 	"github.com/shipa-corp/ketch/internal/utils/conversions"
 )
 
@@ -58,6 +60,16 @@ func Test_frameworkUpdate(t *testing.T) {
 			},
 		},
 	}
+	customFramework := frontendFramework.DeepCopy()
+	customFramework.Spec.Templates = "framework-frontend-framework-templates"
+	storage := &mockStorage{
+		OnGet: func(name string) (*templates.Templates, error) {
+			if name != "framework-frontend-framework-templates" {
+				return nil, fmt.Errorf("configmaps %q not found", name)
+			}
+			return &templates.IstioDefaultTemplates, nil
+		},
+	}
 
 	tests := []struct {
 		name              string
@@ -220,6 +232,131 @@ ingressController:
 				},
 			},
 		},
+		{
+			name:          "update ingress type removes custom templates",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{customFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name:           "frontend-framework",
+				ingressTypeSet: true,
+				ingressType:    traefik,
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.TraefikIngressControllerType,
+					ClusterIssuer:   "le-staging",
+				},
+			},
+		},
+		{
+			name:          "framework from yaml file with custom templates",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{customFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+				StorageInstance:      storage,
+			},
+			yamlData: `name: frontend-framework
+namespace: frontend
+appQuotaLimit: 30
+templates: framework-frontend-framework-templates
+ingressController:
+ type: istio
+ serviceEndpoint: 192.168.1.17
+ clusterIssuer: le-staging
+ className: default-classname`,
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				Name:          "frontend-framework",
+				Version:       "v1",
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.IstioIngressControllerType,
+					ClusterIssuer:   "le-staging",
+				},
+				Templates: "framework-frontend-framework-templates",
+			},
+		},
+		{
+			name:          "error - yaml file keeps custom templates of another ingress type",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{customFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+				StorageInstance:      storage,
+			},
+			yamlData: `name: frontend-framework
+templates: framework-frontend-framework-templates
+ingressController:
+ type: nginx`,
+			wantErr: ketchv1.ErrTemplatesOfAnotherIngressType.Error(),
+		},
+		{
+			name:          "error - yaml file with missing templates",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+				StorageInstance:      storage,
+			},
+			yamlData: `name: frontend-framework
+templates: framework-aws-templates
+ingressController:
+ type: istio`,
+			wantErr: `failed to get templates: configmaps "framework-aws-templates" not found`,
+		},
+		{
+			name:          "update ingress type to gateway-api",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name:           "frontend-framework",
+				ingressTypeSet: true,
+				ingressType:    gatewayAPI,
+				gatewaySet:     true,
+				gateway:        "public",
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.GatewayAPIIngressControllerType,
+					ClusterIssuer:   "le-staging",
+					Gateway:         &ketchv1.GatewayReference{Name: "public"},
+				},
+			},
+		},
+		{
+			name:          "error - gateway-api without a gateway",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name:           "frontend-framework",
+				ingressTypeSet: true,
+				ingressType:    gatewayAPI,
+			},
+			wantErr: ketchv1.ErrGatewayRequired.Error(),
+		},
 		{
 			name:          "update cluster issuer",
 			frameworkName: "frontend-framework",
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const jobHelp = `
+Manage jobs.
+A job is an application deployed with "type: Job" in application.yaml, it runs to completion with a kubernetes Job
+or periodically with a kubernetes CronJob if it has a schedule.
+`
+
+func newJobCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job",
+		Short: "Manage jobs",
+		Long:  jobHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newJobRunCmd(cfg, out, jobRun))
+	cmd.AddCommand(newJobListCmd(cfg, out, jobList))
+	cmd.AddCommand(newJobLogsCmd(cfg, out, jobLogs))
+	return cmd
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/shipa-corp/ketch/cmd/ketch/output"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+const jobListHelp = `
+List kubernetes Jobs of all jobs or of the given job.
+`
+
+type jobListOutput struct {
+	Name        string `json:"name" yaml:"name"`
+	App         string `json:"app" yaml:"app"`
+	Process     string `json:"process" yaml:"process"`
+	Status      string `json:"status" yaml:"status"`
+	Completions string `json:"completions" yaml:"completions"`
+	StartedAt   string `json:"startedAt" yaml:"startedAt"`
+}
+
+type jobListFn func(context.Context, config, string, io.Writer) error
+
+func newJobListCmd(cfg config, out io.Writer, jobList jobListFn) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [APPNAME]",
+		Short: "List kubernetes Jobs of jobs.",
+		Long:  jobListHelp,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appName := ""
+			if len(args) > 0 {
+				appName = args[0]
+			}
+			return jobList(cmd.Context(), cfg, appName, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func jobList(ctx context.Context, cfg config, appName string, out io.Writer) error {
+	var selector client.ListOption = client.HasLabels{utils.KetchAppNameLabel}
+	if len(appName) > 0 {
+		selector = client.MatchingLabels{utils.KetchAppNameLabel: appName}
+	}
+	jobs := batchv1.JobList{}
+	if err := cfg.Client().List(ctx, &jobs, selector); err != nil {
+		return fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return output.Write(generateJobListOutput(jobs), out, "column")
+}
+
+func generateJobListOutput(jobs batchv1.JobList) []jobListOutput {
+	var outputs []jobListOutput
+	for _, job := range jobs.Items {
+		completions := int32(1)
+		if job.Spec.Completions != nil {
+			completions = *job.Spec.Completions
+		}
+		startedAt := ""
+		if job.Status.StartTime != nil {
+			startedAt = job.Status.StartTime.Format(time.RFC3339)
+		}
+		outputs = append(outputs, jobListOutput{
+			Name:        job.Name,
+			App:         job.Labels[utils.KetchAppNameLabel],
+			Process:     job.Labels[utils.KetchProcessNameLabel],
+			Status:      jobState(job),
+			Completions: fmt.Sprintf("%d/%d", job.Status.Succeeded, completions),
+			StartedAt:   startedAt,
+		})
+	}
+	return outputs
+}
+
+func jobState(job batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return "succeeded"
+		case batchv1.JobFailed:
+			return "failed"
+		}
+	}
+	return "running"
+}
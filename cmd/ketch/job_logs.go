@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+const (
+	jobLogsHelp = `
+Show logs of a job.
+Logs of all kubernetes Jobs of the job are shown unless --job is set, pods of finished Jobs are kept by kubernetes
+until the Jobs are removed.
+`
+	// jobNameLabel is set by kubernetes on pods of a Job.
+	jobNameLabel = "job-name"
+)
+
+type jobLogsFn func(context.Context, config, jobLogsOptions, io.Writer, watchLogsFn) error
+
+func newJobLogsCmd(cfg config, out io.Writer, jobLogs jobLogsFn) *cobra.Command {
+	options := jobLogsOptions{}
+	cmd := &cobra.Command{
+		Use:   "logs APPNAME",
+		Short: "Show logs of a job.",
+		Long:  jobLogsHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return jobLogs(cmd.Context(), cfg, options, out, watchLogs)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.jobName, "job", "", "Name of a kubernetes Job as shown by \"ketch job list\"")
+	cmd.Flags().StringVarP(&options.processName, "process", "p", "", "Process name")
+	cmd.Flags().BoolVarP(&options.follow, "follow", "f", false, "Specify if the logs should be streamed")
+	cmd.Flags().BoolVar(&options.prefix, "prefix", false, "Prefix each log line with the log source (pod name and container name)")
+	cmd.Flags().BoolVar(&options.timestamps, "timestamps", false, "Include timestamps on each line in the log output")
+	return cmd
+}
+
+type jobLogsOptions struct {
+	appName     string
+	jobName     string
+	processName string
+	follow      bool
+	timestamps  bool
+	prefix      bool
+}
+
+func jobLogs(ctx context.Context, cfg config, options jobLogsOptions, out io.Writer, watchLogs watchLogsFn) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app instance: %w", err)
+	}
+	if !app.IsJob() {
+		return fmt.Errorf("failed to get logs: %w", ketchv1.ErrNotJob)
+	}
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework instance: %w", err)
+	}
+	set := map[string]string{
+		utils.KetchAppNameLabel: options.appName,
+	}
+	if len(options.jobName) > 0 {
+		set[jobNameLabel] = options.jobName
+	}
+	if len(options.processName) > 0 {
+		set[utils.KetchProcessNameLabel] = options.processName
+	}
+	opts := watchOptions{
+		namespace:  framework.Spec.NamespaceName,
+		selector:   labels.SelectorFromSet(set),
+		follow:     options.follow,
+		timestamps: options.timestamps,
+		prefix:     options.prefix,
+		out:        out,
+	}
+	return watchLogs(cfg.KubernetesClient(), opts, readLogs, streamLogs)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+const jobRunHelp = `
+Run a job once more.
+A job without a schedule runs once it's deployed, a scheduled job runs at the scheduled time,
+this command starts a new kubernetes Job of the latest deployment right away.
+Changing the configuration of a job without a schedule, for example its environment variables, runs it again too,
+a scheduled job runs with its new configuration at the next scheduled time.
+`
+
+type jobRunFn func(context.Context, config, string, io.Writer) error
+
+func newJobRunCmd(cfg config, out io.Writer, jobRun jobRunFn) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run APPNAME",
+		Short: "Run a job once more.",
+		Long:  jobRunHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return jobRun(cmd.Context(), cfg, args[0], out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func jobRun(ctx context.Context, cfg config, appName string, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	if err := app.RunJob(); err != nil {
+		return fmt.Errorf("failed to run job: %w", err)
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully started!")
+	return nil
+}
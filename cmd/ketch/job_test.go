@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/mocks"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+func jobApp(appType ketchv1.AppType) *ketchv1.App {
+	return &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			Type:      appType,
+			Job:       &ketchv1.JobSpec{Schedule: "@daily", Run: 2},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 1, Image: "backup:v1"},
+			},
+		},
+	}
+}
+
+func TestJobRun(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     *ketchv1.App
+		wantRun int
+		wantErr string
+	}{
+		{
+			name:    "run a job",
+			app:     jobApp(ketchv1.JobAppType),
+			wantRun: 3,
+		},
+		{
+			name:    "error - app is not a job",
+			app:     jobApp(ketchv1.ApplicationAppType),
+			wantErr: "failed to run job: app is not a job",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app},
+			}
+			out := &bytes.Buffer{}
+			err := jobRun(context.Background(), cfg, tt.app.Name, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully started!\n", out.String())
+
+			gotApp := ketchv1.App{}
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.app.Name}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantRun, gotApp.Spec.Job.Run)
+		})
+	}
+}
+
+func TestJobList(t *testing.T) {
+	startTime := metav1.NewTime(time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC))
+	job := func(name, appName string, succeeded int32, conditionType batchv1.JobConditionType) *batchv1.Job {
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ketch-gke",
+				Labels: map[string]string{
+					utils.KetchAppNameLabel:     appName,
+					utils.KetchProcessNameLabel: "worker",
+				},
+			},
+			Status: batchv1.JobStatus{Succeeded: succeeded, StartTime: &startTime},
+		}
+		if len(conditionType) > 0 {
+			j.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+		}
+		return j
+	}
+	objects := []runtime.Object{
+		job("backup-worker-1-0123abcd", "backup", 1, batchv1.JobComplete),
+		job("backup-worker-1-4567abcd", "backup", 0, ""),
+		job("report-worker-2-89abcdef", "report", 0, batchv1.JobFailed),
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "not-ketch", Namespace: "default"}},
+	}
+	tests := []struct {
+		name    string
+		appName string
+		want    string
+	}{
+		{
+			name: "all jobs",
+			want: `NAME                        APP       PROCESS    STATUS       COMPLETIONS    STARTED AT
+backup-worker-1-0123abcd    backup    worker     succeeded    1/1            2021-03-01T10:00:00Z
+backup-worker-1-4567abcd    backup    worker     running      0/1            2021-03-01T10:00:00Z
+report-worker-2-89abcdef    report    worker     failed       0/1            2021-03-01T10:00:00Z
+`,
+		},
+		{
+			name:    "jobs of an app",
+			appName: "report",
+			want: `NAME                        APP       PROCESS    STATUS    COMPLETIONS    STARTED AT
+report-worker-2-89abcdef    report    worker     failed    0/1            2021-03-01T10:00:00Z
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: objects,
+			}
+			out := &bytes.Buffer{}
+			err := jobList(context.Background(), cfg, tt.appName, out)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestJobLogs(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	tests := []struct {
+		name             string
+		app              *ketchv1.App
+		options          jobLogsOptions
+		wantWatchOptions watchOptions
+		wantErr          string
+	}{
+		{
+			name:    "logs of a kubernetes Job",
+			app:     jobApp(ketchv1.JobAppType),
+			options: jobLogsOptions{appName: "backup", jobName: "backup-worker-1-0123abcd", follow: true},
+			wantWatchOptions: watchOptions{
+				namespace: "ketch-gke",
+				selector: labels.SelectorFromSet(map[string]string{
+					utils.KetchAppNameLabel: "backup",
+					jobNameLabel:            "backup-worker-1-0123abcd",
+				}),
+				follow: true,
+			},
+		},
+		{
+			name:    "error - app is not a job",
+			app:     jobApp(ketchv1.ApplicationAppType),
+			options: jobLogsOptions{appName: "backup"},
+			wantErr: "failed to get logs: app is not a job",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{tt.app, gke},
+			}
+			called := false
+			watchFn := func(client kubernetes.Interface, options watchOptions, readLogs_ readLogsFn, streamLogs_ streamLogsFn) error {
+				called = true
+				options.out = nil
+				require.Equal(t, tt.wantWatchOptions, options)
+				return nil
+			}
+			err := jobLogs(context.Background(), cfg, tt.options, &bytes.Buffer{}, watchFn)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				require.False(t, called)
+				return
+			}
+			require.Nil(t, err)
+			require.True(t, called)
+		})
+	}
+}
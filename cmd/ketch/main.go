@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/spf13/pflag"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/exec"
@@ -28,8 +31,18 @@ func main() {
 		log.Fatalf("couldn't create pack service %q", err)
 	}
 
+	// commands are cancelled on the first interrupt so they can clean up, e.g. "ketch app run" removes its pod,
+	// the second interrupt terminates ketch right away.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	cmd := newRootCmd(&configuration.Configuration{}, out, packSvc, getKetchConfig())
-	if err := cmd.Execute(); err != nil {
+	err = cmd.ExecuteContext(ctx)
+	stop()
+	if err != nil {
 		log.Fatalf("execution failed %q", err)
 	}
 }
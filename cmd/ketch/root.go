@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/shipa-corp/ketch/cmd/ketch/configuration"
@@ -23,6 +24,8 @@ type config interface {
 	KubernetesClient() kubernetes.Interface
 	// DynamicClient returns kubernetes dynamic client. It's used to work with CRDs for which we don't have go types like ClusterIssuer.
 	DynamicClient() dynamic.Interface
+	// RESTConfig returns a config to connect to the kubernetes API server. It's used to stream to and from containers.
+	RESTConfig() *rest.Config
 }
 
 type resourceCreator interface {
@@ -62,6 +65,7 @@ func newRootCmd(cfg config, out io.Writer, packSvc *pack.Client, ketchConfig con
 	cmd.AddCommand(newCnameCmd(cfg, out))
 	cmd.AddCommand(newFrameworkCmd(cfg, out))
 	cmd.AddCommand(newEnvCmd(cfg, out))
+	cmd.AddCommand(newJobCmd(cfg, out))
 	cmd.AddCommand(newCompletionCmd())
 	return cmd
 }
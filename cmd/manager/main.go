@@ -29,6 +29,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/canary"
 	"github.com/shipa-corp/ketch/internal/chart"
 	"github.com/shipa-corp/ketch/internal/controllers"
 	"github.com/shipa-corp/ketch/internal/templates"
@@ -87,6 +88,14 @@ func main() {
 		setupLog.Error(err, "unable to set default templates")
 		os.Exit(1)
 	}
+	if err = storage.Update(templates.IngressConfigMapName(ketchv1.NginxIngressControllerType.String()), templates.NginxDefaultTemplates); err != nil {
+		setupLog.Error(err, "unable to set default templates")
+		os.Exit(1)
+	}
+	if err = storage.Update(templates.IngressConfigMapName(ketchv1.GatewayAPIIngressControllerType.String()), templates.GatewayAPIDefaultTemplates); err != nil {
+		setupLog.Error(err, "unable to set default templates")
+		os.Exit(1)
+	}
 
 	if err = (&controllers.AppReconciler{
 		TemplateReader: storage,
@@ -98,6 +107,9 @@ func main() {
 		},
 		Now:      time.Now,
 		Recorder: mgr.GetEventRecorderFor("App"),
+		QuerierFactoryFn: func(address string) (canary.Querier, error) {
+			return canary.NewPrometheusQuerier(address), nil
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "App")
 		os.Exit(1)
@@ -11,6 +11,7 @@ require (
 	github.com/google/go-containerregistry v0.1.4
 	github.com/mattn/go-runewidth v0.0.7 // indirect
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/pflag v1.0.5
 	github.com/stretchr/testify v1.6.1
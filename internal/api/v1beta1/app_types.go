@@ -19,12 +19,11 @@ package v1beta1
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	"github.com/shipa-corp/ketch/internal/templates"
 )
 
 func init() {
@@ -34,6 +33,15 @@ func init() {
 const (
 	ShipaCloudDomain     = "shipa.cloud"
 	DefaultNumberOfUnits = 1
+
+	// DefaultCanaryFailureLimit is the number of consecutive failed analysis checks after which a canary is rolled back.
+	DefaultCanaryFailureLimit = 3
+
+	// DefaultBlueGreenKeepPrevious is how long the previous deployment is kept after a blue-green switch.
+	DefaultBlueGreenKeepPrevious = time.Hour
+
+	// DefaultRevisionHistoryLimit is the number of deployments kept in the app's history.
+	DefaultRevisionHistoryLimit = 10
 )
 
 // Env represents an environment variable present in an application.
@@ -43,7 +51,32 @@ type Env struct {
 	Name string `json:"name"`
 
 	// Value of the environment variable.
-	Value string `json:"value"`
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom is a source of the environment variable's value, it is used to keep private values out of the App.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a source for the value of an environment variable.
+type EnvVarSource struct {
+	// SecretKeyRef selects a key of a Secret in the framework's namespace.
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// MaskedEnvValue is shown instead of values of environment variables which are stored in secrets.
+const MaskedEnvValue = "*****"
+
+// IsSecret returns true if the value of the environment variable is stored in a secret.
+func (e Env) IsSecret() bool {
+	return e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil
+}
+
+// DisplayValue returns the value of the environment variable or MaskedEnvValue if the value is stored in a secret.
+func (e Env) DisplayValue() string {
+	if e.IsSecret() {
+		return MaskedEnvValue
+	}
+	return e.Value
 }
 
 // Label represents an environment variable present in an application.
@@ -64,6 +97,43 @@ type CnameList []string
 // then 3 of 10 incoming requests will be sent to the first deployment (approximately).
 type RoutingSettings struct {
 	Weight uint8 `json:"weight"`
+
+	// Match is a list of rules, a request matching any of them is routed to this deployment regardless of the weights.
+	// The nginx ingress controller supports only one rule, see ValidateRouteMatches.
+	Match []RouteMatch `json:"match,omitempty"`
+}
+
+// RouteMatchType is a part of a request checked by a RouteMatch rule.
+type RouteMatchType string
+
+const (
+	// RouteMatchHeader matches a request with an HTTP header of the given value.
+	RouteMatchHeader RouteMatchType = "header"
+
+	// RouteMatchCookie matches a request with a cookie of the given value.
+	RouteMatchCookie RouteMatchType = "cookie"
+)
+
+// RouteMatch is a rule to route requests to a particular deployment.
+type RouteMatch struct {
+	// +kubebuilder:validation:Enum=header;cookie
+	Type RouteMatchType `json:"type"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Name of the header or the cookie.
+	Name string `json:"name"`
+
+	// Value is an exact value of the header or the cookie.
+	Value string `json:"value"`
+}
+
+// ValidateRouteMatches returns an error if the ingress controller can't route requests with all the rules.
+// A canary ingress of the nginx ingress controller matches a single header, so it supports only one rule.
+func ValidateRouteMatches(matches []RouteMatch, ingressType IngressControllerType) error {
+	if ingressType == NginxIngressControllerType && len(matches) > 1 {
+		return ErrMultipleRouteMatches
+	}
+	return nil
 }
 
 // ProcessSpec is a specification of the desired behavior of a process.
@@ -83,6 +153,63 @@ type ProcessSpec struct {
 
 	// Security options the process should run with.
 	SecurityContext *v1.SecurityContext `json:"securityContext,omitempty"`
+
+	// EnvFrom is a list of ConfigMaps and Secrets in the framework's namespace,
+	// all their keys are set as environment variables of the process.
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Resources are CPU and memory requests and limits of the process' containers.
+	// If not set, the framework's default resources are used.
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Autoscaling if set, the number of replicas of the process is managed by a HorizontalPodAutoscaler and Units is ignored.
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	// VolumeMounts is a list of the application's volumes mounted to the process' containers.
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// TerminationGracePeriodSeconds is how long a unit of the process has to shut down once it is stopped,
+	// it includes the time spent on the restart.before hooks of ketch.yaml. The kubernetes default of 30 seconds is used if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Sidecars are containers running next to the process' container in every unit, for example proxies or log shippers.
+	// Job applications don't run sidecars because a running sidecar keeps a Job from completing.
+	Sidecars []Container `json:"sidecars,omitempty"`
+
+	// InitContainers run one by one in every unit before the process' container and sidecars start,
+	// each of them must complete successfully.
+	InitContainers []Container `json:"initContainers,omitempty"`
+
+	// Scheduling describes how units of the process are assigned to nodes,
+	// it's complemented with the default scheduling of the framework.
+	Scheduling *SchedulingSpec `json:"scheduling,omitempty"`
+
+	// DisruptionBudget limits how many units of the process can be evicted at once, for example by a node drain.
+	// If not set, a process running more than one unit can lose one unit at a time.
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+}
+
+// AutoscalingSpec configures a HorizontalPodAutoscaler of a process.
+type AutoscalingSpec struct {
+	// MinReplicas is the lower limit for the number of replicas of the process.
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas int `json:"minReplicas"`
+
+	// MaxReplicas is the upper limit for the number of replicas of the process.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int `json:"maxReplicas"`
+
+	// TargetCPUUtilization is the target average CPU utilization of the process' pods in percent of the requested CPU.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilization *int `json:"targetCPUUtilization,omitempty"`
+
+	// TargetMemoryUtilization is the target average memory utilization of the process' pods in percent of the requested memory.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilization *int `json:"targetMemoryUtilization,omitempty"`
 }
 
 type DeploymentVersion int
@@ -139,6 +266,12 @@ const (
 
 	// AppScheduled indicates whether the has been processed by ketch-controller.
 	AppScheduled AppConditionType = "Scheduled"
+
+	// DeployHooksCompleted indicates whether deploy hooks of the app's deployments have completed successfully.
+	DeployHooksCompleted AppConditionType = "DeployHooksCompleted"
+
+	// JobCompleted indicates whether the latest run of a job has completed successfully.
+	JobCompleted AppConditionType = "JobCompleted"
 )
 
 // AppCondition contains details for the current condition of this app.
@@ -164,6 +297,53 @@ type AppStatus struct {
 	Conditions []AppCondition `json:"conditions,omitempty"`
 
 	Framework *v1.ObjectReference `json:"framework,omitempty"`
+
+	// Revisions is a history of successfully rolled out deployments ordered by version,
+	// at most RevisionHistoryLimit latest deployments are kept.
+	Revisions []AppRevision `json:"revisions,omitempty"`
+
+	// Jobs contains statuses of kubernetes Jobs of a Job application.
+	Jobs []JobStatus `json:"jobs,omitempty"`
+
+	// JobRun is the spec.job.run value of the latest run of a Job application,
+	// a scheduled job is run once more when spec.job.run is greater.
+	JobRun int `json:"jobRun,omitempty"`
+}
+
+// JobStatus is a status of a kubernetes Job created for a Job application.
+type JobStatus struct {
+	// Name of the kubernetes Job.
+	Name string `json:"name"`
+
+	// Active is the number of running pods of the job.
+	Active int32 `json:"active,omitempty"`
+
+	// Succeeded is the number of pods of the job that completed successfully.
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Failed is the number of failed pods of the job.
+	Failed int32 `json:"failed,omitempty"`
+
+	// StartTime is the time the job started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time the job completed successfully.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// AppRevision is a deployment recorded in the app's history.
+type AppRevision struct {
+	// Deployment is the spec of the deployment as it was rolled out.
+	Deployment AppDeploymentSpec `json:"deployment"`
+
+	// DeployedAt is the time the deployment was rolled out.
+	DeployedAt metav1.Time `json:"deployedAt"`
+
+	// Env is the app's environment variables the deployment ran with, they are restored by a rollback.
+	Env []Env `json:"env,omitempty"`
+
+	// EnvFrom is the app's sources of environment variables the deployment ran with, they are restored by a rollback.
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
 }
 
 // CanarySpec represents configuration for a canary deployment.
@@ -185,6 +365,141 @@ type CanarySpec struct {
 	Active bool `json:"active,omitempty"`
 	// Started holds time when canary started
 	Started *metav1.Time `json:"started,omitempty"`
+	// Analysis contains metric rules that are checked before every canary step.
+	Analysis *CanaryAnalysis `json:"analysis,omitempty"`
+	// FailedChecks is the count of consecutive failed analysis checks.
+	FailedChecks int `json:"failedChecks,omitempty"`
+	// Paused shows if the canary deployment is paused, the traffic weights are not changed while it is paused.
+	Paused bool `json:"paused,omitempty"`
+	// PausedAt holds time when the canary deployment was paused.
+	PausedAt *metav1.Time `json:"pausedAt,omitempty"`
+}
+
+// CanaryAnalysis contains metric rules that are checked before every canary step.
+// If all rules pass, the canary advances to the next step.
+// If a rule fails, the next step is put on hold for one step interval,
+// and once the number of consecutive failed checks reaches FailureLimit the canary is rolled back.
+type CanaryAnalysis struct {
+	// PrometheusURL is the address of a Prometheus server used to evaluate the rules.
+	// +kubebuilder:validation:MinLength=1
+	PrometheusURL string `json:"prometheusURL"`
+
+	// SuccessRate is the minimum percentage of requests served by the canary deployment without a 5xx response.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SuccessRate *int `json:"successRate,omitempty"`
+
+	// MaxLatency is the maximum 99th percentile request duration of the canary deployment in milliseconds.
+	// +kubebuilder:validation:Minimum=1
+	MaxLatency *int `json:"maxLatency,omitempty"`
+
+	// Queries override default Prometheus query templates used to measure the success rate and the latency.
+	// The gateway-api ingress controller type has no default queries, so they are required by its analysis.
+	Queries CanaryQueries `json:"queries,omitempty"`
+
+	// FailureLimit is the number of consecutive failed checks after which the canary is rolled back.
+	// +kubebuilder:validation:Minimum=1
+	FailureLimit int `json:"failureLimit,omitempty"`
+}
+
+// CanaryQueries contains Prometheus query templates.
+// A template is rendered with the following fields available:
+// .App, .Namespace, .Process, .Version, .Deployment (a name of the canary's Deployment and Service), .Interval.
+type CanaryQueries struct {
+	// SuccessRate is a query returning a percentage of successful requests.
+	SuccessRate string `json:"successRate,omitempty"`
+
+	// Latency is a query returning a request duration in milliseconds.
+	Latency string `json:"latency,omitempty"`
+}
+
+// StrategyType is a type of a strategy used to roll out a new deployment.
+type StrategyType string
+
+const (
+	// ReplaceStrategy replaces the current deployment with a new one right away.
+	ReplaceStrategy StrategyType = "replace"
+
+	// BlueGreenStrategy runs a new deployment next to the current one without traffic,
+	// all traffic is moved to the new deployment at once when the app is switched.
+	BlueGreenStrategy StrategyType = "blue-green"
+)
+
+// StrategySpec describes how a new deployment of an application is rolled out.
+type StrategySpec struct {
+	// Type of the strategy, "replace" is used when it is empty.
+	// +kubebuilder:validation:Enum=replace;blue-green
+	Type StrategyType `json:"type,omitempty"`
+
+	// BlueGreen contains a configuration and a state of blue-green deployments.
+	BlueGreen BlueGreenSpec `json:"blueGreen,omitempty"`
+}
+
+// BlueGreenSpec represents configuration for a blue-green deployment.
+// While a blue-green deployment is active, the app has two deployments, one of them receives all traffic
+// and the other one is idle and can be accessed with the preview cname.
+type BlueGreenSpec struct {
+	// PreviewCname is a cname to access the idle deployment.
+	// If it is not set, <app name>-preview.<Framework's ServiceEndpoint>.shipa.cloud is used.
+	PreviewCname string `json:"previewCname,omitempty"`
+
+	// KeepPrevious is how long the previous deployment is kept after a switch.
+	KeepPrevious time.Duration `json:"keepPrevious,omitempty"`
+
+	// Active shows if a blue-green deployment is in progress.
+	Active bool `json:"active,omitempty"`
+
+	// SwitchedAt holds time of the last switch.
+	SwitchedAt *metav1.Time `json:"switchedAt,omitempty"`
+}
+
+// AppType is a kind of workload of an application.
+type AppType string
+
+const (
+	// ApplicationAppType is a long-running application run with Deployments, it is the default type.
+	ApplicationAppType AppType = "Application"
+
+	// JobAppType is an application that runs to completion with a Job, or periodically with a CronJob if it has a schedule.
+	JobAppType AppType = "Job"
+)
+
+// JobSpec configures Jobs and CronJobs of a Job application.
+type JobSpec struct {
+	// Schedule in Cron format, if set the job runs periodically with a CronJob.
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// ConcurrencyPolicy specifies how to treat concurrent runs of a scheduled job.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+
+	// Parallelism is the maximum number of pods of the job running at the same time.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Parallelism *int `json:"parallelism,omitempty"`
+
+	// Completions is the number of pods of the job that must complete successfully.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Completions *int `json:"completions,omitempty"`
+
+	// BackoffLimit is the number of retries before the job is marked as failed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int `json:"backoffLimit,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds the job may be active before it is terminated.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+
+	// Run is incremented to run the job once more, see "ketch job run".
+	// A job without a schedule runs with a new kubernetes Job,
+	// a scheduled job runs with a kubernetes Job created from the job template of its CronJob.
+	// +optional
+	Run int `json:"run,omitempty"`
 }
 
 // AppSpec defines the desired state of App.
@@ -197,6 +512,9 @@ type AppSpec struct {
 	// Canary contains a configuration which will be required for canary deployments.
 	Canary CanarySpec `json:"canary,omitempty"`
 
+	// Strategy contains a configuration of the strategy used to roll out new deployments.
+	Strategy StrategySpec `json:"strategy,omitempty"`
+
 	// Deployments is a list of running deployments.
 	Deployments []AppDeploymentSpec `json:"deployments"`
 
@@ -206,6 +524,26 @@ type AppSpec struct {
 	// List of environment variables of the application.
 	Env []Env `json:"env,omitempty"`
 
+	// EnvFrom is a list of ConfigMaps and Secrets in the framework's namespace,
+	// all their keys are set as environment variables of all processes of the application.
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Type of the application, either Application or Job.
+	// +kubebuilder:validation:Enum=Application;Job
+	// +optional
+	Type AppType `json:"type,omitempty"`
+
+	// Job configures Jobs and CronJobs of a Job application.
+	Job *JobSpec `json:"job,omitempty"`
+
+	// RevisionHistoryLimit is the number of deployments kept in the app's history to roll back to, 10 by default.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RevisionHistoryLimit *int `json:"revisionHistoryLimit,omitempty"`
+
+	// Volumes is a list of volumes that can be mounted by processes of the application.
+	Volumes []Volume `json:"volumes,omitempty"`
+
 	// Framework is a name of a Framework used to run the application.
 	// +kubebuilder:validation:MinLength=1
 	Framework string `json:"framework"`
@@ -263,6 +601,56 @@ func (s *AppDeploymentSpec) setUnitsForAllProcess(units int) {
 	}
 }
 
+// SetAutoscaling configures autoscaling of the specified processes.
+// A nil autoscaling disables autoscaling and the processes are scaled according to their units again.
+func (app *App) SetAutoscaling(selector Selector, autoscaling *AutoscalingSpec) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Autoscaling = autoscaling.DeepCopy()
+	})
+}
+
+// SetResources sets CPU and memory requests and limits of the specified processes.
+// Nil resources make the processes use the framework's default resources.
+func (app *App) SetResources(selector Selector, resources *v1.ResourceRequirements) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Resources = resources.DeepCopy()
+	})
+}
+
+// SetScheduling sets scheduling settings of processes matching the selector, nil removes them.
+func (app *App) SetScheduling(selector Selector, scheduling *SchedulingSpec) error {
+	return app.updateProcesses(selector, func(process *ProcessSpec) {
+		process.Scheduling = scheduling.DeepCopy()
+	})
+}
+
+// updateProcesses calls the update function for each process matching the selector.
+func (app *App) updateProcesses(selector Selector, update func(process *ProcessSpec)) error {
+	deploymentFound := false
+	for i := range app.Spec.Deployments {
+		deploymentSpec := &app.Spec.Deployments[i]
+		if selector.DeploymentVersion != nil && *selector.DeploymentVersion != deploymentSpec.Version {
+			continue
+		}
+		processFound := false
+		for j := range deploymentSpec.Processes {
+			if selector.Process != nil && *selector.Process != deploymentSpec.Processes[j].Name {
+				continue
+			}
+			update(&deploymentSpec.Processes[j])
+			processFound = true
+		}
+		if selector.Process != nil && !processFound {
+			return ErrProcessNotFound
+		}
+		deploymentFound = true
+	}
+	if selector.DeploymentVersion != nil && !deploymentFound {
+		return ErrDeploymentNotFound
+	}
+	return nil
+}
+
 // SetUnits set quantity of units of the specified processes.
 func (app *App) SetUnits(selector Selector, units int) error {
 	deploymentFound := false
@@ -307,7 +695,7 @@ func (app *App) SetEnvs(envs []Env) {
 	app.Spec.Env = newEnvs
 }
 
-// Envs returns values of the asked env variables.
+// Envs returns values of the asked env variables, values stored in secrets are masked.
 func (app *App) Envs(names []string) map[string]string {
 	namesMap := make(map[string]struct{}, len(names))
 	for _, name := range names {
@@ -317,16 +705,215 @@ func (app *App) Envs(names []string) map[string]string {
 	envs := make(map[string]string)
 	for _, env := range app.Spec.Env {
 		if len(names) == 0 {
-			envs[env.Name] = env.Value
+			envs[env.Name] = env.DisplayValue()
 			continue
 		}
 		if _, ok := namesMap[env.Name]; ok {
-			envs[env.Name] = env.Value
+			envs[env.Name] = env.DisplayValue()
 		}
 	}
 	return envs
 }
 
+// AddEnvFrom adds a ConfigMap or Secret as a source of environment variables of the app,
+// or of all deployments of the process if the process name is not empty.
+// If the app or the process already has the source, its prefix is updated.
+func (app *App) AddEnvFrom(process string, source v1.EnvFromSource) error {
+	if len(process) == 0 {
+		app.Spec.EnvFrom = addEnvFromSource(app.Spec.EnvFrom, source)
+		return nil
+	}
+	return app.updateProcesses(NewSelector(0, process), func(p *ProcessSpec) {
+		p.EnvFrom = addEnvFromSource(p.EnvFrom, source)
+	})
+}
+
+// RemoveEnvFrom removes a ConfigMap or Secret from sources of environment variables of the app,
+// or of all deployments of the process if the process name is not empty.
+func (app *App) RemoveEnvFrom(process string, source v1.EnvFromSource) error {
+	if len(process) == 0 {
+		app.Spec.EnvFrom = removeEnvFromSource(app.Spec.EnvFrom, source)
+		return nil
+	}
+	return app.updateProcesses(NewSelector(0, process), func(p *ProcessSpec) {
+		p.EnvFrom = removeEnvFromSource(p.EnvFrom, source)
+	})
+}
+
+func addEnvFromSource(sources []v1.EnvFromSource, source v1.EnvFromSource) []v1.EnvFromSource {
+	for i := range sources {
+		if sameEnvFromSource(sources[i], source) {
+			sources[i] = source
+			return sources
+		}
+	}
+	return append(sources, source)
+}
+
+func removeEnvFromSource(sources []v1.EnvFromSource, source v1.EnvFromSource) []v1.EnvFromSource {
+	var result []v1.EnvFromSource
+	for _, s := range sources {
+		if !sameEnvFromSource(s, source) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// sameEnvFromSource returns true if both sources reference the same ConfigMap or Secret.
+func sameEnvFromSource(a, b v1.EnvFromSource) bool {
+	if a.ConfigMapRef != nil && b.ConfigMapRef != nil {
+		return a.ConfigMapRef.Name == b.ConfigMapRef.Name
+	}
+	if a.SecretRef != nil && b.SecretRef != nil {
+		return a.SecretRef.Name == b.SecretRef.Name
+	}
+	return false
+}
+
+// AttachVolume adds the volume to the app or replaces the app's volume with the same name,
+// and mounts it to containers of all deployments of the process, or of all processes if the process name is empty.
+func (app *App) AttachVolume(volume Volume, mount v1.VolumeMount, process string) error {
+	if err := volume.Validate(); err != nil {
+		return err
+	}
+	mount.Name = volume.Name
+	if err := app.updateProcesses(NewSelector(0, process), func(p *ProcessSpec) {
+		p.VolumeMounts = append(removeVolumeMount(p.VolumeMounts, volume.Name), mount)
+	}); err != nil {
+		return err
+	}
+	for i := range app.Spec.Volumes {
+		if app.Spec.Volumes[i].Name == volume.Name {
+			app.Spec.Volumes[i] = volume
+			return nil
+		}
+	}
+	app.Spec.Volumes = append(app.Spec.Volumes, volume)
+	return nil
+}
+
+// DetachVolume unmounts the volume from containers of the process, or of all processes if the process name is empty.
+// The volume is removed from the app once no process mounts it.
+func (app *App) DetachVolume(name string, process string) error {
+	index := -1
+	for i, volume := range app.Spec.Volumes {
+		if volume.Name == name {
+			index = i
+		}
+	}
+	if index == -1 {
+		return ErrVolumeNotFound
+	}
+	if err := app.updateProcesses(NewSelector(0, process), func(p *ProcessSpec) {
+		p.VolumeMounts = removeVolumeMount(p.VolumeMounts, name)
+	}); err != nil {
+		return err
+	}
+	for _, deployment := range app.Spec.Deployments {
+		for _, p := range deployment.Processes {
+			for _, mount := range p.ContainerVolumeMounts() {
+				if mount.Name == name {
+					return nil
+				}
+			}
+		}
+	}
+	app.Spec.Volumes = append(app.Spec.Volumes[:index], app.Spec.Volumes[index+1:]...)
+	return nil
+}
+
+func removeVolumeMount(mounts []v1.VolumeMount, name string) []v1.VolumeMount {
+	var result []v1.VolumeMount
+	for _, mount := range mounts {
+		if mount.Name != name {
+			result = append(result, mount)
+		}
+	}
+	return result
+}
+
+// PodVolumes returns kubernetes volumes to be added to pods of the app.
+func (app *App) PodVolumes() []v1.Volume {
+	if len(app.Spec.Volumes) == 0 {
+		return nil
+	}
+	volumes := make([]v1.Volume, 0, len(app.Spec.Volumes))
+	for _, volume := range app.Spec.Volumes {
+		volumes = append(volumes, volume.PodVolume(app.Name))
+	}
+	return volumes
+}
+
+// ConfigMapNames returns sorted names of ConfigMaps the app gets environment variables from.
+func (app *App) ConfigMapNames() []string {
+	names := map[string]struct{}{}
+	for _, source := range app.envFromSources() {
+		if source.ConfigMapRef != nil {
+			names[source.ConfigMapRef.Name] = struct{}{}
+		}
+	}
+	return sortedNames(names)
+}
+
+// SecretNames returns sorted names of Secrets the app gets environment variables from.
+func (app *App) SecretNames() []string {
+	names := map[string]struct{}{}
+	for _, source := range app.envFromSources() {
+		if source.SecretRef != nil {
+			names[source.SecretRef.Name] = struct{}{}
+		}
+	}
+	for _, env := range app.Spec.Env {
+		if env.IsSecret() {
+			names[env.ValueFrom.SecretKeyRef.Name] = struct{}{}
+		}
+	}
+	return sortedNames(names)
+}
+
+func (app *App) envFromSources() []v1.EnvFromSource {
+	sources := append([]v1.EnvFromSource{}, app.Spec.EnvFrom...)
+	for _, deployment := range app.Spec.Deployments {
+		for _, process := range deployment.Processes {
+			sources = append(sources, process.EnvFrom...)
+		}
+	}
+	return sources
+}
+
+func sortedNames(names map[string]struct{}) []string {
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// EnvSecretName returns the name of a Secret in the framework's namespace to store private environment variables of the app.
+func (app *App) EnvSecretName() string {
+	return fmt.Sprintf("%s-env", app.Name)
+}
+
+// SetSecretEnvs extends the current list of environment variables with variables whose values are stored
+// under the same keys in the app's env secret.
+func (app *App) SetSecretEnvs(names []string) {
+	envs := make([]Env, 0, len(names))
+	for _, name := range names {
+		envs = append(envs, Env{
+			Name: name,
+			ValueFrom: &EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: app.EnvSecretName()},
+					Key:                  name,
+				},
+			},
+		})
+	}
+	app.SetEnvs(envs)
+}
+
 // UnsetEnvs unsets environment values.
 func (app *App) UnsetEnvs(envs []string) {
 	names := make(map[string]struct{}, len(envs))
@@ -412,9 +999,20 @@ func (app *App) DefaultCname(framework *Framework) *string {
 	return &url
 }
 
-// TemplatesConfigMapName returns a name of a configmap that contains templates used to render a helm chart.
-func (app *App) TemplatesConfigMapName(ingressControllerType IngressControllerType) string {
-	return templates.IngressConfigMapName(ingressControllerType.String())
+// PreviewCname returns a cname to access the idle deployment of an active blue-green deployment.
+func (app *App) PreviewCname(framework *Framework) *string {
+	if !app.Spec.Strategy.BlueGreen.Active {
+		return nil
+	}
+	if len(app.Spec.Strategy.BlueGreen.PreviewCname) > 0 {
+		cname := app.Spec.Strategy.BlueGreen.PreviewCname
+		return &cname
+	}
+	if framework == nil || len(framework.Spec.IngressController.ServiceEndpoint) == 0 {
+		return nil
+	}
+	url := fmt.Sprintf("%s-preview.%s.%s", app.Name, framework.Spec.IngressController.ServiceEndpoint, ShipaCloudDomain)
+	return &url
 }
 
 // Units returns a total number units.
@@ -484,11 +1082,33 @@ func (s AppStatus) Condition(t AppConditionType) *AppCondition {
 	return nil
 }
 
+// StepDue returns true if the next canary step is scheduled at or before the given time.
+func (s CanarySpec) StepDue(now metav1.Time) bool {
+	if !s.Active || s.Paused || s.NextScheduledTime == nil {
+		return false
+	}
+	return s.NextScheduledTime.Equal(&now) || s.NextScheduledTime.Before(&now)
+}
+
+// HoldCanary postpones the next canary step by one step interval and records a failed analysis check.
+// It returns true if the number of consecutive failed checks has reached the failure limit and the canary must be rolled back.
+func (app *App) HoldCanary() bool {
+	app.Spec.Canary.FailedChecks++
+	if app.Spec.Canary.NextScheduledTime != nil {
+		*app.Spec.Canary.NextScheduledTime = metav1.NewTime(app.Spec.Canary.NextScheduledTime.Add(app.Spec.Canary.StepTimeInteval))
+	}
+	limit := DefaultCanaryFailureLimit
+	if app.Spec.Canary.Analysis != nil && app.Spec.Canary.Analysis.FailureLimit > 0 {
+		limit = app.Spec.Canary.Analysis.FailureLimit
+	}
+	return app.Spec.Canary.FailedChecks >= limit
+}
+
 // DoCanary checks if canary deployment is needed for an app and gradually increases the traffic weight
 // based on the canary parameters provided by the users. Use it in app controller.
 func (app *App) DoCanary(now metav1.Time) error {
 
-	if !app.Spec.Canary.Active {
+	if !app.Spec.Canary.Active || app.Spec.Canary.Paused {
 		return nil
 	}
 
@@ -505,6 +1125,7 @@ func (app *App) DoCanary(now metav1.Time) error {
 		app.Spec.Deployments[0].RoutingSettings.Weight = app.Spec.Deployments[0].RoutingSettings.Weight - app.Spec.Canary.StepWeight
 		app.Spec.Deployments[1].RoutingSettings.Weight = app.Spec.Deployments[1].RoutingSettings.Weight + app.Spec.Canary.StepWeight
 		app.Spec.Canary.CurrentStep++
+		app.Spec.Canary.FailedChecks = 0
 
 		// update next scheduled time
 		*app.Spec.Canary.NextScheduledTime = metav1.NewTime(app.Spec.Canary.NextScheduledTime.Add(app.Spec.Canary.StepTimeInteval))
@@ -515,6 +1136,7 @@ func (app *App) DoCanary(now metav1.Time) error {
 			// we need to set weight of the target deployment to 100
 			// because there is a chance that on the last step weight is not equal to 100 (e.g. steps=3, step-weight=33)
 			app.Spec.Deployments[1].RoutingSettings.Weight = 100
+			app.Spec.Deployments[1].RoutingSettings.Match = nil
 
 			app.Spec.Canary.Active = false
 			app.Spec.Canary.CurrentStep = app.Spec.Canary.Steps
@@ -528,12 +1150,239 @@ func (app *App) DoCanary(now metav1.Time) error {
 	return nil
 }
 
+// PromoteCanary routes all traffic to the canary deployment immediately and removes the primary deployment.
+func (app *App) PromoteCanary() error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if len(app.Spec.Deployments) <= 1 {
+		return ErrDeploymentNotFound
+	}
+	app.Spec.Deployments[1].RoutingSettings.Weight = 100
+	app.Spec.Deployments[1].RoutingSettings.Match = nil
+	app.Spec.Canary.Active = false
+	app.Spec.Canary.CurrentStep = app.Spec.Canary.Steps
+	app.Spec.Canary.NextScheduledTime = nil
+	app.Spec.Canary.Paused = false
+	app.Spec.Canary.PausedAt = nil
+	app.Spec.Deployments = []AppDeploymentSpec{app.Spec.Deployments[1]}
+	return nil
+}
+
+// PauseCanary freezes the canary deployment at its current step until it is resumed.
+func (app *App) PauseCanary(now metav1.Time) error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if app.Spec.Canary.Paused {
+		return ErrCanaryPaused
+	}
+	app.Spec.Canary.Paused = true
+	app.Spec.Canary.PausedAt = &now
+	return nil
+}
+
+// ResumeCanary resumes a paused canary deployment.
+// The next step is postponed by the time the canary has been paused, so the step keeps the rest of its interval.
+func (app *App) ResumeCanary(now metav1.Time) error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if !app.Spec.Canary.Paused {
+		return ErrCanaryNotPaused
+	}
+	if app.Spec.Canary.NextScheduledTime != nil && app.Spec.Canary.PausedAt != nil {
+		paused := now.Sub(app.Spec.Canary.PausedAt.Time)
+		*app.Spec.Canary.NextScheduledTime = metav1.NewTime(app.Spec.Canary.NextScheduledTime.Add(paused))
+	}
+	app.Spec.Canary.Paused = false
+	app.Spec.Canary.PausedAt = nil
+	return nil
+}
+
+// AbortCanary stops the canary deployment and routes all traffic back to the primary deployment.
+func (app *App) AbortCanary() error {
+	if !app.Spec.Canary.Active {
+		return ErrCanaryNotActive
+	}
+	if len(app.Spec.Deployments) <= 1 {
+		return ErrDeploymentNotFound
+	}
+	app.DoRollback()
+	return nil
+}
+
+// SwitchBlueGreen moves all traffic to the idle deployment of an active blue-green deployment at once.
+// The deployment that received traffic before the switch becomes idle and is kept for KeepPrevious,
+// so switching again moves traffic back to it.
+func (app *App) SwitchBlueGreen(now metav1.Time) error {
+	if !app.Spec.Strategy.BlueGreen.Active {
+		return ErrBlueGreenNotActive
+	}
+	if len(app.Spec.Deployments) <= 1 {
+		return ErrDeploymentNotFound
+	}
+	for i, deployment := range app.Spec.Deployments {
+		if deployment.RoutingSettings.Weight == 0 {
+			app.Spec.Deployments[i].RoutingSettings.Weight = 100
+		} else {
+			app.Spec.Deployments[i].RoutingSettings.Weight = 0
+		}
+	}
+	app.Spec.Strategy.BlueGreen.SwitchedAt = &now
+	return nil
+}
+
+// BlueGreenCleanupTime returns time when the idle deployment of a switched blue-green deployment must be removed.
+func (app *App) BlueGreenCleanupTime() *metav1.Time {
+	blueGreen := app.Spec.Strategy.BlueGreen
+	if !blueGreen.Active || blueGreen.SwitchedAt == nil {
+		return nil
+	}
+	keepPrevious := blueGreen.KeepPrevious
+	if keepPrevious == 0 {
+		keepPrevious = DefaultBlueGreenKeepPrevious
+	}
+	t := metav1.NewTime(blueGreen.SwitchedAt.Add(keepPrevious))
+	return &t
+}
+
+// CompleteBlueGreen removes the idle deployment and finishes a blue-green deployment.
+func (app *App) CompleteBlueGreen() {
+	deployments := make([]AppDeploymentSpec, 0, 1)
+	for _, deployment := range app.Spec.Deployments {
+		if deployment.RoutingSettings.Weight > 0 {
+			deployments = append(deployments, deployment)
+		}
+	}
+	app.Spec.Deployments = deployments
+	app.Spec.Strategy.BlueGreen.Active = false
+	app.Spec.Strategy.BlueGreen.SwitchedAt = nil
+}
+
 // DoRollback performs rollback
 func (app *App) DoRollback() {
 	// we need to rollback all weight to the primary deployment
 	app.Spec.Deployments[0].RoutingSettings.Weight = 100
 	app.Spec.Deployments[1].RoutingSettings.Weight = 0
 	app.Spec.Canary.Active = false
+	app.Spec.Canary.NextScheduledTime = nil
+	app.Spec.Canary.Paused = false
+	app.Spec.Canary.PausedAt = nil
+
+	// remove the canary deployment, so the next deploy starts from the primary deployment
+	app.Spec.Deployments = []AppDeploymentSpec{app.Spec.Deployments[0]}
+}
+
+// IsJob returns true if the app runs to completion with Jobs or CronJobs rather than with Deployments.
+func (app *App) IsJob() bool {
+	return app.Spec.Type == JobAppType
+}
+
+// RunJob requests one more run of a Job application.
+func (app *App) RunJob() error {
+	if !app.IsJob() {
+		return ErrNotJob
+	}
+	if len(app.Spec.Deployments) == 0 {
+		return ErrDeploymentNotFound
+	}
+	if app.Spec.Job == nil {
+		app.Spec.Job = &JobSpec{}
+	}
+	app.Spec.Job.Run += 1
+	return nil
+}
+
+// RecordRevision adds the app's deployment to its history, or updates the recorded one,
+// and removes the oldest deployments beyond the revision history limit.
+// A deployment is recorded only once its rollout has finished, that is when it's the only deployment of the app and gets all traffic,
+// so steps of a canary deployment or an aborted canary deployment are never recorded.
+func (app *App) RecordRevision(now metav1.Time) {
+	if len(app.Spec.Deployments) != 1 || app.Spec.Canary.Active || app.Spec.Strategy.BlueGreen.Active {
+		return
+	}
+	deployment := app.Spec.Deployments[0]
+	if deployment.RoutingSettings.Weight != 100 {
+		return
+	}
+	var env []Env
+	for _, e := range app.Spec.Env {
+		env = append(env, *e.DeepCopy())
+	}
+	var envFrom []v1.EnvFromSource
+	for _, source := range app.Spec.EnvFrom {
+		envFrom = append(envFrom, *source.DeepCopy())
+	}
+	if revision := app.Revision(deployment.Version); revision != nil {
+		revision.Deployment = *deployment.DeepCopy()
+		revision.Env = env
+		revision.EnvFrom = envFrom
+	} else {
+		app.Status.Revisions = append(app.Status.Revisions, AppRevision{
+			Deployment: *deployment.DeepCopy(),
+			DeployedAt: now,
+			Env:        env,
+			EnvFrom:    envFrom,
+		})
+	}
+	sort.Slice(app.Status.Revisions, func(i, j int) bool {
+		return app.Status.Revisions[i].Deployment.Version < app.Status.Revisions[j].Deployment.Version
+	})
+	limit := DefaultRevisionHistoryLimit
+	if app.Spec.RevisionHistoryLimit != nil {
+		limit = *app.Spec.RevisionHistoryLimit
+	}
+	if len(app.Status.Revisions) > limit {
+		app.Status.Revisions = app.Status.Revisions[len(app.Status.Revisions)-limit:]
+	}
+}
+
+// Revision returns the deployment with the version from the app's history or nil.
+func (app *App) Revision(version DeploymentVersion) *AppRevision {
+	for i := range app.Status.Revisions {
+		if app.Status.Revisions[i].Deployment.Version == version {
+			return &app.Status.Revisions[i]
+		}
+	}
+	return nil
+}
+
+// RollbackTo rolls out a new deployment with the exact spec of a deployment from the app's history
+// and restores the app's environment variables the deployment ran with.
+// If steps is greater than 1, the new deployment is rolled out as a canary deployment.
+func (app *App) RollbackTo(version DeploymentVersion, steps int, stepTimeInterval time.Duration, now metav1.Time) error {
+	revision := app.Revision(version)
+	if revision == nil {
+		return ErrRevisionNotFound
+	}
+	if app.Spec.Canary.Active || app.Spec.Strategy.BlueGreen.Active || len(app.Spec.Deployments) > 1 {
+		return ErrDeploymentInProgress
+	}
+	deployment := *revision.Deployment.DeepCopy()
+	app.Spec.DeploymentsCount += 1
+	deployment.Version = DeploymentVersion(app.Spec.DeploymentsCount)
+	deployment.RoutingSettings = RoutingSettings{Weight: 100}
+	app.Spec.Env = revision.Env
+	app.Spec.EnvFrom = revision.EnvFrom
+	if steps <= 1 || len(app.Spec.Deployments) == 0 {
+		app.Spec.Deployments = []AppDeploymentSpec{deployment}
+		return nil
+	}
+	next := metav1.NewTime(now.Add(stepTimeInterval))
+	app.Spec.Canary = CanarySpec{
+		Steps:             steps,
+		StepWeight:        uint8(100 / steps),
+		StepTimeInteval:   stepTimeInterval,
+		NextScheduledTime: &next,
+		CurrentStep:       1,
+		Active:            true,
+		Started:           &now,
+	}
+	// the controller sets the canary's weight once its pods are running.
+	deployment.RoutingSettings.Weight = 0
+	app.Spec.Deployments = append(app.Spec.Deployments, deployment)
+	return nil
 }
 
 // PodState describes the simplified state of a pod in the cluster
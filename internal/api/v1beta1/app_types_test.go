@@ -276,6 +276,17 @@ func TestApp_Envs(t *testing.T) {
 				"KETCH": "true",
 			},
 		},
+		{
+			name: "secret values are masked",
+			initialEnvs: []Env{
+				{Name: "KETCH", Value: "true"},
+				{Name: "DB_PASSWORD", ValueFrom: &EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{Key: "DB_PASSWORD"}}},
+			},
+			want: map[string]string{
+				"KETCH":       "true",
+				"DB_PASSWORD": MaskedEnvValue,
+			},
+		},
 		{
 			name:  "app has no envs",
 			names: []string{"KETCH", "API_KEY", "SOME_VAR"},
@@ -490,37 +501,6 @@ func TestApp_CNames(t *testing.T) {
 	}
 }
 
-func TestApp_TemplatesConfigMapName(t *testing.T) {
-	tests := []struct {
-		name        string
-		app         App
-		ingressType IngressControllerType
-
-		want string
-	}{
-		{
-			name:        "istio configmap",
-			app:         App{},
-			ingressType: IstioIngressControllerType,
-			want:        "ingress-istio-templates",
-		},
-		{
-			name:        "traefik configmap",
-			app:         App{},
-			ingressType: TraefikIngressControllerType,
-			want:        "ingress-traefik-templates",
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := tt.app.TemplatesConfigMapName(tt.ingressType)
-			if diff := cmp.Diff(got, tt.want); diff != "" {
-				t.Errorf("TemplatesConfigMapName() mismatch (-want +got):\n%s", diff)
-			}
-		})
-	}
-}
-
 func TestApp_Units(t *testing.T) {
 	tests := []struct {
 		name string
@@ -1112,6 +1092,29 @@ func TestApp_DoCanary(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "canary is paused - no changes",
+			now:           *timeRef(10, 31),
+			wantNoChanges: true,
+			app: App{
+				Spec: AppSpec{
+					Canary: CanarySpec{
+						Steps:             3,
+						StepWeight:        33,
+						StepTimeInteval:   10 * time.Minute,
+						NextScheduledTime: timeRef(10, 30),
+						CurrentStep:       2,
+						Active:            true,
+						Paused:            true,
+						PausedAt:          timeRef(10, 20),
+					},
+					Deployments: []AppDeploymentSpec{
+						{Version: 2, RoutingSettings: RoutingSettings{Weight: 34}},
+						{Version: 3, RoutingSettings: RoutingSettings{Weight: 66}},
+					},
+				},
+			},
+		},
 		{
 			name:          "error - nextScheduledTime is not set",
 			now:           *timeRef(10, 45),
@@ -1153,3 +1156,432 @@ func TestApp_DoCanary(t *testing.T) {
 		})
 	}
 }
+
+func TestCanarySpec_StepDue(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	tests := []struct {
+		name   string
+		canary CanarySpec
+		now    metav1.Time
+		want   bool
+	}{
+		{
+			name:   "step is due",
+			canary: CanarySpec{Active: true, NextScheduledTime: timeRef(10, 30)},
+			now:    *timeRef(10, 31),
+			want:   true,
+		},
+		{
+			name:   "step is due right now",
+			canary: CanarySpec{Active: true, NextScheduledTime: timeRef(10, 30)},
+			now:    *timeRef(10, 30),
+			want:   true,
+		},
+		{
+			name:   "too early",
+			canary: CanarySpec{Active: true, NextScheduledTime: timeRef(10, 30)},
+			now:    *timeRef(10, 29),
+			want:   false,
+		},
+		{
+			name:   "canary is not active",
+			canary: CanarySpec{Active: false, NextScheduledTime: timeRef(10, 30)},
+			now:    *timeRef(10, 31),
+			want:   false,
+		},
+		{
+			name:   "next step is not scheduled",
+			canary: CanarySpec{Active: true},
+			now:    *timeRef(10, 31),
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.canary.StepDue(tt.now))
+		})
+	}
+}
+
+func TestApp_HoldCanary(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	tests := []struct {
+		name             string
+		canary           CanarySpec
+		wantAbort        bool
+		wantFailedChecks int
+		wantNextStep     *metav1.Time
+	}{
+		{
+			name: "first failed check",
+			canary: CanarySpec{
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 30),
+				Analysis:          &CanaryAnalysis{},
+			},
+			wantFailedChecks: 1,
+			wantNextStep:     timeRef(10, 40),
+		},
+		{
+			name: "default failure limit reached",
+			canary: CanarySpec{
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 30),
+				FailedChecks:      DefaultCanaryFailureLimit - 1,
+				Analysis:          &CanaryAnalysis{},
+			},
+			wantAbort:        true,
+			wantFailedChecks: DefaultCanaryFailureLimit,
+			wantNextStep:     timeRef(10, 40),
+		},
+		{
+			name: "custom failure limit reached",
+			canary: CanarySpec{
+				StepTimeInteval:   5 * time.Minute,
+				NextScheduledTime: timeRef(10, 30),
+				Analysis:          &CanaryAnalysis{FailureLimit: 1},
+			},
+			wantAbort:        true,
+			wantFailedChecks: 1,
+			wantNextStep:     timeRef(10, 35),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := App{Spec: AppSpec{Canary: tt.canary}}
+			require.Equal(t, tt.wantAbort, app.HoldCanary())
+			require.Equal(t, tt.wantFailedChecks, app.Spec.Canary.FailedChecks)
+			require.Equal(t, tt.wantNextStep, app.Spec.Canary.NextScheduledTime)
+		})
+	}
+}
+
+func TestApp_DoRollback(t *testing.T) {
+	next := metav1.Date(2021, 2, 1, 10, 30, 0, 0, time.UTC)
+	app := App{
+		Spec: AppSpec{
+			Canary: CanarySpec{
+				Steps:             3,
+				StepWeight:        33,
+				NextScheduledTime: &next,
+				CurrentStep:       2,
+				Active:            true,
+			},
+			Deployments: []AppDeploymentSpec{
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 67}},
+				{Version: 3, RoutingSettings: RoutingSettings{Weight: 33}},
+			},
+		},
+	}
+	app.DoRollback()
+	require.False(t, app.Spec.Canary.Active)
+	require.Nil(t, app.Spec.Canary.NextScheduledTime)
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}},
+	}, app.Spec.Deployments)
+}
+
+func TestApp_PauseResumeCanary(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	app := App{
+		Spec: AppSpec{
+			Canary: CanarySpec{
+				StepTimeInteval:   10 * time.Minute,
+				NextScheduledTime: timeRef(10, 30),
+				Active:            true,
+			},
+		},
+	}
+	require.Equal(t, ErrCanaryNotPaused, app.ResumeCanary(*timeRef(10, 21)))
+
+	require.Nil(t, app.PauseCanary(*timeRef(10, 25)))
+	require.True(t, app.Spec.Canary.Paused)
+	require.Equal(t, timeRef(10, 25), app.Spec.Canary.PausedAt)
+	require.False(t, app.Spec.Canary.StepDue(*timeRef(10, 45)))
+	require.Equal(t, ErrCanaryPaused, app.PauseCanary(*timeRef(10, 26)))
+
+	require.Nil(t, app.ResumeCanary(*timeRef(10, 45)))
+	require.False(t, app.Spec.Canary.Paused)
+	require.Nil(t, app.Spec.Canary.PausedAt)
+	require.Equal(t, timeRef(10, 50), app.Spec.Canary.NextScheduledTime)
+
+	app.Spec.Canary.Active = false
+	require.Equal(t, ErrCanaryNotActive, app.PauseCanary(*timeRef(10, 46)))
+	require.Equal(t, ErrCanaryNotActive, app.ResumeCanary(*timeRef(10, 46)))
+}
+
+func TestApp_PromoteCanary(t *testing.T) {
+	app := App{
+		Spec: AppSpec{
+			Canary: CanarySpec{
+				Steps:       3,
+				StepWeight:  33,
+				CurrentStep: 1,
+				Active:      true,
+				Paused:      true,
+			},
+			Deployments: []AppDeploymentSpec{
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 67}},
+				{Version: 3, RoutingSettings: RoutingSettings{Weight: 33, Match: []RouteMatch{{Type: RouteMatchHeader, Name: "X-Canary", Value: "true"}}}},
+			},
+		},
+	}
+	require.Nil(t, app.PromoteCanary())
+	require.Equal(t, CanarySpec{Steps: 3, StepWeight: 33, CurrentStep: 3}, app.Spec.Canary)
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 3, RoutingSettings: RoutingSettings{Weight: 100}},
+	}, app.Spec.Deployments)
+	require.Equal(t, ErrCanaryNotActive, app.PromoteCanary())
+	require.Equal(t, ErrCanaryNotActive, app.AbortCanary())
+}
+
+func TestApp_BlueGreen(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	framework := &Framework{
+		Spec: FrameworkSpec{
+			IngressController: IngressControllerSpec{ServiceEndpoint: "10.10.10.10"},
+		},
+	}
+	app := App{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-app"},
+		Spec: AppSpec{
+			Strategy: StrategySpec{
+				Type: BlueGreenStrategy,
+				BlueGreen: BlueGreenSpec{
+					Active:       true,
+					KeepPrevious: 30 * time.Minute,
+				},
+			},
+			Deployments: []AppDeploymentSpec{
+				{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}},
+				{Version: 3, RoutingSettings: RoutingSettings{Weight: 0}},
+			},
+		},
+	}
+	require.Equal(t, "go-app-preview.10.10.10.10.shipa.cloud", *app.PreviewCname(framework))
+	require.Nil(t, app.BlueGreenCleanupTime())
+
+	require.Nil(t, app.SwitchBlueGreen(*timeRef(10, 0)))
+	require.Equal(t, uint8(0), app.Spec.Deployments[0].RoutingSettings.Weight)
+	require.Equal(t, uint8(100), app.Spec.Deployments[1].RoutingSettings.Weight)
+	require.Equal(t, timeRef(10, 30), app.BlueGreenCleanupTime())
+
+	// switching again moves traffic back to the previous deployment
+	require.Nil(t, app.SwitchBlueGreen(*timeRef(10, 10)))
+	require.Equal(t, uint8(100), app.Spec.Deployments[0].RoutingSettings.Weight)
+	require.Equal(t, uint8(0), app.Spec.Deployments[1].RoutingSettings.Weight)
+	require.Equal(t, timeRef(10, 40), app.BlueGreenCleanupTime())
+
+	app.Spec.Strategy.BlueGreen.PreviewCname = "preview.theketch.io"
+	require.Equal(t, "preview.theketch.io", *app.PreviewCname(framework))
+
+	app.CompleteBlueGreen()
+	require.Equal(t, []AppDeploymentSpec{
+		{Version: 2, RoutingSettings: RoutingSettings{Weight: 100}},
+	}, app.Spec.Deployments)
+	require.False(t, app.Spec.Strategy.BlueGreen.Active)
+	require.Nil(t, app.PreviewCname(framework))
+	require.Nil(t, app.BlueGreenCleanupTime())
+	require.Equal(t, ErrBlueGreenNotActive, app.SwitchBlueGreen(*timeRef(10, 50)))
+}
+
+func TestApp_EnvFrom(t *testing.T) {
+	settings := v1.EnvFromSource{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}}
+	credentials := v1.EnvFromSource{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}}}
+	app := &App{
+		Spec: AppSpec{
+			Env: []Env{
+				{Name: "LOG_LEVEL", Value: "debug"},
+				{Name: "DB_PASSWORD", ValueFrom: &EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "go-app-env"}, Key: "DB_PASSWORD"}}},
+			},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Processes: []ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+				{Version: 2, Processes: []ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+			},
+		},
+	}
+	require.Nil(t, app.AddEnvFrom("", settings))
+	require.Nil(t, app.AddEnvFrom("worker", credentials))
+	require.Equal(t, ErrProcessNotFound, app.AddEnvFrom("scheduler", settings))
+
+	prefixed := *settings.DeepCopy()
+	prefixed.Prefix = "CONFIG_"
+	require.Nil(t, app.AddEnvFrom("", prefixed))
+	require.Equal(t, []v1.EnvFromSource{prefixed}, app.Spec.EnvFrom)
+	for _, deployment := range app.Spec.Deployments {
+		require.Nil(t, deployment.Processes[0].EnvFrom)
+		require.Equal(t, []v1.EnvFromSource{credentials}, deployment.Processes[1].EnvFrom)
+	}
+	require.Equal(t, []string{"settings"}, app.ConfigMapNames())
+	require.Equal(t, []string{"credentials", "go-app-env"}, app.SecretNames())
+
+	require.Nil(t, app.RemoveEnvFrom("", settings))
+	require.Nil(t, app.RemoveEnvFrom("worker", credentials))
+	require.Nil(t, app.Spec.EnvFrom)
+	require.Nil(t, app.Spec.Deployments[0].Processes[1].EnvFrom)
+	require.Empty(t, app.ConfigMapNames())
+	require.Equal(t, []string{"go-app-env"}, app.SecretNames())
+}
+
+func TestApp_Volumes(t *testing.T) {
+	app := &App{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-app"},
+		Spec: AppSpec{
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Processes: []ProcessSpec{{Name: "web"}, {Name: "worker"}}},
+			},
+		},
+	}
+	uploads := Volume{Name: "uploads", Claim: &VolumeClaimSpec{}}
+	cache := Volume{Name: "cache", EmptyDir: &v1.EmptyDirVolumeSource{}}
+
+	require.Equal(t, ErrInvalidVolumeSource, app.AttachVolume(Volume{Name: "empty"}, v1.VolumeMount{MountPath: "/empty"}, ""))
+	require.Equal(t, ErrProcessNotFound, app.AttachVolume(cache, v1.VolumeMount{MountPath: "/cache"}, "scheduler"))
+	require.Nil(t, app.AttachVolume(uploads, v1.VolumeMount{MountPath: "/uploads"}, "web"))
+	require.Nil(t, app.AttachVolume(cache, v1.VolumeMount{MountPath: "/cache"}, ""))
+	require.Equal(t, []v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}, {Name: "cache", MountPath: "/cache"}}, app.Spec.Deployments[0].Processes[0].VolumeMounts)
+	require.Equal(t, []v1.VolumeMount{{Name: "cache", MountPath: "/cache"}}, app.Spec.Deployments[0].Processes[1].VolumeMounts)
+	require.Equal(t, []v1.Volume{
+		{Name: "uploads", VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: "go-app-uploads"}}},
+		{Name: "cache", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+	}, app.PodVolumes())
+
+	require.Equal(t, ErrVolumeNotFound, app.DetachVolume("tmp", ""))
+	require.Nil(t, app.DetachVolume("cache", "worker"))
+	require.Len(t, app.Spec.Volumes, 2)
+	require.Nil(t, app.Spec.Deployments[0].Processes[1].VolumeMounts)
+	require.Nil(t, app.DetachVolume("cache", "web"))
+	require.Equal(t, []Volume{uploads}, app.Spec.Volumes)
+	require.Nil(t, app.DetachVolume("uploads", ""))
+	require.Empty(t, app.Spec.Volumes)
+	require.Nil(t, app.PodVolumes())
+
+	// a volume mounted by a sidecar only is kept
+	require.Nil(t, app.AttachVolume(cache, v1.VolumeMount{MountPath: "/cache"}, "web"))
+	app.Spec.Deployments[0].Processes[0].Sidecars = []Container{{Name: "proxy", VolumeMounts: []v1.VolumeMount{{Name: "cache", MountPath: "/var/cache"}}}}
+	require.Nil(t, app.DetachVolume("cache", "web"))
+	require.Equal(t, []Volume{cache}, app.Spec.Volumes)
+}
+
+func TestApp_RecordRevision(t *testing.T) {
+	timeRef := func(hours int, minutes int) *metav1.Time {
+		t := metav1.Date(2021, 2, 1, hours, minutes, 0, 0, time.UTC)
+		return &t
+	}
+	limit := 2
+	app := &App{
+		Spec: AppSpec{
+			RevisionHistoryLimit: &limit,
+			Env:                  []Env{{Name: "LOG_LEVEL", Value: "info"}},
+			Deployments: []AppDeploymentSpec{
+				{Version: 1, Image: "go-app:v1", RoutingSettings: RoutingSettings{Weight: 100}},
+			},
+		},
+	}
+	app.RecordRevision(*timeRef(10, 0))
+	require.Equal(t, []AppRevision{
+		{Deployment: app.Spec.Deployments[0], DeployedAt: *timeRef(10, 0), Env: []Env{{Name: "LOG_LEVEL", Value: "info"}}},
+	}, app.Status.Revisions)
+
+	// a recorded deployment is updated but keeps the time it was rolled out
+	units := 3
+	app.Spec.Deployments[0].Processes = []ProcessSpec{{Name: "web", Units: &units}}
+	app.Spec.Env = []Env{{Name: "LOG_LEVEL", Value: "debug"}}
+	app.RecordRevision(*timeRef(10, 10))
+	require.Len(t, app.Status.Revisions, 1)
+	require.Equal(t, &units, app.Revision(1).Deployment.Processes[0].Units)
+	require.Equal(t, []Env{{Name: "LOG_LEVEL", Value: "debug"}}, app.Revision(1).Env)
+	require.Equal(t, *timeRef(10, 0), app.Revision(1).DeployedAt)
+
+	// steps of a canary deployment aren't recorded
+	app.Spec.Canary.Active = true
+	app.Spec.Deployments[0].RoutingSettings.Weight = 50
+	app.Spec.Deployments = append(app.Spec.Deployments, AppDeploymentSpec{Version: 2, Image: "go-app:v2", RoutingSettings: RoutingSettings{Weight: 50}})
+	app.RecordRevision(*timeRef(10, 15))
+	require.Nil(t, app.Revision(2))
+
+	// an aborted canary deployment isn't recorded
+	app.Spec.Canary.Active = false
+	app.Spec.Deployments = []AppDeploymentSpec{{Version: 1, Image: "go-app:v1", RoutingSettings: RoutingSettings{Weight: 100}}}
+	app.RecordRevision(*timeRef(10, 16))
+	require.Len(t, app.Status.Revisions, 1)
+
+	app.Spec.Deployments = []AppDeploymentSpec{{Version: 2, Image: "go-app:v2", RoutingSettings: RoutingSettings{Weight: 100}}}
+	app.RecordRevision(*timeRef(10, 20))
+	app.Spec.Deployments = []AppDeploymentSpec{{Version: 3, Image: "go-app:v3", RoutingSettings: RoutingSettings{Weight: 100}}}
+	app.RecordRevision(*timeRef(10, 30))
+	require.Len(t, app.Status.Revisions, 2)
+	require.Nil(t, app.Revision(1))
+	require.Equal(t, "go-app:v2", app.Revision(2).Deployment.Image)
+	require.Equal(t, "go-app:v3", app.Revision(3).Deployment.Image)
+}
+
+func TestApp_RunJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		app     App
+		wantRun int
+		wantErr error
+	}{
+		{
+			name: "first run",
+			app: App{Spec: AppSpec{
+				Type:        JobAppType,
+				Deployments: []AppDeploymentSpec{{Version: 1}},
+			}},
+			wantRun: 1,
+		},
+		{
+			name: "next run",
+			app: App{Spec: AppSpec{
+				Type:        JobAppType,
+				Job:         &JobSpec{Schedule: "@daily", Run: 4},
+				Deployments: []AppDeploymentSpec{{Version: 1}},
+			}},
+			wantRun: 5,
+		},
+		{
+			name:    "not deployed",
+			app:     App{Spec: AppSpec{Type: JobAppType}},
+			wantErr: ErrDeploymentNotFound,
+		},
+		{
+			name: "not a job",
+			app: App{Spec: AppSpec{
+				Deployments: []AppDeploymentSpec{{Version: 1}},
+			}},
+			wantErr: ErrNotJob,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.app.RunJob()
+			if tt.wantErr != nil {
+				require.Equal(t, tt.wantErr, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantRun, tt.app.Spec.Job.Run)
+		})
+	}
+}
+
+func TestValidateRouteMatches(t *testing.T) {
+	header := RouteMatch{Type: RouteMatchHeader, Name: "X-Canary", Value: "true"}
+	cookie := RouteMatch{Type: RouteMatchCookie, Name: "canary", Value: "always"}
+	require.Nil(t, ValidateRouteMatches([]RouteMatch{header}, NginxIngressControllerType))
+	require.Nil(t, ValidateRouteMatches([]RouteMatch{header, cookie}, IstioIngressControllerType))
+	require.Equal(t, ErrMultipleRouteMatches, ValidateRouteMatches([]RouteMatch{header, cookie}, NginxIngressControllerType))
+}
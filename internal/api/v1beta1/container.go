@@ -0,0 +1,75 @@
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// Container is a sidecar or an init container of a process.
+// It runs in every unit of the process and shares the unit's network and the application's volumes.
+type Container struct {
+	// Name of the container, it must be unique within the process' units
+	// and differ from the name of the process' container, "<app name>-<process name>-<deployment version>".
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Image of the container.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// Command is an entrypoint of the container. The image's entrypoint is used if not set.
+	Command []string `json:"command,omitempty"`
+
+	// Args are arguments of the entrypoint. The image's cmd is used if not set.
+	Args []string `json:"args,omitempty"`
+
+	// Env is a list of environment variables to set in the container.
+	Env []Env `json:"env,omitempty"`
+
+	// EnvFrom is a list of ConfigMaps and Secrets in the framework's namespace,
+	// all their keys are set as environment variables of the container.
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Ports are ports the container listens on.
+	Ports []v1.ContainerPort `json:"ports,omitempty"`
+
+	// VolumeMounts is a list of the application's volumes mounted to the container.
+	VolumeMounts []v1.VolumeMount `json:"volumeMounts,omitempty"`
+
+	// Resources are CPU and memory requests and limits of the container.
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Security options the container should run with.
+	SecurityContext *v1.SecurityContext `json:"securityContext,omitempty"`
+}
+
+// ValidateContainers returns an error if names of the process' sidecars and init containers are not unique
+// or if one of them is named like the process' own container, "<app name>-<process name>-<deployment version>".
+func (p ProcessSpec) ValidateContainers(appName string) error {
+	prefix := fmt.Sprintf("%s-%s-", appName, p.Name)
+	names := make(map[string]bool, len(p.Sidecars)+len(p.InitContainers))
+	for _, container := range append(append([]Container{}, p.Sidecars...), p.InitContainers...) {
+		if names[container.Name] {
+			return ErrDuplicateContainerName
+		}
+		if version := strings.TrimPrefix(container.Name, prefix); version != container.Name {
+			if _, err := strconv.Atoi(version); err == nil {
+				return ErrReservedContainerName
+			}
+		}
+		names[container.Name] = true
+	}
+	return nil
+}
+
+// ContainerVolumeMounts returns volume mounts of the process' container, sidecars and init containers.
+func (p ProcessSpec) ContainerVolumeMounts() []v1.VolumeMount {
+	mounts := append([]v1.VolumeMount{}, p.VolumeMounts...)
+	for _, container := range append(append([]Container{}, p.Sidecars...), p.InitContainers...) {
+		mounts = append(mounts, container.VolumeMounts...)
+	}
+	return mounts
+}
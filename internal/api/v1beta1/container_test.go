@@ -0,0 +1,72 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestProcessSpec_ValidateContainers(t *testing.T) {
+	tests := []struct {
+		name    string
+		process ProcessSpec
+		wantErr error
+	}{
+		{
+			name: "unique names",
+			process: ProcessSpec{
+				Sidecars:       []Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}, {Name: "fluentd", Image: "fluentd:v1.14"}},
+				InitContainers: []Container{{Name: "migrate", Image: "go-app:v1"}},
+			},
+		},
+		{
+			name: "duplicate sidecars",
+			process: ProcessSpec{
+				Sidecars: []Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}, {Name: "proxy", Image: "nginx:1.21"}},
+			},
+			wantErr: ErrDuplicateContainerName,
+		},
+		{
+			name: "sidecar and init container with the same name",
+			process: ProcessSpec{
+				Sidecars:       []Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}},
+				InitContainers: []Container{{Name: "proxy", Image: "busybox:1.34"}},
+			},
+			wantErr: ErrDuplicateContainerName,
+		},
+		{
+			name: "sidecar named like the process' container",
+			process: ProcessSpec{
+				Name:     "web",
+				Sidecars: []Container{{Name: "go-app-web-3", Image: "envoyproxy/envoy:v1.20.0"}},
+			},
+			wantErr: ErrReservedContainerName,
+		},
+		{
+			name: "sidecar named like the app and the process",
+			process: ProcessSpec{
+				Name:     "web",
+				Sidecars: []Container{{Name: "go-app-web-proxy", Image: "envoyproxy/envoy:v1.20.0"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, tt.process.ValidateContainers("go-app"))
+		})
+	}
+}
+
+func TestProcessSpec_ContainerVolumeMounts(t *testing.T) {
+	process := ProcessSpec{
+		VolumeMounts:   []v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}},
+		Sidecars:       []Container{{Name: "proxy", VolumeMounts: []v1.VolumeMount{{Name: "certs", MountPath: "/certs"}}}},
+		InitContainers: []Container{{Name: "fetch", VolumeMounts: []v1.VolumeMount{{Name: "uploads", MountPath: "/data"}}}},
+	}
+	require.Equal(t, []v1.VolumeMount{
+		{Name: "uploads", MountPath: "/uploads"},
+		{Name: "certs", MountPath: "/certs"},
+		{Name: "uploads", MountPath: "/data"},
+	}, process.ContainerVolumeMounts())
+}
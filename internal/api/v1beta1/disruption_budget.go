@@ -0,0 +1,44 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DisruptionBudgetSpec limits how many units of a process can be evicted at once, for example by a node drain.
+// Exactly one of MinAvailable and MaxUnavailable must be set.
+type DisruptionBudgetSpec struct {
+	// MinAvailable is a number or a percentage of units that must stay available during an eviction.
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// MaxUnavailable is a number or a percentage of units that can be unavailable during an eviction.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// Validate returns an error if the budget doesn't have exactly one of MinAvailable and MaxUnavailable.
+func (b DisruptionBudgetSpec) Validate() error {
+	if (b.MinAvailable == nil) == (b.MaxUnavailable == nil) {
+		return ErrInvalidDisruptionBudget
+	}
+	return nil
+}
+
+// EffectiveDisruptionBudget returns the disruption budget of the process.
+// A process without a budget running more than one unit, or autoscaled up to more than one unit,
+// gets a default budget allowing to evict one unit at a time. Nil is returned if the process needs no budget.
+func (p ProcessSpec) EffectiveDisruptionBudget() *DisruptionBudgetSpec {
+	if p.DisruptionBudget != nil {
+		return p.DisruptionBudget.DeepCopy()
+	}
+	units := DefaultNumberOfUnits
+	if p.Units != nil {
+		units = *p.Units
+	}
+	if p.Autoscaling != nil {
+		units = p.Autoscaling.MaxReplicas
+	}
+	if units <= 1 {
+		return nil
+	}
+	maxUnavailable := intstr.FromInt(1)
+	return &DisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+}
@@ -0,0 +1,81 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestDisruptionBudgetSpec_Validate(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	tests := []struct {
+		name    string
+		budget  DisruptionBudgetSpec
+		wantErr error
+	}{
+		{
+			name:   "min available",
+			budget: DisruptionBudgetSpec{MinAvailable: &half},
+		},
+		{
+			name:   "max unavailable",
+			budget: DisruptionBudgetSpec{MaxUnavailable: &one},
+		},
+		{
+			name:    "empty budget",
+			budget:  DisruptionBudgetSpec{},
+			wantErr: ErrInvalidDisruptionBudget,
+		},
+		{
+			name:    "both fields",
+			budget:  DisruptionBudgetSpec{MinAvailable: &half, MaxUnavailable: &one},
+			wantErr: ErrInvalidDisruptionBudget,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, tt.budget.Validate())
+		})
+	}
+}
+
+func TestProcessSpec_EffectiveDisruptionBudget(t *testing.T) {
+	one := intstr.FromInt(1)
+	half := intstr.FromString("50%")
+	tests := []struct {
+		name    string
+		process ProcessSpec
+		want    *DisruptionBudgetSpec
+	}{
+		{
+			name:    "explicit budget of a single unit",
+			process: ProcessSpec{Units: intRef(1), DisruptionBudget: &DisruptionBudgetSpec{MinAvailable: &half}},
+			want:    &DisruptionBudgetSpec{MinAvailable: &half},
+		},
+		{
+			name:    "default number of units",
+			process: ProcessSpec{},
+		},
+		{
+			name:    "multiple units",
+			process: ProcessSpec{Units: intRef(3)},
+			want:    &DisruptionBudgetSpec{MaxUnavailable: &one},
+		},
+		{
+			name:    "autoscaled process",
+			process: ProcessSpec{Units: intRef(1), Autoscaling: &AutoscalingSpec{MinReplicas: 1, MaxReplicas: 5}},
+			want:    &DisruptionBudgetSpec{MaxUnavailable: &one},
+		},
+		{
+			name:    "process without units",
+			process: ProcessSpec{Units: intRef(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.process.EffectiveDisruptionBudget())
+		})
+	}
+}
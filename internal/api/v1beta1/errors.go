@@ -23,4 +23,61 @@ const (
 
 	// ErrDecreaseQuota is returned when a new quota is too small.
 	ErrDecreaseQuota Error = "failed to decrease quota because the framework has more running apps than the new quota permits"
+
+	// ErrQuotaWithoutDefaultResources is returned when a framework's quota limits CPU or memory but the framework has no default resources for them.
+	ErrQuotaWithoutDefaultResources Error = "resource quota of cpu or memory requires default resources with cpu or memory of the framework"
+
+	// ErrCanaryNotActive is returned when an operation can not be completed because the app has no active canary deployment.
+	ErrCanaryNotActive Error = "canary deployment is not active"
+
+	// ErrCanaryPaused is returned when a canary deployment can not be paused because it is already paused.
+	ErrCanaryPaused Error = "canary deployment is already paused"
+
+	// ErrCanaryNotPaused is returned when a canary deployment can not be resumed because it is not paused.
+	ErrCanaryNotPaused Error = "canary deployment is not paused"
+
+	// ErrBlueGreenNotActive is returned when an operation can not be completed because the app has no active blue-green deployment.
+	ErrBlueGreenNotActive Error = "blue-green deployment is not active"
+
+	// ErrRevisionNotFound is returned when an operation can not be completed because the app's history has no such deployment.
+	ErrRevisionNotFound Error = "revision not found"
+
+	// ErrDeploymentInProgress is returned when an operation can not be completed because the app has an active canary or blue-green deployment.
+	ErrDeploymentInProgress Error = "app has an active canary or blue-green deployment"
+
+	// ErrNotJob is returned when an operation can not be completed because the app is not a Job application.
+	ErrNotJob Error = "app is not a job"
+
+	// ErrVolumeNotFound is returned when an operation can not be completed because the app has no such volume.
+	ErrVolumeNotFound Error = "volume not found"
+
+	// ErrInvalidVolumeSource is returned when a volume doesn't have exactly one source.
+	ErrInvalidVolumeSource Error = "volume must have exactly one source"
+
+	// ErrInvalidProbeHandler is returned when a probe doesn't have exactly one handler.
+	ErrInvalidProbeHandler Error = "probe must have exactly one of exec, httpGet, tcpSocket and grpc"
+
+	// ErrInvalidProbeSuccessThreshold is returned when a liveness or startup probe has a success threshold other than 1.
+	ErrInvalidProbeSuccessThreshold Error = "success threshold of liveness and startup probes must be 1"
+
+	// ErrDuplicateContainerName is returned when sidecars and init containers of a process don't have unique names.
+	ErrDuplicateContainerName Error = "names of sidecars and init containers of a process must be unique"
+
+	// ErrReservedContainerName is returned when a sidecar or an init container is named like the container of its process.
+	ErrReservedContainerName Error = "names of sidecars and init containers must differ from the name of the process' container, <app name>-<process name>-<deployment version>"
+
+	// ErrInvalidDisruptionBudget is returned when a disruption budget doesn't have exactly one of minAvailable and maxUnavailable.
+	ErrInvalidDisruptionBudget Error = "disruption budget must have exactly one of minAvailable and maxUnavailable"
+
+	// ErrMultipleRouteMatches is returned when a deployment has more than one route match rule on a framework with the nginx ingress controller type.
+	ErrMultipleRouteMatches Error = "nginx ingress controller supports only one canary header or cookie rule"
+
+	// ErrTemplatesNotFound is returned when a framework refers to a configmap with custom templates that doesn't exist.
+	ErrTemplatesNotFound Error = "templates configmap not found"
+
+	// ErrTemplatesOfAnotherIngressType is returned when a framework keeps its custom templates while its ingress controller type changes.
+	ErrTemplatesOfAnotherIngressType Error = "custom templates are written for another ingress controller type, remove or replace them when changing the ingress type"
+
+	// ErrGatewayRequired is returned when a framework with the gateway-api ingress controller type doesn't name a Gateway.
+	ErrGatewayRequired Error = "gateway-api ingress controller requires a gateway"
 )
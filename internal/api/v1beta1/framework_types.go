@@ -18,7 +18,10 @@ package v1beta1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/shipa-corp/ketch/internal/templates"
 )
 
 func init() {
@@ -62,6 +65,51 @@ type FrameworkSpec struct {
 	AppQuotaLimit *int `json:"appQuotaLimit"`
 
 	IngressController IngressControllerSpec `json:"ingressController,omitempty"`
+
+	// DefaultResources are CPU and memory requests and limits of containers of processes which don't specify their own resources.
+	// They are also enforced with a LimitRange for every container in the framework's namespace.
+	DefaultResources *v1.ResourceRequirements `json:"defaultResources,omitempty"`
+
+	// ResourceQuota limits the total amount of resources consumed by all apps of the framework.
+	// A quota of CPU or memory requires DefaultResources with CPU or memory, see ValidateResourceQuota.
+	ResourceQuota *ResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// DefaultScheduling is applied to units of all processes of the framework's apps, for example to pin them to a node pool.
+	// See SchedulingSpec.WithDefaults for how it's combined with the scheduling of a process.
+	DefaultScheduling *SchedulingSpec `json:"defaultScheduling,omitempty"`
+
+	// Templates is a name of a configmap in the ketch-system namespace with custom templates to render helm charts of the framework's apps.
+	// If not set, the default templates of the ingress controller type are used.
+	// The templates are written for one ingress controller type, so they must be removed or replaced when the type changes.
+	Templates string `json:"templates,omitempty"`
+}
+
+// ResourceQuotaSpec contains aggregate limits of a framework's namespace.
+type ResourceQuotaSpec struct {
+	// CPU is the total amount of CPU that can be requested by all pods of the framework.
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the total amount of memory that can be requested by all pods of the framework.
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Pods is the maximum number of pods of the framework.
+	Pods *int `json:"pods,omitempty"`
+}
+
+// Hard returns the limits as a list of resources of a ResourceQuota.
+func (q ResourceQuotaSpec) Hard() v1.ResourceList {
+	hard := v1.ResourceList{}
+	if q.CPU != nil {
+		hard[v1.ResourceRequestsCPU] = *q.CPU
+	}
+	if q.Memory != nil {
+		hard[v1.ResourceRequestsMemory] = *q.Memory
+	}
+	if q.Pods != nil {
+		hard[v1.ResourcePods] = *resource.NewQuantity(int64(*q.Pods), resource.DecimalSI)
+	}
+	return hard
 }
 
 type FrameworkPhase string
@@ -71,7 +119,7 @@ const (
 	FrameworkFailed  FrameworkPhase = "Failed"
 )
 
-// +kubebuilder:validation:Enum=traefik;istio
+// +kubebuilder:validation:Enum=traefik;istio;nginx;gateway-api
 
 // IngressControllerType is a type of an ingress controller for this framework.
 type IngressControllerType string
@@ -81,6 +129,11 @@ func (t IngressControllerType) String() string { return string(t) }
 const (
 	TraefikIngressControllerType IngressControllerType = "traefik"
 	IstioIngressControllerType   IngressControllerType = "istio"
+	NginxIngressControllerType   IngressControllerType = "nginx"
+
+	// GatewayAPIIngressControllerType routes traffic with HTTPRoutes of the Kubernetes Gateway API
+	// attached to a Gateway managed by any implementation of the API.
+	GatewayAPIIngressControllerType IngressControllerType = "gateway-api"
 )
 
 // IngressControllerSpec contains configuration for an ingress controller.
@@ -89,6 +142,63 @@ type IngressControllerSpec struct {
 	ServiceEndpoint string                `json:"serviceEndpoint,omitempty"`
 	IngressType     IngressControllerType `json:"type"`
 	ClusterIssuer   string                `json:"clusterIssuer,omitempty"`
+
+	// Gateway is a Gateway that routes of apps are attached to, it's required by the gateway-api type.
+	Gateway *GatewayReference `json:"gateway,omitempty"`
+}
+
+// GatewayReference is a reference to a Gateway of the Kubernetes Gateway API.
+type GatewayReference struct {
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. If not set, the Gateway is looked up in the framework's namespace.
+	// SSL certificates of apps are stored in this namespace, so listeners of the Gateway can reference them.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Validate returns an error if the ingress controller is misconfigured.
+func (s IngressControllerSpec) Validate() error {
+	if s.IngressType == GatewayAPIIngressControllerType && (s.Gateway == nil || len(s.Gateway.Name) == 0) {
+		return ErrGatewayRequired
+	}
+	return nil
+}
+
+// ValidateResourceQuota checks that containers without their own resources get requests from the framework's default resources
+// for every resource limited by the framework's quota, otherwise the quota rejects pods of these containers.
+func (s FrameworkSpec) ValidateResourceQuota() error {
+	if s.ResourceQuota == nil {
+		return nil
+	}
+	hasDefault := func(name v1.ResourceName) bool {
+		if s.DefaultResources == nil {
+			return false
+		}
+		// a LimitRange uses the default limit as the default request if no default request is set.
+		_, request := s.DefaultResources.Requests[name]
+		_, limit := s.DefaultResources.Limits[name]
+		return request || limit
+	}
+	if s.ResourceQuota.CPU != nil && !hasDefault(v1.ResourceCPU) {
+		return ErrQuotaWithoutDefaultResources
+	}
+	if s.ResourceQuota.Memory != nil && !hasDefault(v1.ResourceMemory) {
+		return ErrQuotaWithoutDefaultResources
+	}
+	return nil
+}
+
+// ValidateTemplatesUpdate checks that custom templates are not kept when the ingress controller type changes,
+// otherwise apps of the framework keep rendering charts for the old ingress controller.
+func (s FrameworkSpec) ValidateTemplatesUpdate(old FrameworkSpec) error {
+	if len(s.Templates) == 0 || s.Templates != old.Templates {
+		return nil
+	}
+	if s.IngressController.IngressType != old.IngressController.IngressType {
+		return ErrTemplatesOfAnotherIngressType
+	}
+	return nil
 }
 
 // FrameworkStatus defines the observed state of Framework
@@ -98,6 +208,9 @@ type FrameworkStatus struct {
 
 	Namespace *v1.ObjectReference `json:"namespace,omitempty"`
 	Apps      []string            `json:"apps,omitempty"`
+
+	// ResourceQuota contains limits and current usage of the framework's resource quota.
+	ResourceQuota *v1.ResourceQuotaStatus `json:"resourceQuota,omitempty"`
 }
 
 func (p *Framework) HasApp(name string) bool {
@@ -108,3 +221,11 @@ func (p *Framework) HasApp(name string) bool {
 	}
 	return false
 }
+
+// TemplatesConfigMapName returns a name of a configmap that contains templates used to render helm charts of the framework's apps.
+func (p *Framework) TemplatesConfigMapName() string {
+	if len(p.Spec.Templates) > 0 {
+		return p.Spec.Templates
+	}
+	return templates.IngressConfigMapName(p.Spec.IngressController.IngressType.String())
+}
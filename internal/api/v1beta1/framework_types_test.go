@@ -2,6 +2,9 @@ package v1beta1
 
 import (
 	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestFramework_HasApp(t *testing.T) {
@@ -37,3 +40,158 @@ func TestFramework_HasApp(t *testing.T) {
 		})
 	}
 }
+
+func TestIngressControllerSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    IngressControllerSpec
+		wantErr error
+	}{
+		{
+			name: "traefik without a gateway",
+			spec: IngressControllerSpec{IngressType: TraefikIngressControllerType},
+		},
+		{
+			name: "gateway-api with a gateway",
+			spec: IngressControllerSpec{IngressType: GatewayAPIIngressControllerType, Gateway: &GatewayReference{Name: "public", Namespace: "gateways"}},
+		},
+		{
+			name:    "gateway-api without a gateway",
+			spec:    IngressControllerSpec{IngressType: GatewayAPIIngressControllerType},
+			wantErr: ErrGatewayRequired,
+		},
+		{
+			name:    "gateway-api with an empty gateway name",
+			spec:    IngressControllerSpec{IngressType: GatewayAPIIngressControllerType, Gateway: &GatewayReference{Namespace: "gateways"}},
+			wantErr: ErrGatewayRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFrameworkSpec_ValidateResourceQuota(t *testing.T) {
+	cpu := resource.MustParse("4")
+	memory := resource.MustParse("8Gi")
+	pods := 20
+	tests := []struct {
+		name    string
+		spec    FrameworkSpec
+		wantErr error
+	}{
+		{
+			name: "no quota",
+		},
+		{
+			name: "pods quota without default resources",
+			spec: FrameworkSpec{ResourceQuota: &ResourceQuotaSpec{Pods: &pods}},
+		},
+		{
+			name:    "cpu quota without default resources",
+			spec:    FrameworkSpec{ResourceQuota: &ResourceQuotaSpec{CPU: &cpu}},
+			wantErr: ErrQuotaWithoutDefaultResources,
+		},
+		{
+			name: "memory quota without default memory",
+			spec: FrameworkSpec{
+				ResourceQuota:    &ResourceQuotaSpec{CPU: &cpu, Memory: &memory},
+				DefaultResources: &v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")}},
+			},
+			wantErr: ErrQuotaWithoutDefaultResources,
+		},
+		{
+			name: "default requests and limits",
+			spec: FrameworkSpec{
+				ResourceQuota: &ResourceQuotaSpec{CPU: &cpu, Memory: &memory},
+				DefaultResources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.ValidateResourceQuota(); err != tt.wantErr {
+				t.Errorf("ValidateResourceQuota() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFrameworkSpec_ValidateTemplatesUpdate(t *testing.T) {
+	istio := IngressControllerSpec{IngressType: IstioIngressControllerType}
+	nginx := IngressControllerSpec{IngressType: NginxIngressControllerType}
+	tests := []struct {
+		name    string
+		spec    FrameworkSpec
+		old     FrameworkSpec
+		wantErr error
+	}{
+		{
+			name: "ingress type changed without custom templates",
+			spec: FrameworkSpec{IngressController: nginx},
+			old:  FrameworkSpec{IngressController: istio},
+		},
+		{
+			name: "custom templates kept with the same ingress type",
+			spec: FrameworkSpec{IngressController: istio, Templates: "framework-gke-templates"},
+			old:  FrameworkSpec{IngressController: istio, Templates: "framework-gke-templates"},
+		},
+		{
+			name: "custom templates replaced with the ingress type",
+			spec: FrameworkSpec{IngressController: nginx, Templates: "nginx-templates"},
+			old:  FrameworkSpec{IngressController: istio, Templates: "framework-gke-templates"},
+		},
+		{
+			name:    "custom templates kept with another ingress type",
+			spec:    FrameworkSpec{IngressController: nginx, Templates: "framework-gke-templates"},
+			old:     FrameworkSpec{IngressController: istio, Templates: "framework-gke-templates"},
+			wantErr: ErrTemplatesOfAnotherIngressType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.spec.ValidateTemplatesUpdate(tt.old); err != tt.wantErr {
+				t.Errorf("ValidateTemplatesUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFramework_TemplatesConfigMapName(t *testing.T) {
+	tests := []struct {
+		name string
+		spec FrameworkSpec
+		want string
+	}{
+		{
+			name: "istio configmap",
+			spec: FrameworkSpec{IngressController: IngressControllerSpec{IngressType: IstioIngressControllerType}},
+			want: "ingress-istio-templates",
+		},
+		{
+			name: "traefik configmap",
+			spec: FrameworkSpec{IngressController: IngressControllerSpec{IngressType: TraefikIngressControllerType}},
+			want: "ingress-traefik-templates",
+		},
+		{
+			name: "custom templates",
+			spec: FrameworkSpec{IngressController: IngressControllerSpec{IngressType: TraefikIngressControllerType}, Templates: "framework-gke-templates"},
+			want: "framework-gke-templates",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Framework{Spec: tt.spec}
+			if got := p.TemplatesConfigMapName(); got != tt.want {
+				t.Errorf("TemplatesConfigMapName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,13 +20,19 @@ import (
 	"context"
 	"fmt"
 
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
+// templatesNamespace is a namespace of configmaps with templates, the same as controllers.KetchNamespace.
+const templatesNamespace = "ketch-system"
+
 // log is for logging in this package.
 var frameworklog = logf.Log.WithName("framework-resource")
 
@@ -59,8 +65,17 @@ var _ webhook.Validator = &Framework{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Framework) ValidateCreate() error {
 	frameworklog.Info("validate create", "name", r.Name)
+	if err := r.Spec.IngressController.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.ValidateResourceQuota(); err != nil {
+		return err
+	}
 	client := frameworkmgr.GetClient()
 	ctx := context.TODO()
+	if err := r.validateTemplatesExist(ctx, client); err != nil {
+		return err
+	}
 	frameworks := FrameworkList{}
 	if err := client.List(ctx, &frameworks); err != nil {
 		return err
@@ -81,8 +96,20 @@ func (r *Framework) ValidateUpdate(old runtime.Object) error {
 	if !ok {
 		return fmt.Errorf("can't validate framework update")
 	}
+	if err := r.Spec.IngressController.Validate(); err != nil {
+		return err
+	}
+	if err := r.Spec.ValidateResourceQuota(); err != nil {
+		return err
+	}
+	if err := r.Spec.ValidateTemplatesUpdate(oldFramework.Spec); err != nil {
+		return err
+	}
 
 	c := frameworkmgr.GetClient()
+	if err := r.validateTemplatesExist(context.Background(), c); err != nil {
+		return err
+	}
 	if oldFramework.Spec.NamespaceName != r.Spec.NamespaceName {
 		if len(r.Status.Apps) > 0 {
 			return ErrChangeNamespaceWhenAppsRunning
@@ -114,3 +141,18 @@ func (r *Framework) ValidateDelete() error {
 	}
 	return nil
 }
+
+// validateTemplatesExist checks that the configmap with custom templates of the framework exists.
+func (r *Framework) validateTemplatesExist(ctx context.Context, c client.Client) error {
+	if len(r.Spec.Templates) == 0 {
+		return nil
+	}
+	cm := v1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: r.Spec.Templates, Namespace: templatesNamespace}, &cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ErrTemplatesNotFound
+		}
+		return err
+	}
+	return nil
+}
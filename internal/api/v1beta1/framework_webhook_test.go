@@ -2,8 +2,12 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -54,6 +58,7 @@ func (m *mockManager) GetClient() client.Client {
 func TestFramework_ValidateCreate(t *testing.T) {
 
 	const listError Error = "error"
+	cpuQuota := resource.MustParse("4")
 
 	tests := []struct {
 		name      string
@@ -89,6 +94,41 @@ func TestFramework_ValidateCreate(t *testing.T) {
 			},
 			wantErr: ErrNamespaceIsUsedByAnotherFramework,
 		},
+		{
+			name: "gateway-api without a gateway",
+			framework: Framework{
+				Spec: FrameworkSpec{
+					NamespaceName:     "theketch-namespace",
+					IngressController: IngressControllerSpec{IngressType: GatewayAPIIngressControllerType},
+				},
+			},
+			wantErr: ErrGatewayRequired,
+		},
+		{
+			name: "quota without default resources",
+			framework: Framework{
+				Spec: FrameworkSpec{
+					NamespaceName: "theketch-namespace",
+					ResourceQuota: &ResourceQuotaSpec{CPU: &cpuQuota},
+				},
+			},
+			wantErr: ErrQuotaWithoutDefaultResources,
+		},
+		{
+			name: "templates not found",
+			client: &mocks.MockClient{
+				OnGet: func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+					return apierrors.NewNotFound(v1.Resource("configmaps"), key.Name)
+				},
+			},
+			framework: Framework{
+				Spec: FrameworkSpec{
+					NamespaceName: "theketch-namespace",
+					Templates:     "framework-gke-templates",
+				},
+			},
+			wantErr: ErrTemplatesNotFound,
+		},
 		{
 			name: "namespace is used",
 			client: &mocks.MockClient{
@@ -212,6 +252,44 @@ func TestFramework_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: ErrDecreaseQuota,
 		},
+		{
+			name: "custom templates kept with another ingress type",
+			framework: Framework{
+				ObjectMeta: metav1.ObjectMeta{Name: "framework-1"},
+				Spec: FrameworkSpec{
+					NamespaceName:     "ketch-namespace",
+					IngressController: IngressControllerSpec{IngressType: NginxIngressControllerType},
+					Templates:         "framework-framework-1-templates",
+				},
+			},
+			old: &Framework{
+				Spec: FrameworkSpec{
+					NamespaceName:     "ketch-namespace",
+					IngressController: IngressControllerSpec{IngressType: IstioIngressControllerType},
+					Templates:         "framework-framework-1-templates",
+				},
+			},
+			wantErr: ErrTemplatesOfAnotherIngressType,
+		},
+		{
+			name: "templates not found",
+			framework: Framework{
+				ObjectMeta: metav1.ObjectMeta{Name: "framework-1"},
+				Spec:       FrameworkSpec{NamespaceName: "ketch-namespace", Templates: "framework-framework-1-templates"},
+			},
+			client: &mocks.MockClient{
+				OnGet: func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+					if key.Namespace != "ketch-system" {
+						return fmt.Errorf("unexpected namespace %s", key.Namespace)
+					}
+					return apierrors.NewNotFound(v1.Resource("configmaps"), key.Name)
+				},
+			},
+			old: &Framework{
+				Spec: FrameworkSpec{NamespaceName: "ketch-namespace"},
+			},
+			wantErr: ErrTemplatesNotFound,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
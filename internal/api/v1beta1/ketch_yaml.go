@@ -1,5 +1,10 @@
 package v1beta1
 
+import (
+	"fmt"
+	"sort"
+)
+
 // KetchYamlData describes certain aspects of the application deployment being deployed.
 type KetchYamlData struct {
 
@@ -21,15 +26,37 @@ type KetchYamlHooks struct {
 
 	// Restart describes commands to run during different stages of the application deployment.
 	Restart KetchYamlRestartHooks `json:"restart,omitempty"`
+
+	// Deploy describes commands to run once per deployment.
+	Deploy KetchYamlDeployHooks `json:"deploy,omitempty"`
+}
+
+// KetchYamlDeployHooks describes commands to run once per deployment in a kubernetes Job with the deployment's image.
+// The Job's pod gets the environment, volumes, resources and scheduling of the deployment's routable process, usually "web".
+type KetchYamlDeployHooks struct {
+
+	// Before contains commands that are executed before units of the deployment start, for example database migrations.
+	// The deployment doesn't roll out if they fail, "ketch app info" shows the failed Job,
+	// deleting the Job runs them again and a new deployment runs its own hooks.
+	Before []string `json:"before,omitempty"`
+
+	// After contains commands that are executed once all units of the deployment are available, for example cache warm-up.
+	After []string `json:"after,omitempty"`
 }
 
 // KetchYamlRestartHooks describes commands to run during different stages of the application deployment.
+//
+// The hooks of the deployment apply to every process without its own restart hooks and run in each unit independently,
+// units of different processes are started and stopped in no particular order.
+// Within a unit, commands run in the listed order and a failing command stops the remaining ones.
 type KetchYamlRestartHooks struct {
 
-	// Before contains commands that are executed before a unit is restarted. Commands listed in this hook run once per unit.
+	// Before contains commands that are executed before a unit is stopped, for example to drain connections.
+	// Commands listed in this hook run once per unit as a preStop handler of the unit's container, the container receives SIGTERM once they exit.
+	// The commands and the shutdown of the container must fit into the process' terminationGracePeriodSeconds, the container is killed after it.
 	Before []string `json:"before,omitempty" bson:",omitempty"`
 
-	// Before contains commands that are executed after a unit is restarted. Commands listed in this hook run once per unit.
+	// After contains commands that are executed after a unit is started. Commands listed in this hook run once per unit as a postStart handler of the unit's container.
 	After []string `json:"after,omitempty" bson:",omitempty"`
 }
 
@@ -49,11 +76,14 @@ type KetchYamlHealthcheck struct {
 	// Headers defines optional additional header names that can be used for the request. Header names must be capitalized.
 	Headers map[string]string `json:"headers,omitempty" bson:",omitempty"`
 
-	// Match is a regular expression to be matched against the request body.
-	// If not set, the body won’t be read and only the status code is checked. This regular expression uses Go syntax and runs with a matching \n (s flag).
+	// Match is an extended regular expression to be matched against the request body with grep -E.
+	// If not set, the body won’t be read and only the status code is checked. It isn't supported with use_in_router set.
+	// A healthcheck with match or with a method other than GET runs curl in a shell of the unit's container until it succeeds once,
+	// so the image must provide sh, curl and grep.
 	Match string `json:"match,omitempty"`
 
 	// If not set, only readiness probe will be created.
+	// Without match and with the GET method, the readiness probe is an httpGet probe, it doesn't require a shell in the image.
 	UseInRouter bool `json:"use_in_router,omitempty"`
 
 	// ForceRestart determines whether a unit should be restarted after allowedFailures encounters consecutive healthcheck failures.
@@ -63,7 +93,7 @@ type KetchYamlHealthcheck struct {
 	// AllowedFailures specifies a number of allowed failures before healthcheck considers the application is unhealthy. The defaults is 0.
 	AllowedFailures int `json:"allowed_failures,omitempty"`
 
-	// IntervalSeconds is an interval in seconds between each active healthcheck call if use_in_router is set to true. The default is 10 seconds.
+	// IntervalSeconds is an interval in seconds between each active healthcheck call of an httpGet probe. The default is 10 seconds.
 	IntervalSeconds int `json:"interval_seconds,omitempty"`
 
 	// TimeoutSeconds is a timeout for each healthcheck call in seconds. The default is 60 seconds.
@@ -80,6 +110,12 @@ type KetchYamlKubernetesConfig struct {
 // KetchYamlKubernetesConfig contains specific configurations of a process.
 type KetchYamlProcessConfig struct {
 	Ports []KetchYamlProcessPortConfig `json:"ports,omitempty"`
+
+	// Probes are health checks of the process' containers, they take precedence over the healthcheck.
+	Probes *ProcessProbes `json:"probes,omitempty"`
+
+	// RestartHooks are restart hooks of the process' containers, they take precedence over the restart hooks of the deployment.
+	RestartHooks *KetchYamlRestartHooks `json:"restartHooks,omitempty"`
 }
 
 // KetchYamlKubernetesConfig contains configuration of an exposed port.
@@ -96,3 +132,25 @@ type KetchYamlProcessPortConfig struct {
 	// TargetPort is the port that the process is listening on. If omitted, the port value is used.
 	TargetPort int `json:"target_port,omitempty"`
 }
+
+// Validate returns an error if probes of a process are invalid.
+func (d KetchYamlData) Validate() error {
+	if d.Kubernetes == nil {
+		return nil
+	}
+	names := make([]string, 0, len(d.Kubernetes.Processes))
+	for name := range d.Kubernetes.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		probes := d.Kubernetes.Processes[name].Probes
+		if probes == nil {
+			continue
+		}
+		if err := probes.Validate(); err != nil {
+			return fmt.Errorf("process %s: %w", name, err)
+		}
+	}
+	return nil
+}
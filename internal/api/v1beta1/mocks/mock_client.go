@@ -8,10 +8,14 @@ import (
 )
 
 type MockClient struct {
+	OnGet  func(ctx context.Context, key client.ObjectKey, obj runtime.Object) error
 	OnList func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error
 }
 
 func (m MockClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if m.OnGet != nil {
+		return m.OnGet(ctx, key, obj)
+	}
 	panic("implement me")
 }
 
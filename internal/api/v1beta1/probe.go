@@ -0,0 +1,104 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// ProcessProbes describes health checks of a process' containers.
+// If set, they take precedence over the deployment's healthcheck.
+type ProcessProbes struct {
+	// Readiness is a probe to check whether a unit is ready to receive traffic.
+	Readiness *Probe `json:"readiness,omitempty"`
+
+	// Liveness is a probe to check whether a unit is alive, a unit is restarted once the probe fails.
+	Liveness *Probe `json:"liveness,omitempty"`
+
+	// Startup is a probe to check whether a unit has started,
+	// readiness and liveness probes are disabled until the startup probe succeeds.
+	Startup *Probe `json:"startup,omitempty"`
+}
+
+// Probe describes a health check of a container, exactly one of Exec, HTTPGet, TCPSocket and GRPC must be set.
+type Probe struct {
+	// Exec runs a command in the container, the probe succeeds if the command exits with 0.
+	Exec *v1.ExecAction `json:"exec,omitempty"`
+
+	// HTTPGet sends an HTTP GET request to the container, the probe succeeds if the response's status code is 2xx or 3xx.
+	HTTPGet *v1.HTTPGetAction `json:"httpGet,omitempty"`
+
+	// TCPSocket opens a TCP connection to the container, the probe succeeds if the connection is established.
+	TCPSocket *v1.TCPSocketAction `json:"tcpSocket,omitempty"`
+
+	// GRPC calls the gRPC health checking protocol of the container, the probe succeeds if the service is serving.
+	// It requires kubernetes 1.24 or newer.
+	GRPC *GRPCAction `json:"grpc,omitempty"`
+
+	// InitialDelaySeconds is a number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// TimeoutSeconds is a number of seconds after which the probe times out. The kubernetes default is 1 second.
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// PeriodSeconds is how often to perform the probe. The kubernetes default is 10 seconds.
+	// +kubebuilder:validation:Minimum=0
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// SuccessThreshold is a number of consecutive successes for the probe to be considered successful after having failed.
+	// It must be 1 for liveness and startup probes.
+	// +kubebuilder:validation:Minimum=0
+	SuccessThreshold int32 `json:"successThreshold,omitempty"`
+
+	// FailureThreshold is a number of consecutive failures for the probe to be considered failed after having succeeded.
+	// The kubernetes default is 3.
+	// +kubebuilder:validation:Minimum=0
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// GRPCAction describes a call of the gRPC health checking protocol.
+type GRPCAction struct {
+	// Port is a number of the port of the gRPC service.
+	// +kubebuilder:validation:Minimum=1
+	Port int32 `json:"port"`
+
+	// Service is a name of the service to check. If not set, the server's overall health is checked.
+	Service *string `json:"service,omitempty"`
+}
+
+// Validate returns an error if the probe doesn't have exactly one handler.
+func (p Probe) Validate() error {
+	handlers := 0
+	if p.Exec != nil {
+		handlers++
+	}
+	if p.HTTPGet != nil {
+		handlers++
+	}
+	if p.TCPSocket != nil {
+		handlers++
+	}
+	if p.GRPC != nil {
+		handlers++
+	}
+	if handlers != 1 {
+		return ErrInvalidProbeHandler
+	}
+	return nil
+}
+
+// Validate returns an error if any of the probes is invalid.
+func (p ProcessProbes) Validate() error {
+	for _, probe := range []*Probe{p.Readiness, p.Liveness, p.Startup} {
+		if probe == nil {
+			continue
+		}
+		if err := probe.Validate(); err != nil {
+			return err
+		}
+	}
+	if p.Liveness != nil && p.Liveness.SuccessThreshold > 1 || p.Startup != nil && p.Startup.SuccessThreshold > 1 {
+		return ErrInvalidProbeSuccessThreshold
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestProcessProbes_Validate(t *testing.T) {
+	tcp := &Probe{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(8080)}}
+	tests := []struct {
+		name    string
+		probes  ProcessProbes
+		wantErr error
+	}{
+		{
+			name: "valid probes",
+			probes: ProcessProbes{
+				Readiness: &Probe{HTTPGet: &v1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080)}, SuccessThreshold: 2},
+				Liveness:  &Probe{GRPC: &GRPCAction{Port: 50051}},
+				Startup:   tcp,
+			},
+		},
+		{
+			name:    "probe without handler",
+			probes:  ProcessProbes{Readiness: &Probe{PeriodSeconds: 5}},
+			wantErr: ErrInvalidProbeHandler,
+		},
+		{
+			name:    "probe with two handlers",
+			probes:  ProcessProbes{Liveness: &Probe{Exec: &v1.ExecAction{Command: []string{"true"}}, TCPSocket: tcp.TCPSocket}},
+			wantErr: ErrInvalidProbeHandler,
+		},
+		{
+			name:    "liveness probe with success threshold",
+			probes:  ProcessProbes{Liveness: &Probe{TCPSocket: tcp.TCPSocket, SuccessThreshold: 2}},
+			wantErr: ErrInvalidProbeSuccessThreshold,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantErr, tt.probes.Validate())
+		})
+	}
+}
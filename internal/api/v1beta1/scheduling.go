@@ -0,0 +1,74 @@
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// SchedulingSpec describes how units of a process are assigned to nodes.
+type SchedulingSpec struct {
+	// NodeSelector is a set of labels a node must have to run a unit.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity contains node affinity and pod affinity and anti-affinity rules of units.
+	Affinity *v1.Affinity `json:"affinity,omitempty"`
+
+	// Tolerations allow units to run on nodes with matching taints, for example spot nodes.
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+
+	// TopologySpreadConstraints describe how units are spread across zones, nodes and other topology domains.
+	// A constraint without a labelSelector spreads units of the process.
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// PriorityClassName is a name of a PriorityClass of units.
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// WithDefaults returns the scheduling settings complemented with the defaults of a framework.
+// Node selectors of both are combined, a process' value takes precedence for the same label.
+// Tolerations of both are combined.
+// Affinity, topology spread constraints and priority class of the defaults are used if the process has none.
+func (s *SchedulingSpec) WithDefaults(defaults *SchedulingSpec) *SchedulingSpec {
+	if defaults == nil {
+		return s.DeepCopy()
+	}
+	if s == nil {
+		return defaults.DeepCopy()
+	}
+	result := s.DeepCopy()
+	if len(defaults.NodeSelector) > 0 {
+		nodeSelector := make(map[string]string, len(defaults.NodeSelector)+len(s.NodeSelector))
+		for key, value := range defaults.NodeSelector {
+			nodeSelector[key] = value
+		}
+		for key, value := range s.NodeSelector {
+			nodeSelector[key] = value
+		}
+		result.NodeSelector = nodeSelector
+	}
+	for _, toleration := range defaults.Tolerations {
+		if !hasToleration(result.Tolerations, toleration) {
+			result.Tolerations = append(result.Tolerations, *toleration.DeepCopy())
+		}
+	}
+	if result.Affinity == nil {
+		result.Affinity = defaults.Affinity.DeepCopy()
+	}
+	if len(result.TopologySpreadConstraints) == 0 {
+		for _, constraint := range defaults.TopologySpreadConstraints {
+			result.TopologySpreadConstraints = append(result.TopologySpreadConstraints, *constraint.DeepCopy())
+		}
+	}
+	if len(result.PriorityClassName) == 0 {
+		result.PriorityClassName = defaults.PriorityClassName
+	}
+	return result
+}
+
+func hasToleration(tolerations []v1.Toleration, toleration v1.Toleration) bool {
+	for _, t := range tolerations {
+		if t.MatchToleration(&toleration) {
+			return true
+		}
+	}
+	return false
+}
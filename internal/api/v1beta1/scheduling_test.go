@@ -0,0 +1,65 @@
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestSchedulingSpec_WithDefaults(t *testing.T) {
+	spot := v1.Toleration{Key: "spot", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}
+	gpu := v1.Toleration{Key: "gpu", Operator: v1.TolerationOpEqual, Value: "true", Effect: v1.TaintEffectNoSchedule}
+	zone := v1.TopologySpreadConstraint{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.ScheduleAnyway}
+	host := v1.TopologySpreadConstraint{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: v1.DoNotSchedule}
+	affinity := &v1.Affinity{NodeAffinity: &v1.NodeAffinity{}}
+	defaults := &SchedulingSpec{
+		NodeSelector:              map[string]string{"pool": "apps", "disktype": "hdd"},
+		Affinity:                  affinity,
+		Tolerations:               []v1.Toleration{spot},
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{zone},
+		PriorityClassName:         "normal",
+	}
+	tests := []struct {
+		name       string
+		scheduling *SchedulingSpec
+		defaults   *SchedulingSpec
+		want       *SchedulingSpec
+	}{
+		{
+			name: "no scheduling",
+		},
+		{
+			name:     "defaults only",
+			defaults: defaults,
+			want:     defaults,
+		},
+		{
+			name:       "process scheduling only",
+			scheduling: &SchedulingSpec{PriorityClassName: "high"},
+			want:       &SchedulingSpec{PriorityClassName: "high"},
+		},
+		{
+			name: "process scheduling takes precedence",
+			scheduling: &SchedulingSpec{
+				NodeSelector:              map[string]string{"disktype": "ssd"},
+				Tolerations:               []v1.Toleration{gpu, spot},
+				TopologySpreadConstraints: []v1.TopologySpreadConstraint{host},
+				PriorityClassName:         "high",
+			},
+			defaults: defaults,
+			want: &SchedulingSpec{
+				NodeSelector:              map[string]string{"pool": "apps", "disktype": "ssd"},
+				Affinity:                  affinity,
+				Tolerations:               []v1.Toleration{gpu, spot},
+				TopologySpreadConstraints: []v1.TopologySpreadConstraint{host},
+				PriorityClassName:         "high",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.scheduling.WithDefaults(tt.defaults))
+		})
+	}
+}
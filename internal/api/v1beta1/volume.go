@@ -0,0 +1,91 @@
+package v1beta1
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Volume is a volume of an application, processes mount it with VolumeMounts.
+// Exactly one of Claim, PersistentVolumeClaim, ConfigMap, Secret and EmptyDir must be set.
+type Volume struct {
+	// Name of the volume, it must be unique within the application.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Claim if set, ketch creates a PersistentVolumeClaim for the volume in the framework's namespace.
+	// The claim is kept when the volume is detached or the application is removed.
+	Claim *VolumeClaimSpec `json:"claim,omitempty"`
+
+	// PersistentVolumeClaim references an existing PersistentVolumeClaim in the framework's namespace.
+	PersistentVolumeClaim *v1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// ConfigMap references a ConfigMap in the framework's namespace, its keys are mounted as files.
+	ConfigMap *v1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+
+	// Secret references a Secret in the framework's namespace, its keys are mounted as files.
+	Secret *v1.SecretVolumeSource `json:"secret,omitempty"`
+
+	// EmptyDir is a temporary directory that shares a pod's lifetime.
+	EmptyDir *v1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+}
+
+// VolumeClaimSpec describes a PersistentVolumeClaim created by ketch.
+type VolumeClaimSpec struct {
+	// StorageClassName is a name of a StorageClass used to provision the volume.
+	// If not set, the cluster's default StorageClass is used.
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the requested size of the volume.
+	Size resource.Quantity `json:"size"`
+
+	// AccessModes are the desired access modes of the volume, ReadWriteOnce by default.
+	AccessModes []v1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// ClaimName returns the name of a PersistentVolumeClaim created by ketch for the volume.
+func (v Volume) ClaimName(appName string) string {
+	return fmt.Sprintf("%s-%s", appName, v.Name)
+}
+
+// Validate returns an error if the volume doesn't have exactly one source.
+func (v Volume) Validate() error {
+	sources := 0
+	if v.Claim != nil {
+		sources++
+	}
+	if v.PersistentVolumeClaim != nil {
+		sources++
+	}
+	if v.ConfigMap != nil {
+		sources++
+	}
+	if v.Secret != nil {
+		sources++
+	}
+	if v.EmptyDir != nil {
+		sources++
+	}
+	if sources != 1 {
+		return ErrInvalidVolumeSource
+	}
+	return nil
+}
+
+// PodVolume returns a kubernetes volume to be added to pods of the application.
+func (v Volume) PodVolume(appName string) v1.Volume {
+	volume := v1.Volume{
+		Name: v.Name,
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: v.PersistentVolumeClaim,
+			ConfigMap:             v.ConfigMap,
+			Secret:                v.Secret,
+			EmptyDir:              v.EmptyDir,
+		},
+	}
+	if v.Claim != nil {
+		volume.PersistentVolumeClaim = &v1.PersistentVolumeClaimVolumeSource{ClaimName: v.ClaimName(appName)}
+	}
+	return volume
+}
@@ -0,0 +1,136 @@
+package canary
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+	"time"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
+)
+
+// defaultQueries contains query templates used for each ingress controller if an analysis doesn't override them.
+// There are no default queries for the gateway-api ingress controller type because metrics depend on the implementation of the API.
+var defaultQueries = map[ketchv1.IngressControllerType]ketchv1.CanaryQueries{
+	ketchv1.IstioIngressControllerType: {
+		SuccessRate: `sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="{{ .Namespace }}",destination_workload="{{ .Deployment }}",response_code!~"5.*"}[{{ .Interval }}])) / sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="{{ .Namespace }}",destination_workload="{{ .Deployment }}"}[{{ .Interval }}])) * 100`,
+		Latency:     `histogram_quantile(0.99, sum(rate(istio_request_duration_milliseconds_bucket{reporter="destination",destination_workload_namespace="{{ .Namespace }}",destination_workload="{{ .Deployment }}"}[{{ .Interval }}])) by (le))`,
+	},
+	ketchv1.TraefikIngressControllerType: {
+		SuccessRate: `sum(rate(traefik_service_requests_total{service=~"{{ .Namespace }}-{{ .Deployment }}-.*",code!~"5.."}[{{ .Interval }}])) / sum(rate(traefik_service_requests_total{service=~"{{ .Namespace }}-{{ .Deployment }}-.*"}[{{ .Interval }}])) * 100`,
+		Latency:     `histogram_quantile(0.99, sum(rate(traefik_service_request_duration_seconds_bucket{service=~"{{ .Namespace }}-{{ .Deployment }}-.*"}[{{ .Interval }}])) by (le)) * 1000`,
+	},
+	ketchv1.NginxIngressControllerType: {
+		SuccessRate: `sum(rate(nginx_ingress_controller_requests{namespace="{{ .Namespace }}",service="{{ .Deployment }}",status!~"5.."}[{{ .Interval }}])) / sum(rate(nginx_ingress_controller_requests{namespace="{{ .Namespace }}",service="{{ .Deployment }}"}[{{ .Interval }}])) * 100`,
+		Latency:     `histogram_quantile(0.99, sum(rate(nginx_ingress_controller_request_duration_seconds_bucket{namespace="{{ .Namespace }}",service="{{ .Deployment }}"}[{{ .Interval }}])) by (le)) * 1000`,
+	},
+}
+
+// QueryParams contains values available in query templates.
+type QueryParams struct {
+	App        string
+	Namespace  string
+	Process    string
+	Version    string
+	Deployment string
+	Interval   string
+}
+
+// NewQueryParams returns QueryParams describing the canary deployment of the given app.
+func NewQueryParams(app ketchv1.App, framework ketchv1.Framework) (*QueryParams, error) {
+	if len(app.Spec.Deployments) <= 1 {
+		return nil, fmt.Errorf("no canary deployment found")
+	}
+	canary := app.Spec.Deployments[1]
+	procfile, err := chart.ProcfileFromProcesses(canary.Processes)
+	if err != nil {
+		return nil, err
+	}
+	return &QueryParams{
+		App:        app.Name,
+		Namespace:  framework.Spec.NamespaceName,
+		Process:    procfile.RoutableProcessName,
+		Version:    canary.Version.String(),
+		Deployment: fmt.Sprintf("%s-%s-%v", app.Name, procfile.RoutableProcessName, canary.Version),
+		Interval:   promDuration(app.Spec.Canary.StepTimeInteval),
+	}, nil
+}
+
+// promDuration converts the duration to Prometheus format.
+func promDuration(d time.Duration) string {
+	seconds := int64(d.Seconds())
+	if seconds < 60 {
+		// rate() needs at least two samples within the range to return a value.
+		seconds = 60
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// Analyze evaluates the analysis rules against the metrics of the canary deployment.
+// It returns nil if all rules pass, otherwise the returned error describes the first failed rule.
+func Analyze(ctx context.Context, q Querier, analysis ketchv1.CanaryAnalysis, ingressType ketchv1.IngressControllerType, params QueryParams) error {
+	queries := analysisQueries(analysis, ingressType)
+	if analysis.SuccessRate != nil {
+		value, err := runQuery(ctx, q, "success rate", queries.SuccessRate, params)
+		if err != nil {
+			return err
+		}
+		if value < float64(*analysis.SuccessRate) {
+			return fmt.Errorf("success rate %.2f%% is below the threshold of %d%%", value, *analysis.SuccessRate)
+		}
+	}
+	if analysis.MaxLatency != nil {
+		value, err := runQuery(ctx, q, "latency", queries.Latency, params)
+		if err != nil {
+			return err
+		}
+		if value > float64(*analysis.MaxLatency) {
+			return fmt.Errorf("latency %.0fms is above the threshold of %dms", value, *analysis.MaxLatency)
+		}
+	}
+	return nil
+}
+
+// ValidateQueries returns an error if a rule of the analysis has neither a query of the analysis nor a default query of the ingress controller.
+func ValidateQueries(analysis ketchv1.CanaryAnalysis, ingressType ketchv1.IngressControllerType) error {
+	queries := analysisQueries(analysis, ingressType)
+	if analysis.SuccessRate != nil && len(queries.SuccessRate) == 0 {
+		return fmt.Errorf("success rate query is not defined, %s ingress controller has no default queries", ingressType)
+	}
+	if analysis.MaxLatency != nil && len(queries.Latency) == 0 {
+		return fmt.Errorf("latency query is not defined, %s ingress controller has no default queries", ingressType)
+	}
+	return nil
+}
+
+func analysisQueries(analysis ketchv1.CanaryAnalysis, ingressType ketchv1.IngressControllerType) ketchv1.CanaryQueries {
+	queries := defaultQueries[ingressType]
+	if len(analysis.Queries.SuccessRate) > 0 {
+		queries.SuccessRate = analysis.Queries.SuccessRate
+	}
+	if len(analysis.Queries.Latency) > 0 {
+		queries.Latency = analysis.Queries.Latency
+	}
+	return queries
+}
+
+func runQuery(ctx context.Context, q Querier, name string, queryTemplate string, params QueryParams) (float64, error) {
+	if len(queryTemplate) == 0 {
+		return 0, fmt.Errorf("%s query is not defined", name)
+	}
+	t, err := template.New(name).Parse(queryTemplate)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s query: %w", name, err)
+	}
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, params); err != nil {
+		return 0, fmt.Errorf("failed to render %s query: %w", name, err)
+	}
+	value, err := q.Query(ctx, buf.String())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get %s: %w", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,194 @@
+package canary
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+type fakeQuerier struct {
+	values  map[string]float64
+	queries []string
+}
+
+func (f *fakeQuerier) Query(ctx context.Context, query string) (float64, error) {
+	f.queries = append(f.queries, query)
+	for prefix, value := range f.values {
+		if strings.HasPrefix(query, prefix) {
+			return value, nil
+		}
+	}
+	return 0, ErrNoData
+}
+
+func intRef(i int) *int {
+	return &i
+}
+
+func TestAnalyze(t *testing.T) {
+	params := QueryParams{
+		App:        "go-app",
+		Namespace:  "ketch-gke",
+		Process:    "web",
+		Version:    "3",
+		Deployment: "go-app-web-3",
+		Interval:   "60s",
+	}
+	tests := []struct {
+		name        string
+		analysis    ketchv1.CanaryAnalysis
+		ingressType ketchv1.IngressControllerType
+		values      map[string]float64
+		wantQueries []string
+		wantErr     string
+	}{
+		{
+			name: "all rules pass",
+			analysis: ketchv1.CanaryAnalysis{
+				SuccessRate: intRef(99),
+				MaxLatency:  intRef(500),
+				Queries: ketchv1.CanaryQueries{
+					SuccessRate: `success{deployment="{{ .Deployment }}",ns="{{ .Namespace }}"}[{{ .Interval }}]`,
+					Latency:     `latency{app="{{ .App }}",process="{{ .Process }}",version="{{ .Version }}"}`,
+				},
+			},
+			values: map[string]float64{"success": 99.5, "latency": 120},
+			wantQueries: []string{
+				`success{deployment="go-app-web-3",ns="ketch-gke"}[60s]`,
+				`latency{app="go-app",process="web",version="3"}`,
+			},
+		},
+		{
+			name: "success rate is below the threshold",
+			analysis: ketchv1.CanaryAnalysis{
+				SuccessRate: intRef(99),
+				Queries:     ketchv1.CanaryQueries{SuccessRate: "success"},
+			},
+			values:  map[string]float64{"success": 95},
+			wantErr: "success rate 95.00% is below the threshold of 99%",
+		},
+		{
+			name: "latency is above the threshold",
+			analysis: ketchv1.CanaryAnalysis{
+				MaxLatency: intRef(500),
+				Queries:    ketchv1.CanaryQueries{Latency: "latency"},
+			},
+			values:  map[string]float64{"latency": 750},
+			wantErr: "latency 750ms is above the threshold of 500ms",
+		},
+		{
+			name: "no data",
+			analysis: ketchv1.CanaryAnalysis{
+				SuccessRate: intRef(99),
+				Queries:     ketchv1.CanaryQueries{SuccessRate: "success"},
+			},
+			wantErr: "failed to get success rate: query returned no data",
+		},
+		{
+			name:        "default istio query",
+			ingressType: ketchv1.IstioIngressControllerType,
+			analysis: ketchv1.CanaryAnalysis{
+				SuccessRate: intRef(99),
+			},
+			values: map[string]float64{"sum(rate(istio_requests_total": 100},
+			wantQueries: []string{
+				`sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="ketch-gke",destination_workload="go-app-web-3",response_code!~"5.*"}[60s])) / sum(rate(istio_requests_total{reporter="destination",destination_workload_namespace="ketch-gke",destination_workload="go-app-web-3"}[60s])) * 100`,
+			},
+		},
+		{
+			name: "invalid query template",
+			analysis: ketchv1.CanaryAnalysis{
+				MaxLatency: intRef(500),
+				Queries:    ketchv1.CanaryQueries{Latency: "{{ .Unknown }}"},
+			},
+			wantErr: `failed to render latency query: template: latency:1:3: executing "latency" at <.Unknown>: can't evaluate field Unknown in type canary.QueryParams`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQuerier{values: tt.values}
+			err := Analyze(context.Background(), q, tt.analysis, tt.ingressType, params)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantQueries, q.queries)
+		})
+	}
+}
+
+func TestNewQueryParams(t *testing.T) {
+	app := ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "go-app"},
+		Spec: ketchv1.AppSpec{
+			Canary: ketchv1.CanarySpec{StepTimeInteval: 5 * time.Minute},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 2, Processes: []ketchv1.ProcessSpec{{Name: "web"}}},
+				{Version: 3, Processes: []ketchv1.ProcessSpec{{Name: "worker"}, {Name: "web"}}},
+			},
+		},
+	}
+	framework := ketchv1.Framework{Spec: ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"}}
+
+	got, err := NewQueryParams(app, framework)
+	require.Nil(t, err)
+	require.Equal(t, &QueryParams{
+		App:        "go-app",
+		Namespace:  "ketch-gke",
+		Process:    "web",
+		Version:    "3",
+		Deployment: "go-app-web-3",
+		Interval:   "300s",
+	}, got)
+
+	app.Spec.Deployments = app.Spec.Deployments[:1]
+	_, err = NewQueryParams(app, framework)
+	require.NotNil(t, err)
+}
+
+func TestValidateQueries(t *testing.T) {
+	tests := []struct {
+		name        string
+		analysis    ketchv1.CanaryAnalysis
+		ingressType ketchv1.IngressControllerType
+		wantErr     string
+	}{
+		{
+			name:        "default queries",
+			analysis:    ketchv1.CanaryAnalysis{SuccessRate: intRef(99), MaxLatency: intRef(500)},
+			ingressType: ketchv1.NginxIngressControllerType,
+		},
+		{
+			name:        "gateway-api without queries",
+			analysis:    ketchv1.CanaryAnalysis{MaxLatency: intRef(500)},
+			ingressType: ketchv1.GatewayAPIIngressControllerType,
+			wantErr:     "latency query is not defined, gateway-api ingress controller has no default queries",
+		},
+		{
+			name: "gateway-api with queries of the analysis",
+			analysis: ketchv1.CanaryAnalysis{
+				SuccessRate: intRef(99),
+				Queries:     ketchv1.CanaryQueries{SuccessRate: `success{deployment="{{ .Deployment }}"}`},
+			},
+			ingressType: ketchv1.GatewayAPIIngressControllerType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQueries(tt.analysis, tt.ingressType)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Package canary contains an analysis of canary deployments based on metrics collected by a metrics provider.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error is a main error type of this package.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+const (
+	// ErrNoData is returned when a query doesn't return any value.
+	ErrNoData Error = "query returned no data"
+
+	defaultQueryTimeout = 10 * time.Second
+)
+
+// Querier knows how to run a query against a metrics provider.
+type Querier interface {
+	// Query runs the query and returns its result as a single value.
+	Query(ctx context.Context, query string) (float64, error)
+}
+
+// PrometheusQuerier is a Querier implementation that uses Prometheus HTTP API.
+type PrometheusQuerier struct {
+	address string
+	client  *http.Client
+}
+
+// NewPrometheusQuerier returns a PrometheusQuerier instance.
+func NewPrometheusQuerier(address string) *PrometheusQuerier {
+	return &PrometheusQuerier{
+		address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: defaultQueryTimeout},
+	}
+}
+
+// prometheusResponse represents a response of Prometheus "/api/v1/query" endpoint.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusSample struct {
+	Value []interface{} `json:"value"`
+}
+
+// Query runs an instant query and returns the value of the first sample.
+func (q *PrometheusQuerier) Query(ctx context.Context, query string) (float64, error) {
+	u := fmt.Sprintf("%s/api/v1/query?%s", q.address, url.Values{"query": []string{query}}.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	var response prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("failed to decode prometheus response: %w", err)
+	}
+	if response.Status != "success" {
+		return 0, fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+
+	var value []interface{}
+	switch response.Data.ResultType {
+	case "vector":
+		var samples []prometheusSample
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return 0, fmt.Errorf("failed to decode prometheus response: %w", err)
+		}
+		if len(samples) == 0 {
+			return 0, ErrNoData
+		}
+		value = samples[0].Value
+	case "scalar":
+		if err := json.Unmarshal(response.Data.Result, &value); err != nil {
+			return 0, fmt.Errorf("failed to decode prometheus response: %w", err)
+		}
+	default:
+		return 0, fmt.Errorf("unsupported prometheus result type %q", response.Data.ResultType)
+	}
+	return parseSampleValue(value)
+}
+
+// parseSampleValue parses a Prometheus sample, which is a [<timestamp>, "<value>"] pair.
+func parseSampleValue(value []interface{}) (float64, error) {
+	if len(value) != 2 {
+		return 0, ErrNoData
+	}
+	str, ok := value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected prometheus sample value %v", value[1])
+	}
+	result, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrNoData
+	}
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package canary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusQuerier_Query(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		response   string
+		want       float64
+		wantErr    string
+	}{
+		{
+			name:       "vector",
+			statusCode: http.StatusOK,
+			response:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1612137600.781,"99.5"]}]}}`,
+			want:       99.5,
+		},
+		{
+			name:       "scalar",
+			statusCode: http.StatusOK,
+			response:   `{"status":"success","data":{"resultType":"scalar","result":[1612137600.781,"250"]}}`,
+			want:       250,
+		},
+		{
+			name:       "empty vector",
+			statusCode: http.StatusOK,
+			response:   `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			wantErr:    "query returned no data",
+		},
+		{
+			name:       "NaN value",
+			statusCode: http.StatusOK,
+			response:   `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1612137600.781,"NaN"]}]}}`,
+			wantErr:    "query returned no data",
+		},
+		{
+			name:       "unsupported result type",
+			statusCode: http.StatusOK,
+			response:   `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			wantErr:    `unsupported prometheus result type "matrix"`,
+		},
+		{
+			name:       "bad query",
+			statusCode: http.StatusBadRequest,
+			response:   `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			wantErr:    "prometheus query failed: parse error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, "/api/v1/query", r.URL.Path)
+				require.Equal(t, `sum(rate(requests{app="myapp"}[1m]))`, r.URL.Query().Get("query"))
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			q := NewPrometheusQuerier(server.URL + "/")
+			got, err := q.Query(context.Background(), `sum(rate(requests{app="myapp"}[1m]))`)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
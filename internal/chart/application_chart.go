@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
@@ -20,6 +21,7 @@ import (
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/templates"
+	"github.com/shipa-corp/ketch/internal/utils"
 )
 
 // ApplicationChart is an internal representation of a helm chart converted from the App CRD
@@ -44,7 +46,7 @@ type httpsEndpoint struct {
 }
 
 // Ingress contains information about entrypoints of an application.
-// Both istio and traefik templates use "ingress" to render Kubernetes Ingress objects.
+// Istio, traefik, nginx and gateway-api templates use "ingress" to render Kubernetes Ingress objects and routes.
 type ingress struct {
 
 	// Https is a list of http entrypoints.
@@ -52,13 +54,25 @@ type ingress struct {
 
 	// Https is a list of https entrypoints.
 	Https []httpsEndpoint `json:"https"`
+
+	// Preview is an http entrypoint of the idle deployment of a blue-green deployment.
+	Preview string `json:"preview,omitempty"`
 }
 
 type app struct {
-	Name        string        `json:"name"`
-	Deployments []deployment  `json:"deployments"`
-	Env         []ketchv1.Env `json:"env"`
-	Ingress     ingress       `json:"ingress"`
+	Name        string             `json:"name"`
+	Deployments []deployment       `json:"deployments"`
+	Env         []ketchv1.Env      `json:"env"`
+	EnvFrom     []v1.EnvFromSource `json:"envFrom,omitempty"`
+	Ingress     ingress            `json:"ingress"`
+	// Jobs are rendered as kubernetes Jobs or CronJobs, they are set instead of Deployments for a Job application.
+	Jobs []deployment `json:"jobs,omitempty"`
+	Job  *job         `json:"job,omitempty"`
+	// ConfigChecksum is a checksum of ConfigMaps and Secrets used by the application,
+	// it is set as an annotation of pods to roll them when the configuration changes.
+	ConfigChecksum string `json:"configChecksum,omitempty"`
+	// VolumeClaims are PersistentVolumeClaims created for volumes of the application.
+	VolumeClaims []volumeClaim `json:"volumeClaims,omitempty"`
 	// IsAccessible if not set, ketch won't create kubernetes objects like Ingress/Gateway to handle incoming request.
 	// These objects could be broken without valid routes to the application.
 	// For example, "spec.rules" of an Ingress object must contain at least one rule.
@@ -70,14 +84,49 @@ type deployment struct {
 	Version         ketchv1.DeploymentVersion `json:"version"`
 	Processes       []process                 `json:"processes"`
 	Labels          []ketchv1.Label           `json:"labels"`
-	RoutingSettings ketchv1.RoutingSettings   `json:"routingSettings"`
+	RoutingSettings routingSettings           `json:"routingSettings"`
 	DeploymentExtra deploymentExtra           `json:"extra"`
+	// Preview if set, the deployment is the idle deployment of a blue-green deployment and is accessible with the preview entrypoint.
+	Preview bool `json:"preview"`
+}
+
+// job configures Jobs and CronJobs of a Job application.
+type job struct {
+	Schedule              string `json:"schedule,omitempty"`
+	ConcurrencyPolicy     string `json:"concurrencyPolicy,omitempty"`
+	Parallelism           *int   `json:"parallelism,omitempty"`
+	Completions           *int   `json:"completions,omitempty"`
+	BackoffLimit          *int   `json:"backoffLimit,omitempty"`
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	Run                   int    `json:"run"`
+}
+
+type routingSettings struct {
+	Weight uint8 `json:"weight"`
+	// Match is a list of rules, a request matching any of them is routed to the deployment regardless of the weights.
+	Match []routeMatch `json:"match,omitempty"`
+}
+
+// routeMatch is a condition on a request header.
+// Either Exact or Regex is set, cookies are matched by a regular expression against the "cookie" header.
+type routeMatch struct {
+	// Header is a lowercase name of the header.
+	Header string `json:"header"`
+	Exact  string `json:"exact,omitempty"`
+	Regex  string `json:"regex,omitempty"`
 }
 
 type deploymentExtra struct {
 	Volumes []v1.Volume `json:"volumes,omitempty"`
 }
 
+type volumeClaim struct {
+	Name             string                          `json:"name"`
+	StorageClassName *string                         `json:"storageClassName,omitempty"`
+	Size             string                          `json:"size"`
+	AccessModes      []v1.PersistentVolumeAccessMode `json:"accessModes"`
+}
+
 type dockerRegistrySpec struct {
 	ImagePullSecret string `json:"imagePullSecret"`
 }
@@ -88,6 +137,8 @@ type Options struct {
 	// ExposedPorts are ports exposed by an image of each deployment.
 	ExposedPorts map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort
 	Templates    templates.Templates
+	// ConfigChecksum is a checksum of ConfigMaps and Secrets used by the application.
+	ConfigChecksum string
 }
 
 func WithExposedPorts(ports map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort) Option {
@@ -109,6 +160,13 @@ func WithTemplates(tpls templates.Templates) Option {
 	}
 }
 
+// WithConfigChecksum sets a checksum of ConfigMaps and Secrets used by the application.
+func WithConfigChecksum(checksum string) Option {
+	return func(opts *Options) {
+		opts.ConfigChecksum = checksum
+	}
+}
+
 // New returns an ApplicationChart instance.
 func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option) (*ApplicationChart, error) {
 
@@ -122,6 +180,10 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 			Name:    application.Name,
 			Ingress: newIngress(*application, *framework),
 			Env:     application.Spec.Env,
+			EnvFrom: application.Spec.EnvFrom,
+
+			ConfigChecksum: options.ConfigChecksum,
+			VolumeClaims:   newVolumeClaims(*application),
 		},
 		IngressController: &framework.Spec.IngressController,
 		DockerRegistry: dockerRegistrySpec{
@@ -129,14 +191,17 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 		},
 	}
 
+	volumes := application.PodVolumes()
 	for _, deploymentSpec := range application.Spec.Deployments {
 		deployment := deployment{
 			Image:   deploymentSpec.Image,
 			Version: deploymentSpec.Version,
 			Labels:  deploymentSpec.Labels,
-			RoutingSettings: ketchv1.RoutingSettings{
+			RoutingSettings: routingSettings{
 				Weight: deploymentSpec.RoutingSettings.Weight,
+				Match:  newRouteMatches(deploymentSpec.RoutingSettings.Match),
 			},
+			Preview: application.Spec.Strategy.BlueGreen.Active && deploymentSpec.RoutingSettings.Weight == 0,
 		}
 		procfile, err := ProcfileFromProcesses(deploymentSpec.Processes)
 		if err != nil {
@@ -146,13 +211,33 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 		c := NewConfigurator(deploymentSpec.KetchYaml, *procfile, exposedPorts, DefaultApplicationPort)
 		for _, processSpec := range deploymentSpec.Processes {
 			name := processSpec.Name
+			if err := processSpec.ValidateContainers(application.Name); err != nil {
+				return nil, fmt.Errorf("process %s: %w", name, err)
+			}
 			isRoutable := procfile.IsRoutable(name)
+			// jobs don't get disruption budgets, an evicted unit of a job is retried by the job controller.
+			var disruptionBudget *ketchv1.DisruptionBudgetSpec
+			if !application.IsJob() {
+				disruptionBudget = processSpec.EffectiveDisruptionBudget()
+			}
 			process, err := newProcess(name, isRoutable,
 				withCmd(c.procfile.Processes[name]),
 				withUnits(processSpec.Units),
+				withAutoscaling(processSpec.Autoscaling),
+				withDisruptionBudget(disruptionBudget),
+				withEnvFrom(processSpec.EnvFrom),
+				withVolumeMounts(processSpec.VolumeMounts, volumes),
+				withContainers(processSpec.Sidecars, processSpec.InitContainers, volumes),
 				withPortsAndProbes(c),
-				withLifecycle(c.Lifecycle()),
-				withSecurityContext(processSpec.SecurityContext))
+				withLifecycle(c.Lifecycle(processSpec.Name)),
+				withTerminationGracePeriod(processSpec.TerminationGracePeriodSeconds),
+				withSecurityContext(processSpec.SecurityContext),
+				withResourceRequirements(processSpec.Resources, framework.Spec.DefaultResources),
+				withScheduling(processSpec.Scheduling, framework.Spec.DefaultScheduling, map[string]string{
+					utils.KetchAppNameLabel:           application.Name,
+					utils.KetchProcessNameLabel:       name,
+					utils.KetchDeploymentVersionLabel: deploymentSpec.Version.String(),
+				}))
 
 			if err != nil {
 				return nil, err
@@ -162,6 +247,13 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 		}
 		values.App.Deployments = append(values.App.Deployments, deployment)
 	}
+	if application.IsJob() {
+		// jobs don't handle incoming requests.
+		values.App.Jobs = values.App.Deployments
+		values.App.Deployments = nil
+		values.App.Ingress = ingress{}
+		values.App.Job = newJob(application.Spec.Job)
+	}
 	values.App.IsAccessible = isAppAccessible(values.App)
 	return &ApplicationChart{
 		values:    *values,
@@ -232,8 +324,11 @@ func (chrt ApplicationChart) ExportToDirectory(directory string, chartConfig Cha
 	timestamp := time.Now().Format(time.RFC822)
 	replacer := strings.NewReplacer(" ", "_", ":", "_")
 	chartDir := chartConfig.AppName + "_" + replacer.Replace(timestamp)
-	targetDir := filepath.Join(directory, chartDir)
+	return chrt.WriteToDirectory(filepath.Join(directory, chartDir), chartConfig)
+}
 
+// WriteToDirectory saves the chart to the provided directory, the directory is created if it doesn't exist.
+func (chrt ApplicationChart) WriteToDirectory(targetDir string, chartConfig ChartConfig) error {
 	err := os.MkdirAll(filepath.Join(targetDir, "templates"), os.ModePerm)
 	if err != nil {
 		return err
@@ -297,8 +392,43 @@ func (chrt ApplicationChart) AppName() string {
 	return chrt.values.App.Name
 }
 
+func newVolumeClaims(application ketchv1.App) []volumeClaim {
+	var claims []volumeClaim
+	for _, volume := range application.Spec.Volumes {
+		if volume.Claim == nil {
+			continue
+		}
+		accessModes := volume.Claim.AccessModes
+		if len(accessModes) == 0 {
+			accessModes = []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce}
+		}
+		claims = append(claims, volumeClaim{
+			Name:             volume.ClaimName(application.Name),
+			StorageClassName: volume.Claim.StorageClassName,
+			Size:             volume.Claim.Size.String(),
+			AccessModes:      accessModes,
+		})
+	}
+	return claims
+}
+
+func newJob(spec *ketchv1.JobSpec) *job {
+	if spec == nil {
+		return &job{}
+	}
+	return &job{
+		Schedule:              spec.Schedule,
+		ConcurrencyPolicy:     spec.ConcurrencyPolicy,
+		Parallelism:           spec.Parallelism,
+		Completions:           spec.Completions,
+		BackoffLimit:          spec.BackoffLimit,
+		ActiveDeadlineSeconds: spec.ActiveDeadlineSeconds,
+		Run:                   spec.Run,
+	}
+}
+
 func isAppAccessible(a *app) bool {
-	if len(a.Ingress.Http)+len(a.Ingress.Https) == 0 {
+	if len(a.Ingress.Http)+len(a.Ingress.Https) == 0 && len(a.Ingress.Preview) == 0 {
 		return false
 	}
 	for _, deployment := range a.Deployments {
@@ -311,6 +441,25 @@ func isAppAccessible(a *app) bool {
 	return false
 }
 
+func newRouteMatches(rules []ketchv1.RouteMatch) []routeMatch {
+	var matches []routeMatch
+	for _, rule := range rules {
+		switch rule.Type {
+		case ketchv1.RouteMatchHeader:
+			matches = append(matches, routeMatch{
+				Header: strings.ToLower(rule.Name),
+				Exact:  rule.Value,
+			})
+		case ketchv1.RouteMatchCookie:
+			matches = append(matches, routeMatch{
+				Header: "cookie",
+				Regex:  fmt.Sprintf(`^(.*?;\s*)?(%s=%s)(;.*)?$`, regexp.QuoteMeta(rule.Name), regexp.QuoteMeta(rule.Value)),
+			})
+		}
+	}
+	return matches
+}
+
 func newIngress(app ketchv1.App, framework ketchv1.Framework) ingress {
 	var http []string
 	var https []string
@@ -332,8 +481,13 @@ func newIngress(app ketchv1.App, framework ketchv1.Framework) ingress {
 	if defaultCname != nil {
 		http = append(http, *defaultCname)
 	}
+	var preview string
+	if previewCname := app.PreviewCname(&framework); previewCname != nil {
+		preview = *previewCname
+	}
 	return ingress{
-		Http:  http,
-		Https: httpsEndpoints,
+		Http:    http,
+		Https:   httpsEndpoints,
+		Preview: preview,
 	}
 }
@@ -8,13 +8,17 @@ import (
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/templates"
+	"github.com/shipa-corp/ketch/internal/utils/conversions"
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/kube/fake"
 	"helm.sh/helm/v3/pkg/storage"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"io/ioutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestNew(t *testing.T) {
@@ -46,8 +50,15 @@ func TestNew(t *testing.T) {
 			},
 		},
 	}
+	frameworkWithGateway := frameworkWithClusterIssuer.DeepCopy()
+	frameworkWithGateway.Spec.IngressController.IngressType = ketchv1.GatewayAPIIngressControllerType
+	frameworkWithGateway.Spec.IngressController.Gateway = &ketchv1.GatewayReference{Name: "public", Namespace: "gateways"}
+	frameworkWithLocalGateway := frameworkWithoutClusterIssuer.DeepCopy()
+	frameworkWithLocalGateway.Spec.IngressController.IngressType = ketchv1.GatewayAPIIngressControllerType
+	frameworkWithLocalGateway.Spec.IngressController.Gateway = &ketchv1.GatewayReference{Name: "ketch"}
 	exportedPorts := map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{
 		3: {{Port: 9090, Protocol: "TCP"}},
+		4: {{Port: 9091, Protocol: "TCP"}},
 	}
 	dashboard := &ketchv1.App{
 		ObjectMeta: metav1.ObjectMeta{
@@ -78,6 +89,226 @@ func TestNew(t *testing.T) {
 		},
 	}
 
+	canary := dashboard.DeepCopy()
+	canary.Name = "dashboard-canary"
+	canary.Spec.Ingress.GenerateDefaultCname = false
+	canary.Spec.Deployments[0].RoutingSettings.Weight = 80
+	canary.Spec.Deployments = append(canary.Spec.Deployments, ketchv1.AppDeploymentSpec{
+		Image:   "shipasoftware/go-app:v2",
+		Version: 4,
+		Processes: []ketchv1.ProcessSpec{
+			{Name: "web", Units: intRef(1), Cmd: []string{"python"}},
+		},
+		RoutingSettings: ketchv1.RoutingSettings{
+			Weight: 20,
+			Match: []ketchv1.RouteMatch{
+				{Type: ketchv1.RouteMatchHeader, Name: "X-Canary", Value: "true"},
+				{Type: ketchv1.RouteMatchCookie, Name: "canary", Value: "always"},
+			},
+		},
+	})
+
+	blueGreen := dashboard.DeepCopy()
+	blueGreen.Name = "dashboard-blue-green"
+	blueGreen.Spec.Strategy = ketchv1.StrategySpec{
+		Type:      ketchv1.BlueGreenStrategy,
+		BlueGreen: ketchv1.BlueGreenSpec{Active: true},
+	}
+	blueGreen.Spec.Deployments = append(blueGreen.Spec.Deployments, ketchv1.AppDeploymentSpec{
+		Image:   "shipasoftware/go-app:v2",
+		Version: 4,
+		Processes: []ketchv1.ProcessSpec{
+			{Name: "web", Units: intRef(3), Cmd: []string{"python"}},
+			{Name: "worker", Units: intRef(1), Cmd: []string{"celery"}},
+		},
+		RoutingSettings: ketchv1.RoutingSettings{
+			Weight: 0,
+		},
+	})
+
+	autoscaling := dashboard.DeepCopy()
+	autoscaling.Name = "dashboard-autoscaling"
+	autoscaling.Spec.Deployments[0].Processes[0].Autoscaling = &ketchv1.AutoscalingSpec{
+		MinReplicas:             2,
+		MaxReplicas:             10,
+		TargetCPUUtilization:    intRef(80),
+		TargetMemoryUtilization: intRef(70),
+	}
+
+	resources := dashboard.DeepCopy()
+	resources.Name = "dashboard-resources"
+	resources.Spec.Deployments[0].Processes[0].Resources = &v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+		Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), v1.ResourceMemory: resource.MustParse("256Mi")},
+	}
+	frameworkWithDefaultResources := frameworkWithoutClusterIssuer.DeepCopy()
+	frameworkWithDefaultResources.Spec.DefaultResources = &v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("64Mi")},
+		Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")},
+	}
+
+	secretEnv := dashboard.DeepCopy()
+	secretEnv.Name = "dashboard-secret-env"
+	secretEnv.SetSecretEnvs([]string{"DATABASE_PASSWORD"})
+
+	envFrom := dashboard.DeepCopy()
+	envFrom.Name = "dashboard-env-from"
+	envFrom.Spec.EnvFrom = []v1.EnvFromSource{
+		{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+	}
+	envFrom.Spec.Deployments[0].Processes[0].EnvFrom = []v1.EnvFromSource{
+		{Prefix: "DB_", SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}}},
+	}
+
+	volumes := dashboard.DeepCopy()
+	volumes.Name = "dashboard-volumes"
+	storageClass := "standard"
+	require.Nil(t, volumes.AttachVolume(ketchv1.Volume{
+		Name:  "uploads",
+		Claim: &ketchv1.VolumeClaimSpec{Size: resource.MustParse("10Gi"), StorageClassName: &storageClass},
+	}, v1.VolumeMount{MountPath: "/uploads"}, "web"))
+	require.Nil(t, volumes.AttachVolume(ketchv1.Volume{
+		Name:     "cache",
+		EmptyDir: &v1.EmptyDirVolumeSource{},
+	}, v1.VolumeMount{MountPath: "/cache"}, ""))
+
+	job := dashboard.DeepCopy()
+	job.Name = "dashboard-job"
+	job.Spec.Type = ketchv1.JobAppType
+	job.Spec.Job = &ketchv1.JobSpec{BackoffLimit: conversions.IntPtr(0), Completions: conversions.IntPtr(2)}
+
+	cronJob := dashboard.DeepCopy()
+	cronJob.Name = "dashboard-cronjob"
+	cronJob.Spec.Type = ketchv1.JobAppType
+	cronJob.Spec.Job = &ketchv1.JobSpec{Schedule: "*/5 * * * *", ConcurrencyPolicy: "Forbid"}
+
+	restartHooks := dashboard.DeepCopy()
+	restartHooks.Name = "dashboard-restart-hooks"
+	restartHooks.Spec.Deployments[0].KetchYaml = &ketchv1.KetchYamlData{
+		Hooks: &ketchv1.KetchYamlHooks{
+			Restart: ketchv1.KetchYamlRestartHooks{
+				Before: []string{"touch /tmp/draining", "sleep 10"},
+				After:  []string{"echo started"},
+			},
+		},
+		Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+			Processes: map[string]ketchv1.KetchYamlProcessConfig{
+				"worker": {RestartHooks: &ketchv1.KetchYamlRestartHooks{Before: []string{"touch /tmp/stop-consuming"}}},
+			},
+		},
+	}
+	restartHooks.Spec.Deployments[0].Processes[0].TerminationGracePeriodSeconds = conversions.Int64Ptr(60)
+	restartHooks.Spec.Deployments[0].Processes[1].TerminationGracePeriodSeconds = conversions.Int64Ptr(0)
+
+	probes := dashboard.DeepCopy()
+	probes.Name = "dashboard-probes"
+	probes.Spec.Deployments[0].KetchYaml = &ketchv1.KetchYamlData{
+		Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+			Processes: map[string]ketchv1.KetchYamlProcessConfig{
+				"web": {
+					Probes: &ketchv1.ProcessProbes{
+						Readiness: &ketchv1.Probe{
+							HTTPGet: &v1.HTTPGetAction{
+								Path:        "/health",
+								Port:        intstr.FromInt(9090),
+								HTTPHeaders: []v1.HTTPHeader{{Name: "X-Probe", Value: "readiness"}},
+							},
+							PeriodSeconds: 5,
+						},
+						Startup: &ketchv1.Probe{
+							TCPSocket:        &v1.TCPSocketAction{Port: intstr.FromInt(9090)},
+							FailureThreshold: 30,
+							PeriodSeconds:    10,
+						},
+					},
+				},
+				"worker": {
+					Probes: &ketchv1.ProcessProbes{
+						Readiness: &ketchv1.Probe{
+							GRPC: &ketchv1.GRPCAction{Port: 50051},
+						},
+						Liveness: &ketchv1.Probe{
+							Exec:                &v1.ExecAction{Command: []string{"celery", "inspect", "ping"}},
+							InitialDelaySeconds: 15,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	sidecars := dashboard.DeepCopy()
+	sidecars.Name = "dashboard-sidecars"
+	require.Nil(t, sidecars.AttachVolume(ketchv1.Volume{
+		Name:   "cloudsql-credentials",
+		Secret: &v1.SecretVolumeSource{SecretName: "cloudsql"},
+	}, v1.VolumeMount{MountPath: "/secrets"}, "worker"))
+	// the volume is mounted by the sidecar only.
+	sidecars.Spec.Deployments[0].Processes[1].VolumeMounts = nil
+	sidecars.Spec.Deployments[0].Processes[0].Sidecars = []ketchv1.Container{
+		{
+			Name:    "cloudsql-proxy",
+			Image:   "gcr.io/cloudsql-docker/gce-proxy:1.28.0",
+			Command: []string{"/cloud_sql_proxy"},
+			Args:    []string{"-instances=project:region:db=tcp:5432", "-credential_file=/secrets/credentials.json"},
+			Env: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "info"},
+				{Name: "TOKEN", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "cloudsql"}, Key: "token"}}},
+			},
+			Ports:        []v1.ContainerPort{{Name: "postgres", ContainerPort: 5432}},
+			VolumeMounts: []v1.VolumeMount{{Name: "cloudsql-credentials", MountPath: "/secrets", ReadOnly: true}},
+		},
+	}
+	sidecars.Spec.Deployments[0].Processes[0].InitContainers = []ketchv1.Container{
+		{
+			Name:    "wait-for-schema",
+			Image:   "busybox:1.34",
+			Command: []string{"sh", "-c", "until nc -z db 5432; do sleep 1; done"},
+		},
+	}
+
+	scheduling := dashboard.DeepCopy()
+	scheduling.Name = "dashboard-scheduling"
+	scheduling.Spec.Deployments[0].Processes[0].Scheduling = &ketchv1.SchedulingSpec{
+		NodeSelector: map[string]string{"disktype": "ssd"},
+		Affinity: &v1.Affinity{
+			PodAntiAffinity: &v1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []v1.WeightedPodAffinityTerm{
+					{
+						Weight: 100,
+						PodAffinityTerm: v1.PodAffinityTerm{
+							LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"theketch.io/app-name": "cache"}},
+							TopologyKey:   "kubernetes.io/hostname",
+						},
+					},
+				},
+			},
+		},
+		TopologySpreadConstraints: []v1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: v1.DoNotSchedule},
+		},
+		PriorityClassName: "high",
+	}
+	frameworkWithDefaultScheduling := frameworkWithoutClusterIssuer.DeepCopy()
+	frameworkWithDefaultScheduling.Spec.DefaultScheduling = &ketchv1.SchedulingSpec{
+		NodeSelector: map[string]string{"cloud.google.com/gke-nodepool": "apps"},
+		Tolerations:  []v1.Toleration{{Key: "spot", Operator: v1.TolerationOpExists, Effect: v1.TaintEffectNoSchedule}},
+	}
+	cronJobScheduling := cronJob.DeepCopy()
+	cronJobScheduling.Name = "dashboard-cronjob-scheduling"
+	cronJobScheduling.Spec.Deployments[0].Processes[0].Scheduling = &ketchv1.SchedulingSpec{
+		NodeSelector:      map[string]string{"pool": "batch"},
+		Tolerations:       []v1.Toleration{{Key: "batch", Operator: v1.TolerationOpEqual, Value: "true", Effect: v1.TaintEffectNoExecute}},
+		PriorityClassName: "low",
+	}
+
+	disruptionBudget := dashboard.DeepCopy()
+	disruptionBudget.Name = "dashboard-disruption-budget"
+	halfOfUnits := intstr.FromString("50%")
+	oneUnit := intstr.FromInt(1)
+	disruptionBudget.Spec.Deployments[0].Processes[0].DisruptionBudget = &ketchv1.DisruptionBudgetSpec{MinAvailable: &halfOfUnits}
+	disruptionBudget.Spec.Deployments[0].Processes[1].DisruptionBudget = &ketchv1.DisruptionBudgetSpec{MinAvailable: &oneUnit}
+
 	tests := []struct {
 		name        string
 		application *ketchv1.App
@@ -127,6 +358,257 @@ func TestNew(t *testing.T) {
 			framework:         frameworkWithoutClusterIssuer,
 			wantYamlsFilename: "dashboard-traefik",
 		},
+		{
+			name: "istio templates with canary match rules",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       canary,
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-canary-istio",
+		},
+		{
+			name: "traefik templates with canary match rules",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       canary,
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-canary-traefik",
+		},
+		{
+			name: "istio templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       blueGreen,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-blue-green-istio",
+		},
+		{
+			name: "traefik templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       blueGreen,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-blue-green-traefik",
+		},
+		{
+			name: "nginx templates with cluster issuer",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       dashboard,
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx-cluster-issuer",
+		},
+		{
+			name: "nginx templates without cluster issuer",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       dashboard,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-nginx",
+		},
+		{
+			name: "nginx templates with canary match rules",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       canary,
+			framework:         frameworkWithClusterIssuer,
+			wantYamlsFilename: "dashboard-canary-nginx",
+		},
+		{
+			name: "nginx templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.NginxDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       blueGreen,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-blue-green-nginx",
+		},
+		{
+			name: "gateway-api templates with cluster issuer",
+			opts: []Option{
+				WithTemplates(templates.GatewayAPIDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       dashboard,
+			framework:         frameworkWithGateway,
+			wantYamlsFilename: "dashboard-gateway-api-cluster-issuer",
+		},
+		{
+			name: "gateway-api templates without cluster issuer",
+			opts: []Option{
+				WithTemplates(templates.GatewayAPIDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       dashboard,
+			framework:         frameworkWithLocalGateway,
+			wantYamlsFilename: "dashboard-gateway-api",
+		},
+		{
+			name: "gateway-api templates with canary match rules",
+			opts: []Option{
+				WithTemplates(templates.GatewayAPIDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       canary,
+			framework:         frameworkWithGateway,
+			wantYamlsFilename: "dashboard-canary-gateway-api",
+		},
+		{
+			name: "gateway-api templates with blue-green deployment",
+			opts: []Option{
+				WithTemplates(templates.GatewayAPIDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       blueGreen,
+			framework:         frameworkWithLocalGateway,
+			wantYamlsFilename: "dashboard-blue-green-gateway-api",
+		},
+		{
+			name: "istio templates with autoscaling",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       autoscaling,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-autoscaling-istio",
+		},
+		{
+			name: "istio templates with resources and framework's default resources",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       resources,
+			framework:         frameworkWithDefaultResources,
+			wantYamlsFilename: "dashboard-resources-istio",
+		},
+		{
+			name: "istio templates with secret env",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       secretEnv,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-secret-env-istio",
+		},
+		{
+			name: "istio templates with env from configmaps and secrets",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+				WithConfigChecksum("4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"),
+			},
+			application:       envFrom,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-env-from-istio",
+		},
+		{
+			name: "istio templates with volumes",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       volumes,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-volumes-istio",
+		},
+		{
+			name: "istio templates with job",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       job,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-job-istio",
+		},
+		{
+			name: "traefik templates with cronjob",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       cronJob,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-cronjob-traefik",
+		},
+		{
+			name: "istio templates with restart hooks and termination grace period",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       restartHooks,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-restart-hooks-istio",
+		},
+		{
+			name: "istio templates with probes",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       probes,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-probes-istio",
+		},
+		{
+			name: "istio templates with sidecars and init containers",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       sidecars,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-sidecars-istio",
+		},
+		{
+			name: "istio templates with scheduling and framework's default scheduling",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       scheduling,
+			framework:         frameworkWithDefaultScheduling,
+			wantYamlsFilename: "dashboard-scheduling-istio",
+		},
+		{
+			name: "traefik templates with cronjob and framework's default scheduling",
+			opts: []Option{
+				WithTemplates(templates.TraefikDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       cronJobScheduling,
+			framework:         frameworkWithDefaultScheduling,
+			wantYamlsFilename: "dashboard-cronjob-scheduling-traefik",
+		},
+		{
+			name: "istio templates with disruption budgets",
+			opts: []Option{
+				WithTemplates(templates.IstioDefaultTemplates),
+				WithExposedPorts(exportedPorts),
+			},
+			application:       disruptionBudget,
+			framework:         frameworkWithoutClusterIssuer,
+			wantYamlsFilename: "dashboard-disruption-budget-istio",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
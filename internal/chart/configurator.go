@@ -3,6 +3,7 @@ package chart
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -34,18 +35,34 @@ func NewConfigurator(data *ketchv1.KetchYamlData, procfile Procfile, exposedPort
 	}
 }
 
-// Probes represents a Pod's liveness and readiness probes.
+// Probes represents a Pod's liveness, readiness and startup probes.
 type Probes struct {
-	Liveness  *apiv1.Probe
-	Readiness *apiv1.Probe
+	Liveness  *ketchv1.Probe
+	Readiness *ketchv1.Probe
+	Startup   *ketchv1.Probe
 }
 
-func (c Configurator) Probes(port int32) (Probes, error) {
+// Probes returns probes of the process' containers.
+// Probes of the process configured in ketch.yaml take precedence over the healthcheck,
+// the healthcheck is used only if the process listens on a port.
+func (c Configurator) Probes(process string, port int32) (Probes, error) {
 	var result Probes
-	hc := c.data.Healthcheck
-	if hc == nil || hc.Path == "" {
+	if c.data.Kubernetes != nil {
+		if config, ok := c.data.Kubernetes.Processes[process]; ok && config.Probes != nil {
+			if err := config.Probes.Validate(); err != nil {
+				return result, errors.Wrapf(err, "process %s", process)
+			}
+			result.Liveness = config.Probes.Liveness
+			result.Readiness = config.Probes.Readiness
+			result.Startup = config.Probes.Startup
+			return result, nil
+		}
+	}
+	if c.data.Healthcheck == nil || c.data.Healthcheck.Path == "" || port == 0 {
 		return result, nil
 	}
+	// defaults are applied to a copy, the healthcheck is shared by all processes.
+	hc := *c.data.Healthcheck
 	if hc.Scheme == "" {
 		hc.Scheme = defaultHealthcheckScheme
 	}
@@ -59,65 +76,106 @@ func (c Configurator) Probes(port int32) (Probes, error) {
 	if hc.TimeoutSeconds == 0 {
 		hc.TimeoutSeconds = defaultHealthcheckTimeoutSeconds
 	}
-	if !hc.UseInRouter {
+	headerNames := make([]string, 0, len(hc.Headers))
+	for name := range hc.Headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+	// reading the body or a method other than GET require curl, so the healthcheck runs in a shell of the container
+	// and, as it isn't used in the router, only until it succeeds once.
+	if !hc.UseInRouter && (hc.Match != "" || hc.Method != http.MethodGet) {
 		url := fmt.Sprintf("%s://localhost:%d/%s", hc.Scheme, port, strings.TrimPrefix(hc.Path, "/"))
-		result.Readiness = &apiv1.Probe{
+		var curlArgs string
+		for _, name := range headerNames {
+			curlArgs += " -H " + shellQuote(fmt.Sprintf("%s: %s", name, hc.Headers[name]))
+		}
+		request := fmt.Sprintf(`curl -ksSf -X%s%s -o /dev/null %s`, shellQuote(hc.Method), curlArgs, shellQuote(url))
+		if hc.Match != "" {
+			request = fmt.Sprintf(`curl -ksSf -X%s%s %s | grep -qE %s`, shellQuote(hc.Method), curlArgs, shellQuote(url), shellQuote(hc.Match))
+		}
+		result.Readiness = &ketchv1.Probe{
 			FailureThreshold: int32(hc.AllowedFailures),
 			PeriodSeconds:    int32(3),
 			TimeoutSeconds:   int32(hc.TimeoutSeconds),
-			Handler: apiv1.Handler{
-				Exec: &apiv1.ExecAction{
-					Command: []string{
-						"sh", "-c",
-						fmt.Sprintf(`if [ ! -f /tmp/onetimeprobesuccessful ]; then curl -ksSf -X%[1]s -o /dev/null %[2]s && touch /tmp/onetimeprobesuccessful; fi`,
-							hc.Method, url),
-					},
+			Exec: &apiv1.ExecAction{
+				Command: []string{
+					"sh", "-c",
+					fmt.Sprintf(`if [ ! -f /tmp/onetimeprobesuccessful ]; then %s && touch /tmp/onetimeprobesuccessful; fi`, request),
 				},
 			},
 		}
 		return result, nil
 	}
-	if hc.Method != http.MethodGet {
-		return result, errors.New("healthcheck: only GET method is supported in with use_in_router set")
-	}
-	if hc.AllowedFailures == 0 {
-		hc.AllowedFailures = defaultHealthcheckAllowedFailures
+	if hc.UseInRouter {
+		if hc.Method != http.MethodGet {
+			return result, errors.New("healthcheck: only GET method is supported in with use_in_router set")
+		}
+		if hc.Match != "" {
+			return result, errors.New("healthcheck: match is not supported with use_in_router set, use probes instead")
+		}
+		if hc.AllowedFailures == 0 {
+			hc.AllowedFailures = defaultHealthcheckAllowedFailures
+		}
 	}
 	hc.Scheme = strings.ToUpper(hc.Scheme)
-	probe := &apiv1.Probe{
+	httpGet := &apiv1.HTTPGetAction{
+		Path:   hc.Path,
+		Port:   intstr.FromInt(int(port)),
+		Scheme: apiv1.URIScheme(hc.Scheme),
+	}
+	for _, name := range headerNames {
+		httpGet.HTTPHeaders = append(httpGet.HTTPHeaders, apiv1.HTTPHeader{Name: name, Value: hc.Headers[name]})
+	}
+	probe := &ketchv1.Probe{
 		FailureThreshold: int32(hc.AllowedFailures),
 		PeriodSeconds:    int32(hc.IntervalSeconds),
 		TimeoutSeconds:   int32(hc.TimeoutSeconds),
-		Handler: apiv1.Handler{
-			HTTPGet: &apiv1.HTTPGetAction{
-				Path:   hc.Path,
-				Port:   intstr.FromInt(int(port)),
-				Scheme: apiv1.URIScheme(hc.Scheme),
-			},
-		},
+		HTTPGet:          httpGet,
 	}
 	result.Readiness = probe
-	if hc.ForceRestart {
+	if hc.UseInRouter && hc.ForceRestart {
 		result.Liveness = probe
 	}
 	return result, nil
 }
 
-func (c Configurator) Lifecycle() *apiv1.Lifecycle {
-	if c.data.Hooks == nil {
-		return nil
+// shellQuote quotes s as a single argument of a sh command.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Lifecycle returns handlers running the restart hooks of the process,
+// restart.after commands run as a postStart handler and restart.before commands run as a preStop handler.
+// Restart hooks of the process take precedence over the restart hooks of ketch.yaml.
+func (c Configurator) Lifecycle(process string) *apiv1.Lifecycle {
+	var restart ketchv1.KetchYamlRestartHooks
+	if c.data.Hooks != nil {
+		restart = c.data.Hooks.Restart
+	}
+	if c.data.Kubernetes != nil {
+		if config, ok := c.data.Kubernetes.Processes[process]; ok && config.RestartHooks != nil {
+			restart = *config.RestartHooks
+		}
 	}
-	if len(c.data.Hooks.Restart.After) == 0 {
+	if len(restart.After) == 0 && len(restart.Before) == 0 {
 		return nil
 	}
-	hookCmds := []string{
-		"sh", "-c",
-		strings.Join(c.data.Hooks.Restart.After, " && "),
+	lifecycle := &apiv1.Lifecycle{}
+	if len(restart.After) > 0 {
+		lifecycle.PostStart = hookHandler(restart.After)
 	}
-	return &apiv1.Lifecycle{
-		PostStart: &apiv1.Handler{
-			Exec: &apiv1.ExecAction{
-				Command: hookCmds,
+	if len(restart.Before) > 0 {
+		lifecycle.PreStop = hookHandler(restart.Before)
+	}
+	return lifecycle
+}
+
+func hookHandler(commands []string) *apiv1.Handler {
+	return &apiv1.Handler{
+		Exec: &apiv1.ExecAction{
+			Command: []string{
+				"sh", "-c",
+				strings.Join(commands, " && "),
 			},
 		},
 	}
@@ -125,8 +183,9 @@ func (c Configurator) Lifecycle() *apiv1.Lifecycle {
 
 func (c Configurator) ProcessPortConfigs(process string) []ketchv1.KetchYamlProcessPortConfig {
 	if c.data.Kubernetes != nil {
+		// a process can be configured with probes only, it uses the exposed ports then.
 		podConfig, ok := c.data.Kubernetes.Processes[process]
-		if ok {
+		if ok && podConfig.Ports != nil {
 			return podConfig.Ports
 		}
 	}
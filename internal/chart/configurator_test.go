@@ -0,0 +1,191 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+func TestConfigurator_Probes(t *testing.T) {
+	liveness := &ketchv1.Probe{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(8080)}}
+	healthcheck := &ketchv1.KetchYamlHealthcheck{
+		Path:         "/health",
+		UseInRouter:  true,
+		ForceRestart: true,
+		Headers:      map[string]string{"X-Probe": "true", "Host": "app.theketch.io"},
+	}
+	tests := []struct {
+		name    string
+		data    ketchv1.KetchYamlData
+		process string
+		port    int32
+		want    Probes
+		wantErr string
+	}{
+		{
+			name: "probes of the process take precedence over the healthcheck",
+			data: ketchv1.KetchYamlData{
+				Healthcheck: healthcheck,
+				Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+					Processes: map[string]ketchv1.KetchYamlProcessConfig{
+						"web": {Probes: &ketchv1.ProcessProbes{Liveness: liveness}},
+					},
+				},
+			},
+			process: "web",
+			port:    8080,
+			want:    Probes{Liveness: liveness},
+		},
+		{
+			name: "process without a port gets its probes",
+			data: ketchv1.KetchYamlData{
+				Healthcheck: healthcheck,
+				Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+					Processes: map[string]ketchv1.KetchYamlProcessConfig{
+						"worker": {Probes: &ketchv1.ProcessProbes{Startup: liveness}},
+					},
+				},
+			},
+			process: "worker",
+			want:    Probes{Startup: liveness},
+		},
+		{
+			name: "invalid probe",
+			data: ketchv1.KetchYamlData{
+				Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+					Processes: map[string]ketchv1.KetchYamlProcessConfig{
+						"web": {Probes: &ketchv1.ProcessProbes{Readiness: &ketchv1.Probe{PeriodSeconds: 5}}},
+					},
+				},
+			},
+			process: "web",
+			port:    8080,
+			wantErr: "process web: probe must have exactly one of exec, httpGet, tcpSocket and grpc",
+		},
+		{
+			name:    "healthcheck with headers",
+			data:    ketchv1.KetchYamlData{Healthcheck: healthcheck},
+			process: "web",
+			port:    8080,
+			want: Probes{
+				Readiness: &ketchv1.Probe{
+					FailureThreshold: 3,
+					PeriodSeconds:    10,
+					TimeoutSeconds:   60,
+					HTTPGet: &v1.HTTPGetAction{
+						Path:        "/health",
+						Port:        intstr.FromInt(8080),
+						Scheme:      v1.URISchemeHTTP,
+						HTTPHeaders: []v1.HTTPHeader{{Name: "Host", Value: "app.theketch.io"}, {Name: "X-Probe", Value: "true"}},
+					},
+				},
+				Liveness: &ketchv1.Probe{
+					FailureThreshold: 3,
+					PeriodSeconds:    10,
+					TimeoutSeconds:   60,
+					HTTPGet: &v1.HTTPGetAction{
+						Path:        "/health",
+						Port:        intstr.FromInt(8080),
+						Scheme:      v1.URISchemeHTTP,
+						HTTPHeaders: []v1.HTTPHeader{{Name: "Host", Value: "app.theketch.io"}, {Name: "X-Probe", Value: "true"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "healthcheck of a process without a port",
+			data:    ketchv1.KetchYamlData{Healthcheck: healthcheck},
+			process: "worker",
+		},
+		{
+			name:    "healthcheck not used in router",
+			data:    ketchv1.KetchYamlData{Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/health", ForceRestart: true, Headers: map[string]string{"X-Probe": "true"}}},
+			process: "web",
+			port:    8080,
+			want: Probes{
+				Readiness: &ketchv1.Probe{
+					PeriodSeconds:  10,
+					TimeoutSeconds: 60,
+					HTTPGet: &v1.HTTPGetAction{
+						Path:        "/health",
+						Port:        intstr.FromInt(8080),
+						Scheme:      v1.URISchemeHTTP,
+						HTTPHeaders: []v1.HTTPHeader{{Name: "X-Probe", Value: "true"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "healthcheck with match",
+			data:    ketchv1.KetchYamlData{Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/health", Match: "ok"}},
+			process: "web",
+			port:    8080,
+			want: Probes{
+				Readiness: &ketchv1.Probe{
+					PeriodSeconds:  3,
+					TimeoutSeconds: 60,
+					Exec: &v1.ExecAction{
+						Command: []string{"sh", "-c", "if [ ! -f /tmp/onetimeprobesuccessful ]; then curl -ksSf -X'GET' 'http://localhost:8080/health' | grep -qE 'ok' && touch /tmp/onetimeprobesuccessful; fi"},
+					},
+				},
+			},
+		},
+		{
+			name: "healthcheck with match and headers containing quotes",
+			data: ketchv1.KetchYamlData{Healthcheck: &ketchv1.KetchYamlHealthcheck{
+				Path:    "/health",
+				Match:   `"status": 'ok'`,
+				Headers: map[string]string{"X-Probe": "it's me"},
+			}},
+			process: "web",
+			port:    8080,
+			want: Probes{
+				Readiness: &ketchv1.Probe{
+					PeriodSeconds:  3,
+					TimeoutSeconds: 60,
+					Exec: &v1.ExecAction{
+						Command: []string{"sh", "-c", `if [ ! -f /tmp/onetimeprobesuccessful ]; then curl -ksSf -X'GET' -H 'X-Probe: it'\''s me' 'http://localhost:8080/health' | grep -qE '"status": '\''ok'\''' && touch /tmp/onetimeprobesuccessful; fi`},
+					},
+				},
+			},
+		},
+		{
+			name:    "healthcheck with a method other than GET",
+			data:    ketchv1.KetchYamlData{Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/health", Method: "post"}},
+			process: "web",
+			port:    8080,
+			want: Probes{
+				Readiness: &ketchv1.Probe{
+					PeriodSeconds:  3,
+					TimeoutSeconds: 60,
+					Exec: &v1.ExecAction{
+						Command: []string{"sh", "-c", "if [ ! -f /tmp/onetimeprobesuccessful ]; then curl -ksSf -X'POST' -o /dev/null 'http://localhost:8080/health' && touch /tmp/onetimeprobesuccessful; fi"},
+					},
+				},
+			},
+		},
+		{
+			name:    "healthcheck with match used in router",
+			data:    ketchv1.KetchYamlData{Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/health", Match: "ok", UseInRouter: true}},
+			process: "web",
+			port:    8080,
+			wantErr: "healthcheck: match is not supported with use_in_router set, use probes instead",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigurator(&tt.data, Procfile{}, nil, DefaultApplicationPort)
+			got, err := c.Probes(tt.process, tt.port)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
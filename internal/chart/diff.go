@@ -0,0 +1,126 @@
+package chart
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"sigs.k8s.io/yaml"
+)
+
+// volatileFields are fields set by kubernetes or changing with every release, they are ignored by DiffManifests.
+var volatileFields = [][]string{
+	{"metadata", "creationTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "managedFields"},
+	{"metadata", "resourceVersion"},
+	{"metadata", "selfLink"},
+	{"metadata", "uid"},
+	{"status"},
+}
+
+// DiffManifests returns a unified diff of every resource that differs between the current and the proposed manifests.
+// Resources are matched by their kind, namespace and name, volatile fields are ignored.
+// The diff is empty if the manifests describe the same resources.
+func DiffManifests(current, proposed string) (string, error) {
+	currentResources, err := parseManifest(current)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the current manifest: %w", err)
+	}
+	proposedResources, err := parseManifest(proposed)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse the proposed manifest: %w", err)
+	}
+	keys := make([]string, 0, len(currentResources)+len(proposedResources))
+	for key := range currentResources {
+		keys = append(keys, key)
+	}
+	for key := range proposedResources {
+		if _, ok := currentResources[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, key := range keys {
+		a, inCurrent := currentResources[key]
+		b, inProposed := proposedResources[key]
+		if a == b {
+			continue
+		}
+		diff := difflib.UnifiedDiff{
+			A:        splitLines(a),
+			B:        splitLines(b),
+			FromFile: "current/" + key,
+			ToFile:   "proposed/" + key,
+			Context:  3,
+		}
+		if !inCurrent {
+			diff.FromFile = "/dev/null"
+		}
+		if !inProposed {
+			diff.ToFile = "/dev/null"
+		}
+		if err := difflib.WriteUnifiedDiff(&buf, diff); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// parseManifest returns resources of the manifest without volatile fields as yaml documents with sorted keys.
+func parseManifest(manifest string) (map[string]string, error) {
+	resources := make(map[string]string)
+	for _, document := range releaseutil.SplitManifests(manifest) {
+		var resource map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &resource); err != nil {
+			return nil, err
+		}
+		if len(resource) == 0 {
+			continue
+		}
+		for _, path := range volatileFields {
+			removeField(resource, path)
+		}
+		content, err := yaml.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		resources[resourceKey(resource)] = string(content)
+	}
+	return resources, nil
+}
+
+// resourceKey returns a kind, a namespace if any and a name of the resource, ex. Deployment/dashboard-web-1.
+func resourceKey(resource map[string]interface{}) string {
+	kind, _ := resource["kind"].(string)
+	metadata, _ := resource["metadata"].(map[string]interface{})
+	name, _ := metadata["name"].(string)
+	if namespace, _ := metadata["namespace"].(string); len(namespace) > 0 {
+		return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	}
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// splitLines returns lines of the yaml document, every line ends with a newline.
+func splitLines(document string) []string {
+	lines := strings.SplitAfter(document, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func removeField(resource map[string]interface{}, path []string) {
+	for _, field := range path[:len(path)-1] {
+		next, ok := resource[field].(map[string]interface{})
+		if !ok {
+			return
+		}
+		resource = next
+	}
+	delete(resource, path[len(path)-1])
+}
@@ -0,0 +1,152 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffManifests(t *testing.T) {
+	current := `---
+# Source: dashboard/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: dashboard-web-1
+  resourceVersion: "1234"
+spec:
+  ports:
+  - port: 9090
+---
+# Source: dashboard/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard-web-1
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: dashboard-web-1
+        image: shipasoftware/go-app:v1
+---
+# Source: dashboard/templates/certificate.yaml
+apiVersion: cert-manager.io/v1
+kind: Certificate
+metadata:
+  name: dashboard-cname-theketch-io
+  namespace: istio-system
+spec:
+  secretName: dashboard-cname-theketch-io
+`
+	proposed := `---
+# Source: dashboard/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: dashboard-web-1
+  creationTimestamp: null
+spec:
+  template:
+    spec:
+      containers:
+      - image: shipasoftware/go-app:v2
+        name: dashboard-web-1
+  replicas: 1
+status: {}
+---
+# Source: dashboard/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: dashboard-web-1
+spec:
+  ports:
+  - port: 9090
+---
+# Source: dashboard/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: dashboard-settings
+data:
+  LOG_LEVEL: info
+`
+	tests := []struct {
+		name     string
+		current  string
+		proposed string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "changed, added and removed resources",
+			current:  current,
+			proposed: proposed,
+			want: `--- current/Certificate/istio-system/dashboard-cname-theketch-io
++++ /dev/null
+@@ -1,7 +0,0 @@
+-apiVersion: cert-manager.io/v1
+-kind: Certificate
+-metadata:
+-  name: dashboard-cname-theketch-io
+-  namespace: istio-system
+-spec:
+-  secretName: dashboard-cname-theketch-io
+--- /dev/null
++++ proposed/ConfigMap/dashboard-settings
+@@ -0,0 +1,6 @@
++apiVersion: v1
++data:
++  LOG_LEVEL: info
++kind: ConfigMap
++metadata:
++  name: dashboard-settings
+--- current/Deployment/dashboard-web-1
++++ proposed/Deployment/dashboard-web-1
+@@ -7,5 +7,5 @@
+   template:
+     spec:
+       containers:
+-      - image: shipasoftware/go-app:v1
++      - image: shipasoftware/go-app:v2
+         name: dashboard-web-1
+`,
+		},
+		{
+			name:     "same resources",
+			current:  current,
+			proposed: current,
+		},
+		{
+			name:     "new app",
+			proposed: "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: dashboard-web-1\n",
+			want: `--- /dev/null
++++ proposed/Service/dashboard-web-1
+@@ -0,0 +1,4 @@
++apiVersion: v1
++kind: Service
++metadata:
++  name: dashboard-web-1
+`,
+		},
+		{
+			name:     "invalid manifest",
+			current:  current,
+			proposed: "kind: Service\n  metadata: {}\n:",
+			wantErr:  "failed to parse the proposed manifest: error converting YAML to JSON: yaml: line 2: mapping values are not allowed in this context",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffManifests(tt.current, tt.proposed)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,13 +1,23 @@
 package chart
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 
 	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
 	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
@@ -49,15 +59,7 @@ type InstallOption func(install *action.Install)
 // UpdateChart checks if the app chart is already installed and performs "helm install" or "helm update" operation.
 func (c HelmClient) UpdateChart(appChrt ApplicationChart, config ChartConfig, opts ...InstallOption) (*release.Release, error) {
 	appName := appChrt.AppName()
-	files, err := appChrt.bufferedFiles(config)
-	if err != nil {
-		return nil, err
-	}
-	chrt, err := loader.LoadFiles(files)
-	if err != nil {
-		return nil, err
-	}
-	vals, err := appChrt.getValues()
+	chrt, vals, err := appChrt.load(config)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +83,109 @@ func (c HelmClient) UpdateChart(appChrt ApplicationChart, config ChartConfig, op
 	return updateClient.Run(appName, chrt, vals)
 }
 
+// Capabilities returns the kubernetes version and API versions of the cluster,
+// so that charts rendered by RenderChart match the charts the controller installs to the cluster.
+// Helm's default capabilities are returned if the cluster is unreachable.
+func Capabilities(client discovery.DiscoveryInterface) *chartutil.Capabilities {
+	kubeVersion, err := client.ServerVersion()
+	if err != nil {
+		return chartutil.DefaultCapabilities
+	}
+	apiVersions, err := action.GetVersionSet(client)
+	if err != nil {
+		return chartutil.DefaultCapabilities
+	}
+	return &chartutil.Capabilities{
+		APIVersions: apiVersions,
+		KubeVersion: chartutil.KubeVersion{
+			Version: kubeVersion.GitVersion,
+			Major:   kubeVersion.Major,
+			Minor:   kubeVersion.Minor,
+		},
+	}
+}
+
+// RenderChart renders manifests of the app chart for a cluster with the capabilities without installing it like "helm template" does.
+// Helm's default capabilities are used if caps is nil.
+func RenderChart(appChrt ApplicationChart, config ChartConfig, namespace string, caps *chartutil.Capabilities) (string, error) {
+	chrt, vals, err := appChrt.load(config)
+	if err != nil {
+		return "", err
+	}
+	if caps == nil {
+		caps = chartutil.DefaultCapabilities
+	}
+	// a client-only install always renders with the default capabilities,
+	// so the install gets the capabilities, a fake kube client and in-memory releases instead.
+	mem := driver.NewMemory()
+	mem.SetNamespace(namespace)
+	cfg := &action.Configuration{
+		Capabilities: caps,
+		KubeClient:   &kubefake.PrintingKubeClient{Out: ioutil.Discard},
+		Releases:     storage.Init(mem),
+		Log:          func(string, ...interface{}) {},
+	}
+	clientInstall := action.NewInstall(cfg)
+	clientInstall.DryRun = true
+	clientInstall.ReleaseName = appChrt.AppName()
+	clientInstall.Namespace = namespace
+	rel, err := clientInstall.Run(chrt, vals)
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// DeployedManifest returns a manifest of the deployed helm release of the app.
+// Releases are read with the storage driver set by HELM_DRIVER like the controller does, Secrets by default.
+// The manifest is empty if the app has no deployed release.
+func DeployedManifest(client kubernetes.Interface, namespace, appName string) (string, error) {
+	d, err := releaseDriver(client, namespace, os.Getenv("HELM_DRIVER"))
+	if err != nil {
+		return "", err
+	}
+	releases := storage.Init(d)
+	rel, err := releases.Deployed(appName)
+	if errors.Is(err, driver.ErrNoDeployedReleases) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return rel.Manifest, nil
+}
+
+// releaseDriver returns the storage driver of helm releases with the given name, it selects drivers like action.Configuration.Init does.
+func releaseDriver(client kubernetes.Interface, namespace, name string) (driver.Driver, error) {
+	switch name {
+	case "secret", "secrets", "":
+		return driver.NewSecrets(client.CoreV1().Secrets(namespace)), nil
+	case "configmap", "configmaps":
+		return driver.NewConfigMaps(client.CoreV1().ConfigMaps(namespace)), nil
+	case "memory":
+		return nil, errors.New("releases of the memory helm driver are only available to the controller")
+	default:
+		return nil, fmt.Errorf("unknown helm driver %q", name)
+	}
+}
+
+// load returns the helm chart and its values.
+func (chrt ApplicationChart) load(config ChartConfig) (*chart.Chart, map[string]interface{}, error) {
+	files, err := chrt.bufferedFiles(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	helmChrt, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, nil, err
+	}
+	vals, err := chrt.getValues()
+	if err != nil {
+		return nil, nil, err
+	}
+	return helmChrt, vals, nil
+}
+
 // DeleteChart uninstalls the app's helm release. It doesn't return an error if the release is not found.
 func (c HelmClient) DeleteChart(appName string) error {
 	uninstall := action.NewUninstall(c.cfg)
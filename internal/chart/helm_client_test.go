@@ -0,0 +1,199 @@
+package chart
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+func TestDeployedManifest(t *testing.T) {
+	deployed := &release.Release{
+		Name:      "dashboard",
+		Namespace: "ketch-framework",
+		Version:   1,
+		Info:      &release.Info{Status: release.StatusDeployed},
+		Manifest:  "kind: Deployment",
+	}
+	tests := []struct {
+		name         string
+		helmDriver   string
+		storeDriver  func(client *fake.Clientset) driver.Driver
+		wantManifest string
+		wantErr      string
+	}{
+		{
+			name:       "secrets by default",
+			helmDriver: "",
+			storeDriver: func(client *fake.Clientset) driver.Driver {
+				return driver.NewSecrets(client.CoreV1().Secrets("ketch-framework"))
+			},
+			wantManifest: "kind: Deployment",
+		},
+		{
+			name:       "configmaps",
+			helmDriver: "configmap",
+			storeDriver: func(client *fake.Clientset) driver.Driver {
+				return driver.NewConfigMaps(client.CoreV1().ConfigMaps("ketch-framework"))
+			},
+			wantManifest: "kind: Deployment",
+		},
+		{
+			name:       "release stored with another driver",
+			helmDriver: "secret",
+			storeDriver: func(client *fake.Clientset) driver.Driver {
+				return driver.NewConfigMaps(client.CoreV1().ConfigMaps("ketch-framework"))
+			},
+			wantManifest: "",
+		},
+		{
+			name:       "memory",
+			helmDriver: "memory",
+			storeDriver: func(client *fake.Clientset) driver.Driver {
+				return driver.NewMemory()
+			},
+			wantErr: "releases of the memory helm driver are only available to the controller",
+		},
+		{
+			name:       "unknown driver",
+			helmDriver: "sql",
+			storeDriver: func(client *fake.Clientset) driver.Driver {
+				return driver.NewMemory()
+			},
+			wantErr: `unknown helm driver "sql"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer os.Setenv("HELM_DRIVER", os.Getenv("HELM_DRIVER"))
+			require.Nil(t, os.Setenv("HELM_DRIVER", tt.helmDriver))
+
+			client := fake.NewSimpleClientset()
+			require.Nil(t, tt.storeDriver(client).Create("sh.helm.release.v1.dashboard.v1", deployed))
+
+			got, err := DeployedManifest(client, "ketch-framework", "dashboard")
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantManifest, got)
+		})
+	}
+}
+
+// unreachableDiscovery is a discovery client of a cluster that can't be reached.
+type unreachableDiscovery struct {
+	*fakediscovery.FakeDiscovery
+}
+
+func (d unreachableDiscovery) ServerVersion() (*version.Info, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestCapabilities(t *testing.T) {
+	cronJobs := []*metav1.APIResourceList{
+		{GroupVersion: "batch/v1", APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob"}}},
+	}
+	tests := []struct {
+		name            string
+		client          discovery.DiscoveryInterface
+		wantKubeVersion string
+		wantCronJobV1   bool
+	}{
+		{
+			name: "cluster serving batch/v1 cronjobs",
+			client: &fakediscovery.FakeDiscovery{
+				Fake:               &k8stesting.Fake{Resources: cronJobs},
+				FakedServerVersion: &version.Info{GitVersion: "v1.21.2", Major: "1", Minor: "21"},
+			},
+			wantKubeVersion: "v1.21.2",
+			wantCronJobV1:   true,
+		},
+		{
+			name: "unreachable cluster",
+			client: unreachableDiscovery{
+				FakeDiscovery: &fakediscovery.FakeDiscovery{Fake: &k8stesting.Fake{Resources: cronJobs}},
+			},
+			wantKubeVersion: chartutil.DefaultCapabilities.KubeVersion.Version,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caps := Capabilities(tt.client)
+			require.Equal(t, tt.wantKubeVersion, caps.KubeVersion.Version)
+			require.Equal(t, tt.wantCronJobV1, caps.APIVersions.Has("batch/v1/CronJob"))
+		})
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard-cronjob"},
+		Spec: ketchv1.AppSpec{
+			Type: ketchv1.JobAppType,
+			Job:  &ketchv1.JobSpec{Schedule: "*/5 * * * *"},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:           "shipasoftware/go-app:v1",
+					Version:         1,
+					Processes:       []ketchv1.ProcessSpec{{Name: "web", Units: intRef(1), Cmd: []string{"python"}}},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+			Framework: "framework",
+		},
+	}
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName:     "ketch-framework",
+			IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.TraefikIngressControllerType},
+		},
+	}
+	appChrt, err := New(app, framework,
+		WithTemplates(templates.TraefikDefaultTemplates),
+		WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: {{Port: 9090, Protocol: "TCP"}}}))
+	require.Nil(t, err)
+
+	tests := []struct {
+		name           string
+		caps           *chartutil.Capabilities
+		wantAPIVersion string
+	}{
+		{
+			name:           "default capabilities",
+			wantAPIVersion: "apiVersion: batch/v1beta1",
+		},
+		{
+			name: "cluster serving batch/v1 cronjobs",
+			caps: &chartutil.Capabilities{
+				APIVersions: chartutil.VersionSet{"v1", "batch/v1", "batch/v1/CronJob"},
+				KubeVersion: chartutil.KubeVersion{Version: "v1.21.2", Major: "1", Minor: "21"},
+			},
+			wantAPIVersion: "apiVersion: batch/v1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := RenderChart(*appChrt, NewChartConfig(*app), "ketch-framework", tt.caps)
+			require.Nil(t, err)
+			require.Contains(t, manifest, "kind: CronJob")
+			require.Contains(t, manifest, tt.wantAPIVersion)
+		})
+	}
+}
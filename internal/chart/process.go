@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 )
@@ -23,6 +24,14 @@ type process struct {
 	ServicePorts      []v1.ServicePort   `json:"servicePorts"`
 	PublicServicePort int32              `json:"publicServicePort,omitempty"`
 	Env               []ketchv1.Env      `json:"env"`
+	EnvFrom           []v1.EnvFromSource `json:"envFrom,omitempty"`
+
+	Autoscaling *ketchv1.AutoscalingSpec `json:"autoscaling,omitempty"`
+
+	DisruptionBudget *ketchv1.DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+
+	Sidecars       []ketchv1.Container `json:"sidecars,omitempty"`
+	InitContainers []ketchv1.Container `json:"initContainers,omitempty"`
 
 	PodExtra podExtra `json:"extra"`
 }
@@ -30,11 +39,20 @@ type process struct {
 type podExtra struct {
 	SecurityContext      *v1.SecurityContext      `json:"securityContext,omitempty"`
 	ResourceRequirements *v1.ResourceRequirements `json:"resourceRequirements,omitempty"`
-	NodeSelectorTerms    []v1.NodeSelectorTerm    `json:"nodeSelectorTerms,omitempty"`
 	VolumeMounts         []v1.VolumeMount         `json:"volumeMounts,omitempty"`
-	ReadinessProbe       *v1.Probe                `json:"readinessProbe,omitempty"`
-	LivenessProbe        *v1.Probe                `json:"livenessProbe,omitempty"`
+	Volumes              []v1.Volume              `json:"volumes,omitempty"`
+	ReadinessProbe       *ketchv1.Probe           `json:"readinessProbe,omitempty"`
+	LivenessProbe        *ketchv1.Probe           `json:"livenessProbe,omitempty"`
+	StartupProbe         *ketchv1.Probe           `json:"startupProbe,omitempty"`
 	Lifecycle            *v1.Lifecycle            `json:"lifecycle,omitempty"`
+
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	NodeSelector              map[string]string             `json:"nodeSelector,omitempty"`
+	Affinity                  *v1.Affinity                  `json:"affinity,omitempty"`
+	Tolerations               []v1.Toleration               `json:"tolerations,omitempty"`
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	PriorityClassName         string                        `json:"priorityClassName,omitempty"`
 }
 
 type processOption func(p *process) error
@@ -48,6 +66,20 @@ func withUnits(units *int) processOption {
 	}
 }
 
+func withAutoscaling(autoscaling *ketchv1.AutoscalingSpec) processOption {
+	return func(p *process) error {
+		p.Autoscaling = autoscaling
+		return nil
+	}
+}
+
+func withEnvFrom(envFrom []v1.EnvFromSource) processOption {
+	return func(p *process) error {
+		p.EnvFrom = envFrom
+		return nil
+	}
+}
+
 func withCmd(cmd []string) processOption {
 	return func(p *process) error {
 		p.Cmd = cmd
@@ -59,23 +91,26 @@ func withCmd(cmd []string) processOption {
 type portConfigurator interface {
 	ContainerPortsForProcess(process string) []v1.ContainerPort
 	ServicePortsForProcess(process string) []v1.ServicePort
-	Probes(port int32) (Probes, error)
+	Probes(process string, port int32) (Probes, error)
 }
 
+// withPortsAndProbes sets ports and probes of the process, a process without ports gets only the probes configured for it.
 func withPortsAndProbes(c portConfigurator) processOption {
 	return func(p *process) error {
 		p.ServicePorts = c.ServicePortsForProcess(p.Name)
 		p.ContainerPorts = c.ContainerPortsForProcess(p.Name)
-		if len(p.ContainerPorts) == 0 || len(p.ServicePorts) == 0 {
-			return nil
+		var port int32
+		if p.hasOpenPort() {
+			port = p.ContainerPorts[0].ContainerPort
+			p.PublicServicePort = p.ServicePorts[0].Port
 		}
-		probes, err := c.Probes(p.ContainerPorts[0].ContainerPort)
+		probes, err := c.Probes(p.Name, port)
 		if err != nil {
 			return err
 		}
-		p.PublicServicePort = p.ServicePorts[0].Port
 		p.PodExtra.LivenessProbe = probes.Liveness
 		p.PodExtra.ReadinessProbe = probes.Readiness
+		p.PodExtra.StartupProbe = probes.Startup
 		return nil
 	}
 }
@@ -87,6 +122,62 @@ func withSecurityContext(securityContext *v1.SecurityContext) processOption {
 	}
 }
 
+// withResourceRequirements sets resources of the process' containers, defaults are used if the process has no resources.
+func withResourceRequirements(resources *v1.ResourceRequirements, defaults *v1.ResourceRequirements) processOption {
+	return func(p *process) error {
+		if resources == nil {
+			resources = defaults
+		}
+		p.PodExtra.ResourceRequirements = resources
+		return nil
+	}
+}
+
+// withVolumeMounts mounts volumes to the process' containers,
+// only volumes mounted by the process are added to its pods.
+func withVolumeMounts(mounts []v1.VolumeMount, volumes []v1.Volume) processOption {
+	return func(p *process) error {
+		for _, mount := range mounts {
+			for _, volume := range volumes {
+				if volume.Name == mount.Name {
+					p.PodExtra.Volumes = append(p.PodExtra.Volumes, volume)
+					break
+				}
+			}
+		}
+		p.PodExtra.VolumeMounts = mounts
+		return nil
+	}
+}
+
+// withContainers adds sidecars and init containers to the process' pods along with the volumes they mount.
+func withContainers(sidecars []ketchv1.Container, initContainers []ketchv1.Container, volumes []v1.Volume) processOption {
+	return func(p *process) error {
+		p.Sidecars = sidecars
+		p.InitContainers = initContainers
+		for _, container := range append(append([]ketchv1.Container{}, sidecars...), initContainers...) {
+			for _, mount := range container.VolumeMounts {
+				for _, volume := range volumes {
+					if volume.Name == mount.Name && !hasVolume(p.PodExtra.Volumes, volume.Name) {
+						p.PodExtra.Volumes = append(p.PodExtra.Volumes, volume)
+						break
+					}
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func hasVolume(volumes []v1.Volume, name string) bool {
+	for _, volume := range volumes {
+		if volume.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func withLifecycle(lc *v1.Lifecycle) processOption {
 	return func(p *process) error {
 		p.PodExtra.Lifecycle = lc
@@ -94,6 +185,49 @@ func withLifecycle(lc *v1.Lifecycle) processOption {
 	}
 }
 
+// withTerminationGracePeriod sets how long the process' pods have to run preStop handlers and shut down.
+func withTerminationGracePeriod(seconds *int64) processOption {
+	return func(p *process) error {
+		p.PodExtra.TerminationGracePeriodSeconds = seconds
+		return nil
+	}
+}
+
+// withScheduling sets how the process' pods are assigned to nodes, the scheduling is complemented with the framework's defaults.
+// Topology spread constraints without a label selector spread pods with the given labels.
+func withScheduling(scheduling *ketchv1.SchedulingSpec, defaults *ketchv1.SchedulingSpec, podLabels map[string]string) processOption {
+	return func(p *process) error {
+		scheduling := scheduling.WithDefaults(defaults)
+		if scheduling == nil {
+			return nil
+		}
+		for i := range scheduling.TopologySpreadConstraints {
+			if scheduling.TopologySpreadConstraints[i].LabelSelector == nil {
+				scheduling.TopologySpreadConstraints[i].LabelSelector = &metav1.LabelSelector{MatchLabels: podLabels}
+			}
+		}
+		p.PodExtra.NodeSelector = scheduling.NodeSelector
+		p.PodExtra.Affinity = scheduling.Affinity
+		p.PodExtra.Tolerations = scheduling.Tolerations
+		p.PodExtra.TopologySpreadConstraints = scheduling.TopologySpreadConstraints
+		p.PodExtra.PriorityClassName = scheduling.PriorityClassName
+		return nil
+	}
+}
+
+// withDisruptionBudget sets a disruption budget of the process, nil means the process doesn't get a PodDisruptionBudget.
+func withDisruptionBudget(budget *ketchv1.DisruptionBudgetSpec) processOption {
+	return func(p *process) error {
+		if budget != nil {
+			if err := budget.Validate(); err != nil {
+				return fmt.Errorf("process %s: %w", p.Name, err)
+			}
+		}
+		p.DisruptionBudget = budget
+		return nil
+	}
+}
+
 func newProcess(name string, isRoutable bool, opts ...processOption) (*process, error) {
 	process := &process{
 		Name:     name,
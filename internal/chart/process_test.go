@@ -8,15 +8,17 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/utils/conversions"
 )
 
 type mockConfigurator struct {
 	servicePorts   map[string][]v1.ServicePort
 	containerPorts map[string][]v1.ContainerPort
+	probes         map[string]Probes
 }
 
-func (m mockConfigurator) Probes(port int32) (Probes, error) {
-	return Probes{}, nil
+func (m mockConfigurator) Probes(process string, port int32) (Probes, error) {
+	return m.probes[process], nil
 }
 
 func (m mockConfigurator) ServicePortsForProcess(process string) []v1.ServicePort {
@@ -52,6 +54,7 @@ func TestNewProcess(t *testing.T) {
 				withCmd([]string{"gunicorn", "-p", "8080"}),
 				withUnits(intRef(5)),
 				withLifecycle(&v1.Lifecycle{}),
+				withTerminationGracePeriod(conversions.Int64Ptr(45)),
 				withSecurityContext(&v1.SecurityContext{Privileged: boolRef(true)}),
 				withPortsAndProbes(
 					mockConfigurator{
@@ -86,13 +89,46 @@ func TestNewProcess(t *testing.T) {
 					{Name: "PORT_web", Value: "9999"},
 				},
 				PodExtra: podExtra{
-					Lifecycle: &v1.Lifecycle{},
+					Lifecycle:                     &v1.Lifecycle{},
+					TerminationGracePeriodSeconds: conversions.Int64Ptr(45),
 					SecurityContext: &v1.SecurityContext{
 						Privileged: boolRef(true),
 					},
 				},
 			},
 		},
+		{
+			name:        "sidecars and init containers with volumes",
+			processName: "worker",
+			options: []processOption{
+				withVolumeMounts([]v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}}, []v1.Volume{
+					{Name: "uploads", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+					{Name: "certs", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "certs"}}},
+				}),
+				withContainers(
+					[]ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0", VolumeMounts: []v1.VolumeMount{{Name: "certs", MountPath: "/certs"}}}},
+					[]ketchv1.Container{{Name: "fetch", Image: "busybox:1.34", VolumeMounts: []v1.VolumeMount{{Name: "uploads", MountPath: "/data"}}}},
+					[]v1.Volume{
+						{Name: "uploads", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+						{Name: "certs", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "certs"}}},
+					},
+				),
+				withPortsAndProbes(mockConfigurator{}),
+			},
+			want: &process{
+				Name:           "worker",
+				Units:          ketchv1.DefaultNumberOfUnits,
+				Sidecars:       []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0", VolumeMounts: []v1.VolumeMount{{Name: "certs", MountPath: "/certs"}}}},
+				InitContainers: []ketchv1.Container{{Name: "fetch", Image: "busybox:1.34", VolumeMounts: []v1.VolumeMount{{Name: "uploads", MountPath: "/data"}}}},
+				PodExtra: podExtra{
+					VolumeMounts: []v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}},
+					Volumes: []v1.Volume{
+						{Name: "uploads", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}},
+						{Name: "certs", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "certs"}}},
+					},
+				},
+			},
+		},
 		{
 			name:        "no service port",
 			processName: "web",
@@ -177,6 +213,29 @@ func TestNewProcess(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "non routable process without ports has probes",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withPortsAndProbes(
+					&mockConfigurator{
+						probes: map[string]Probes{
+							"worker": {
+								Liveness: &ketchv1.Probe{Exec: &v1.ExecAction{Command: []string{"celery", "inspect", "ping"}}},
+							},
+						},
+					},
+				),
+			},
+			want: &process{
+				Name:  "worker",
+				Units: ketchv1.DefaultNumberOfUnits,
+				PodExtra: podExtra{
+					LivenessProbe: &ketchv1.Probe{Exec: &v1.ExecAction{Command: []string{"celery", "inspect", "ping"}}},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,56 @@
+package chart
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+// ValidateTemplates returns an error if the templates fail to render a helm chart of a sample app of the framework.
+func ValidateTemplates(tpls templates.Templates, framework ketchv1.Framework) error {
+	app := sampleApp(framework.Name)
+	exposedPorts := map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{
+		1: {{Port: DefaultApplicationPort, Protocol: "TCP"}},
+	}
+	appChrt, err := New(&app, &framework, WithTemplates(tpls), WithExposedPorts(exposedPorts))
+	if err != nil {
+		return err
+	}
+	_, err = RenderChart(*appChrt, NewChartConfig(app), framework.Spec.NamespaceName, nil)
+	return err
+}
+
+// sampleApp returns an app with a routable and a background process, so the templates render all their main resources.
+func sampleApp(framework string) ketchv1.App {
+	units := 1
+	return ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "sample",
+			Generation: 1,
+		},
+		Spec: ketchv1.AppSpec{
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipasoftware/go-app:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: &units, Cmd: []string{"./app"}},
+						{Name: "worker", Units: &units, Cmd: []string{"./worker"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{
+						Weight: 100,
+					},
+				},
+			},
+			Env: []ketchv1.Env{
+				{Name: "PORT", Value: "8080"},
+			},
+			Framework: framework,
+			Ingress: ketchv1.IngressSpec{
+				GenerateDefaultCname: true,
+				Cnames:               []string{"sample.theketch.io"},
+			},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/templates"
+)
+
+func TestValidateTemplates(t *testing.T) {
+	framework := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "framework",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-framework",
+			IngressController: ketchv1.IngressControllerSpec{
+				ClassName:       "istio",
+				ServiceEndpoint: "10.10.10.10",
+				ClusterIssuer:   "letsencrypt-production",
+				IngressType:     ketchv1.IstioIngressControllerType,
+			},
+		},
+	}
+	gatewayFramework := framework.DeepCopy()
+	gatewayFramework.Spec.IngressController.IngressType = ketchv1.GatewayAPIIngressControllerType
+	gatewayFramework.Spec.IngressController.Gateway = &ketchv1.GatewayReference{Name: "public"}
+
+	brokenTemplates := templates.Templates{Yamls: map[string]string{}}
+	for name, content := range templates.IstioDefaultTemplates.Yamls {
+		brokenTemplates.Yamls[name] = content
+	}
+	brokenTemplates.Yamls["service.yaml"] = "{{ range $.Values.app.deployments }}"
+
+	tests := []struct {
+		name      string
+		templates templates.Templates
+		framework ketchv1.Framework
+		wantErr   bool
+	}{
+		{
+			name:      "istio templates",
+			templates: templates.IstioDefaultTemplates,
+			framework: framework,
+		},
+		{
+			name:      "traefik templates",
+			templates: templates.TraefikDefaultTemplates,
+			framework: framework,
+		},
+		{
+			name:      "nginx templates",
+			templates: templates.NginxDefaultTemplates,
+			framework: framework,
+		},
+		{
+			name:      "gateway-api templates",
+			templates: templates.GatewayAPIDefaultTemplates,
+			framework: *gatewayFramework,
+		},
+		{
+			name:      "broken template",
+			templates: brokenTemplates,
+			framework: framework,
+			wantErr:   true,
+		},
+		{
+			name:      "template rendering invalid yaml",
+			templates: templates.Templates{Yamls: map[string]string{"configmap.yaml": "kind: ConfigMap\n  metadata: {{ $.Values.app.name }}\n:"}},
+			framework: framework,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplates(tt.templates, tt.framework)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
@@ -19,25 +19,40 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/canary"
 	"github.com/shipa-corp/ketch/internal/chart"
 	"github.com/shipa-corp/ketch/internal/templates"
+	"github.com/shipa-corp/ketch/internal/utils"
 )
 
 // AppReconciler reconciles a App object.
@@ -49,6 +64,8 @@ type AppReconciler struct {
 	HelmFactoryFn  helmFactoryFn
 	Now            timeNowFn
 	Recorder       record.EventRecorder
+	// QuerierFactoryFn returns a querier used to check canary analysis rules.
+	QuerierFactoryFn querierFactoryFn
 }
 
 // timeNowFn knows how to get the current time.
@@ -57,6 +74,8 @@ type timeNowFn func() time.Time
 
 type helmFactoryFn func(namespace string) (Helm, error)
 
+type querierFactoryFn func(address string) (canary.Querier, error)
+
 // Helm has methods to update/delete helm charts.
 type Helm interface {
 	UpdateChart(appChrt chart.ApplicationChart, config chart.ChartConfig, opts ...chart.InstallOption) (*release.Release, error)
@@ -70,10 +89,15 @@ type Helm interface {
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="batch",resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="autoscaling",resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="policy",resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="networking.istio.io",resources=gateways,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="networking.istio.io",resources=virtualservices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="cert-manager.io",resources=certificates,verbs=get;list;watch;create;update;patch;delete
@@ -85,6 +109,7 @@ type Helm interface {
 // +kubebuilder:rbac:groups="traefik.containo.us",resources=ingressroutes/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="traefik.containo.us",resources=traefikservices,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="traefik.containo.us",resources=traefikservices/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="gateway.networking.k8s.io",resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update;delete
 
 func (r *AppReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -105,13 +130,18 @@ func (r *AppReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		result ctrl.Result
 	)
 	scheduleResult := r.reconcile(ctx, &app)
-	if scheduleResult.status == v1.ConditionFalse {
+	switch scheduleResult.status {
+	case v1.ConditionFalse:
 		// we have to return an error to run reconcile again.
 		err = fmt.Errorf(scheduleResult.message)
 		reason := AppReconcileReason{AppName: app.Name, DeploymentCount: app.Spec.DeploymentsCount}
 		r.Recorder.Event(&app, v1.EventTypeWarning, reason.String(), err.Error())
-	} else {
+	case v1.ConditionUnknown:
+		// the rollout is on hold until a pre-deploy hook completes,
+		// the app is reconciled again once the hook's Job changes.
+	default:
 		app.Status.Framework = scheduleResult.framework
+		app.RecordRevision(metav1.NewTime(r.Now()))
 		reason := AppReconcileReason{AppName: app.Name, DeploymentCount: app.Spec.DeploymentsCount}
 		r.Recorder.Event(&app, v1.EventTypeNormal, reason.String(), "success")
 	}
@@ -120,11 +150,23 @@ func (r *AppReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return result, err
 	}
 
-	// use canary step interval as the timeout when canary is active
-	if app.Spec.Canary.Active {
+	// use canary step interval as the timeout when canary is active,
+	// a paused canary doesn't need to be requeued because resuming it updates the app.
+	if app.Spec.Canary.Active && !app.Spec.Canary.Paused {
 		result = ctrl.Result{RequeueAfter: app.Spec.Canary.StepTimeInteval}
 	}
 
+	// requeue to remove the idle deployment of a blue-green deployment in time
+	if cleanupTime := app.BlueGreenCleanupTime(); cleanupTime != nil {
+		if after := cleanupTime.Sub(r.Now()); result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result = ctrl.Result{RequeueAfter: after}
+		}
+	}
+
+	if after := scheduleResult.requeueAfter; after > 0 && (result.RequeueAfter == 0 || after < result.RequeueAfter) {
+		result = ctrl.Result{RequeueAfter: after}
+	}
+
 	if scheduleResult.useTimeout {
 		// set default timeout
 		result = ctrl.Result{RequeueAfter: reconcileTimeout}
@@ -138,6 +180,8 @@ type reconcileResult struct {
 	message    string
 	framework  *v1.ObjectReference
 	useTimeout bool
+	// requeueAfter if set, the app is reconciled again after the given duration.
+	requeueAfter time.Duration
 }
 
 func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconcileResult {
@@ -161,7 +205,7 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconci
 			message: fmt.Sprintf(`framework "%s" is not linked to a kubernetes namespace`, framework.Name),
 		}
 	}
-	tpls, err := r.TemplateReader.Get(app.TemplatesConfigMapName(framework.Spec.IngressController.IngressType))
+	tpls, err := r.TemplateReader.Get(framework.TemplatesConfigMapName())
 	if err != nil {
 		return reconcileResult{
 			status:  v1.ConditionFalse,
@@ -174,9 +218,30 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconci
 			message: fmt.Sprintf(`you have reached the limit of apps`),
 		}
 	}
+	// remove the idle deployment once the previous deployment of a blue-green deployment has been kept long enough.
+	if cleanupTime := app.BlueGreenCleanupTime(); cleanupTime != nil && !cleanupTime.After(r.Now()) {
+		app.CompleteBlueGreen()
+		if err := r.Update(ctx, app); err != nil {
+			return reconcileResult{
+				status:     v1.ConditionFalse,
+				message:    fmt.Sprintf("failed to update app crd: %v", err),
+				useTimeout: true,
+			}
+		}
+	}
+
+	checksum, err := ConfigChecksum(ctx, r, app, framework.Status.Namespace.Name)
+	if err != nil {
+		return reconcileResult{
+			status:  v1.ConditionFalse,
+			message: fmt.Sprintf("failed to calculate checksum of the app's configuration: %v", err),
+		}
+	}
+
 	options := []chart.Option{
 		chart.WithExposedPorts(app.ExposedPorts()),
 		chart.WithTemplates(*tpls),
+		chart.WithConfigChecksum(checksum),
 	}
 
 	appChrt, err := chart.New(app, &framework, options...)
@@ -206,6 +271,11 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconci
 		}
 	}
 
+	// a deployment is rolled out once its pre-deploy hook has succeeded.
+	if result := r.runPreDeployHooks(ctx, app, &framework); result != nil {
+		return *result
+	}
+
 	// check for canary deployment
 	if app.Spec.Canary.Active {
 		// ensures that the canary deployment exists
@@ -242,6 +312,48 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconci
 			}
 		}
 
+		// Check analysis rules before the next step, the step is put on hold or the canary is rolled back if a rule fails.
+		if app.Spec.Canary.Analysis != nil && app.Spec.Canary.StepDue(metav1.NewTime(r.Now())) {
+			if err := r.analyzeCanary(ctx, app, framework); err != nil {
+				abort := app.HoldCanary()
+				if abort {
+					app.DoRollback()
+				}
+				if e := r.Update(ctx, app); e != nil {
+					return reconcileResult{
+						status:     v1.ConditionFalse,
+						message:    fmt.Sprintf("failed to update app crd: %v", e),
+						useTimeout: true,
+					}
+				}
+				if !abort {
+					return reconcileResult{
+						status:     v1.ConditionFalse,
+						message:    fmt.Sprintf("canary analysis failed, the next step is on hold: %v", err),
+						useTimeout: true,
+					}
+				}
+				// route all traffic back to the primary deployment.
+				rollbackChrt, e := chart.New(app, &framework, options...)
+				if e != nil {
+					return reconcileResult{
+						status:  v1.ConditionFalse,
+						message: e.Error(),
+					}
+				}
+				if _, e := helmClient.UpdateChart(*rollbackChrt, chart.NewChartConfig(*app)); e != nil {
+					return reconcileResult{
+						status:  v1.ConditionFalse,
+						message: fmt.Sprintf("failed to update helm chart: %v", e),
+					}
+				}
+				return reconcileResult{
+					status:  v1.ConditionFalse,
+					message: fmt.Sprintf("canary analysis failed, the canary has been rolled back: %v", err),
+				}
+			}
+		}
+
 		// Once all pods are running then Perform canary deployment.
 		if err = app.DoCanary(metav1.NewTime(r.Now())); err != nil {
 			return reconcileResult{
@@ -264,10 +376,62 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App) reconci
 			message: fmt.Sprintf("failed to update helm chart: %v", err),
 		}
 	}
-	return reconcileResult{
+	if app.IsJob() {
+		if err := r.runScheduledJob(ctx, app, targetNamespace); err != nil {
+			return reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to run scheduled job: %v", err),
+			}
+		}
+		if err := r.updateJobStatus(ctx, app, targetNamespace); err != nil {
+			return reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to get jobs of the app: %v", err),
+			}
+		}
+	}
+	if err := r.removeDeployHookJobs(ctx, app, targetNamespace); err != nil {
+		return reconcileResult{
+			status:  v1.ConditionFalse,
+			message: fmt.Sprintf("failed to remove jobs of deploy hooks: %v", err),
+		}
+	}
+	pending, err := r.runPostDeployHooks(ctx, app, &framework)
+	if err != nil {
+		return reconcileResult{
+			status:  v1.ConditionFalse,
+			message: fmt.Sprintf("failed to run post-deploy hook: %v", err),
+		}
+	}
+	result := reconcileResult{
 		framework: ref,
 		status:    v1.ConditionTrue,
 	}
+	if pending {
+		result.requeueAfter = deployHookPollInterval
+	}
+	return result
+}
+
+// analyzeCanary checks the canary analysis rules against the metrics of the canary deployment.
+func (r *AppReconciler) analyzeCanary(ctx context.Context, app *ketchv1.App, framework ketchv1.Framework) error {
+	analysis := app.Spec.Canary.Analysis
+	// the canary doesn't receive traffic yet, there is nothing to analyze.
+	if app.Spec.Deployments[1].RoutingSettings.Weight == 0 {
+		return nil
+	}
+	if r.QuerierFactoryFn == nil {
+		return errors.New("metrics querier is not configured")
+	}
+	querier, err := r.QuerierFactoryFn(analysis.PrometheusURL)
+	if err != nil {
+		return err
+	}
+	params, err := canary.NewQueryParams(*app, framework)
+	if err != nil {
+		return err
+	}
+	return canary.Analyze(ctx, querier, *analysis, framework.Spec.IngressController.IngressType, *params)
 }
 
 // check if timeout has expired
@@ -353,9 +517,248 @@ func (r *AppReconciler) deleteChart(ctx context.Context, appName string) error {
 
 }
 
+// ConfigChecksum returns a checksum of ConfigMaps and Secrets the app gets environment variables from.
+// The checksum is empty if the app doesn't reference any ConfigMap or Secret.
+func ConfigChecksum(ctx context.Context, reader client.Reader, app *ketchv1.App, namespace string) (string, error) {
+	configMaps := app.ConfigMapNames()
+	secrets := app.SecretNames()
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return "", nil
+	}
+	hash := sha256.New()
+	for _, name := range configMaps {
+		configMap := v1.ConfigMap{}
+		err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "configmap/%s\n", name)
+		for _, key := range sortedKeys(configMap.Data) {
+			fmt.Fprintf(hash, "%s=%s\n", key, configMap.Data[key])
+		}
+		for _, key := range sortedBinaryKeys(configMap.BinaryData) {
+			fmt.Fprintf(hash, "%s=%x\n", key, configMap.BinaryData[key])
+		}
+	}
+	for _, name := range secrets {
+		secret := v1.Secret{}
+		err := reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &secret)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", err
+		}
+		fmt.Fprintf(hash, "secret/%s\n", name)
+		for _, key := range sortedBinaryKeys(secret.Data) {
+			fmt.Fprintf(hash, "%s=%x\n", key, secret.Data[key])
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedBinaryKeys(data map[string][]byte) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// updateJobStatus sets statuses of the kubernetes Jobs of a Job application and reports their completion with the JobCompleted condition.
+func (r *AppReconciler) updateJobStatus(ctx context.Context, app *ketchv1.App, namespace string) error {
+	// Jobs of deploy hooks are reported with the DeployHooksCompleted condition.
+	notHook, err := labels.NewRequirement(utils.KetchDeployHookLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		return err
+	}
+	selector := labels.SelectorFromSet(labels.Set{utils.KetchAppNameLabel: app.Name}).Add(*notHook)
+	jobs := batchv1.JobList{}
+	if err := r.List(ctx, &jobs, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return err
+	}
+	sort.Slice(jobs.Items, func(i, j int) bool {
+		return jobs.Items[i].CreationTimestamp.Before(&jobs.Items[j].CreationTimestamp) ||
+			jobs.Items[i].CreationTimestamp.Equal(&jobs.Items[j].CreationTimestamp) && jobs.Items[i].Name < jobs.Items[j].Name
+	})
+	statuses := make([]ketchv1.JobStatus, 0, len(jobs.Items))
+	var running, failed []string
+	for _, job := range jobs.Items {
+		statuses = append(statuses, ketchv1.JobStatus{
+			Name:           job.Name,
+			Active:         job.Status.Active,
+			Succeeded:      job.Status.Succeeded,
+			Failed:         job.Status.Failed,
+			StartTime:      job.Status.StartTime,
+			CompletionTime: job.Status.CompletionTime,
+		})
+		switch {
+		case jobHasCondition(job, batchv1.JobFailed):
+			failed = append(failed, job.Name)
+		case !jobHasCondition(job, batchv1.JobComplete):
+			running = append(running, job.Name)
+		}
+	}
+	app.Status.Jobs = statuses
+	now := metav1.NewTime(r.Now())
+	switch {
+	case len(jobs.Items) == 0:
+		// a scheduled job hasn't run yet.
+	case len(failed) > 0:
+		app.SetCondition(ketchv1.JobCompleted, v1.ConditionFalse, fmt.Sprintf("job %s failed", strings.Join(failed, ", ")), now)
+	case len(running) > 0:
+		app.SetCondition(ketchv1.JobCompleted, v1.ConditionUnknown, fmt.Sprintf("job %s is running", strings.Join(running, ", ")), now)
+	default:
+		app.SetCondition(ketchv1.JobCompleted, v1.ConditionTrue, "", now)
+	}
+	return nil
+}
+
+func jobHasCondition(job batchv1.Job, conditionType batchv1.JobConditionType) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == conditionType && c.Status == v1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// runScheduledJob starts a kubernetes Job from the job template of each CronJob of a scheduled Job application
+// when spec.job.run has been incremented since the latest run, see "ketch job run".
+func (r *AppReconciler) runScheduledJob(ctx context.Context, app *ketchv1.App, namespace string) error {
+	if app.Spec.Job == nil {
+		return nil
+	}
+	if app.Spec.Job.Schedule == "" || app.Spec.Job.Run <= app.Status.JobRun {
+		// a job without a schedule runs with a new Job rendered by the chart.
+		app.Status.JobRun = app.Spec.Job.Run
+		return nil
+	}
+	cronJobs, err := r.listCronJobs(ctx, app, namespace)
+	if err != nil {
+		return err
+	}
+	for _, cronJob := range cronJobs {
+		template := batchv1beta1.JobTemplateSpec{}
+		jobTemplate, _, err := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate")
+		if err != nil {
+			return err
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(jobTemplate, &template); err != nil {
+			return err
+		}
+		job := batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("%s-run-%d", cronJob.GetName(), app.Spec.Job.Run),
+				Namespace:       namespace,
+				Labels:          template.Labels,
+				Annotations:     map[string]string{"cronjob.kubernetes.io/instantiate": "manual"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(&cronJob, cronJob.GroupVersionKind())},
+			},
+			Spec: template.Spec,
+		}
+		if err := r.Create(ctx, &job); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	app.Status.JobRun = app.Spec.Job.Run
+	return nil
+}
+
+// listCronJobs returns CronJobs of a Job application, they are served as batch/v1 or as batch/v1beta1 depending on the cluster version.
+func (r *AppReconciler) listCronJobs(ctx context.Context, app *ketchv1.App, namespace string) ([]unstructured.Unstructured, error) {
+	var err error
+	for _, version := range []string{"batch/v1", "batch/v1beta1"} {
+		cronJobs := unstructured.UnstructuredList{}
+		cronJobs.SetAPIVersion(version)
+		cronJobs.SetKind("CronJobList")
+		err = r.List(ctx, &cronJobs, client.InNamespace(namespace), client.MatchingLabels{utils.KetchAppNameLabel: app.Name})
+		if err == nil {
+			return cronJobs.Items, nil
+		}
+		if !meta.IsNoMatchError(err) && !runtime.IsNotRegisteredError(err) {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// appOfJob returns a map function that enqueues the app of a changed kubernetes Job.
+func appOfJob(obj handler.MapObject) []reconcile.Request {
+	appName, ok := obj.Meta.GetLabels()[utils.KetchAppNameLabel]
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: appName}}}
+}
+
+// appsReferencingConfig returns a map function that enqueues apps getting environment variables from a changed ConfigMap or Secret.
+// Apps are looked up with the index of names of their ConfigMaps or Secrets, so changes of other objects, for example Secrets of helm releases, don't list every app.
+func (r *AppReconciler) appsReferencingConfig(field string, namesFn func(app *ketchv1.App) []string) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		ctx := context.Background()
+		apps := ketchv1.AppList{}
+		if err := r.List(ctx, &apps, client.MatchingFields{field: obj.Meta.GetName()}); err != nil {
+			r.Log.Error(err, "failed to list apps")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, app := range apps.Items {
+			if !containsName(namesFn(&app), obj.Meta.GetName()) {
+				continue
+			}
+			framework := ketchv1.Framework{}
+			if err := r.Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+				continue
+			}
+			if framework.Status.Namespace == nil || framework.Status.Namespace.Name != obj.Meta.GetNamespace() {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name}})
+		}
+		return requests
+	}
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(context.Background(), &ketchv1.App{}, appConfigMapsField, func(obj runtime.Object) []string {
+		return obj.(*ketchv1.App).ConfigMapNames()
+	}); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &ketchv1.App{}, appSecretsField, func(obj runtime.Object) []string {
+		return obj.(*ketchv1.App).SecretNames()
+	}); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ketchv1.App{}).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.appsReferencingConfig(appConfigMapsField, (*ketchv1.App).ConfigMapNames),
+		}).
+		Watches(&source.Kind{Type: &v1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: r.appsReferencingConfig(appSecretsField, (*ketchv1.App).SecretNames),
+		}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(appOfJob),
+		}).
 		Complete(r)
 }
 
@@ -9,11 +9,17 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"helm.sh/helm/v3/pkg/release"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/chart"
@@ -246,3 +252,278 @@ func TestParseReconcileReason(t *testing.T) {
 		}
 	}
 }
+
+func TestConfigChecksum(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: ketchv1.AppSpec{
+			EnvFrom: []v1.EnvFromSource{
+				{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+			},
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Processes: []ketchv1.ProcessSpec{
+						{
+							Name: "web",
+							EnvFrom: []v1.EnvFromSource{
+								{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "credentials"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	configMap := func(value string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "ketch-framework"},
+			Data:       map[string]string{"LOG_LEVEL": value},
+		}
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "ketch-framework"},
+		Data:       map[string][]byte{"TOKEN": []byte("token")},
+	}
+	checksum := func(objects ...runtime.Object) string {
+		got, err := ConfigChecksum(context.Background(), fake.NewFakeClientWithScheme(scheme, objects...), app, "ketch-framework")
+		require.Nil(t, err)
+		return got
+	}
+
+	debug := checksum(configMap("debug"), secret)
+	require.NotEmpty(t, debug)
+	require.Equal(t, debug, checksum(configMap("debug"), secret))
+	require.NotEqual(t, debug, checksum(configMap("info"), secret))
+	require.NotEqual(t, debug, checksum(configMap("debug")))
+
+	got, err := ConfigChecksum(context.Background(), fake.NewFakeClientWithScheme(scheme), &ketchv1.App{}, "ketch-framework")
+	require.Nil(t, err)
+	require.Empty(t, got)
+}
+
+func TestAppReconciler_updateJobStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	job := func(name string, conditionType batchv1.JobConditionType) *batchv1.Job {
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "ketch-framework",
+				Labels:    map[string]string{"theketch.io/app-name": "app"},
+			},
+			Status: batchv1.JobStatus{Active: 1},
+		}
+		if len(conditionType) > 0 {
+			j.Status = batchv1.JobStatus{
+				Succeeded:  1,
+				Conditions: []batchv1.JobCondition{{Type: conditionType, Status: v1.ConditionTrue}},
+			}
+		}
+		return j
+	}
+	otherApp := job("other-web-1", batchv1.JobFailed)
+	otherApp.Labels["theketch.io/app-name"] = "other"
+	hook := job("app-pre-deploy-1", batchv1.JobFailed)
+	hook.Labels["theketch.io/deploy-hook"] = "pre-deploy"
+
+	tests := []struct {
+		name          string
+		jobs          []runtime.Object
+		wantJobs      []string
+		wantCondition *ketchv1.AppCondition
+	}{
+		{
+			name:     "no jobs",
+			jobs:     []runtime.Object{otherApp},
+			wantJobs: []string{},
+		},
+		{
+			name:     "running",
+			jobs:     []runtime.Object{job("app-web-1-a", batchv1.JobComplete), job("app-web-1-b", ""), otherApp},
+			wantJobs: []string{"app-web-1-a", "app-web-1-b"},
+			wantCondition: &ketchv1.AppCondition{
+				Type:    ketchv1.JobCompleted,
+				Status:  v1.ConditionUnknown,
+				Message: "job app-web-1-b is running",
+			},
+		},
+		{
+			name:     "failed",
+			jobs:     []runtime.Object{job("app-web-1-a", batchv1.JobFailed), job("app-web-1-b", batchv1.JobComplete)},
+			wantJobs: []string{"app-web-1-a", "app-web-1-b"},
+			wantCondition: &ketchv1.AppCondition{
+				Type:    ketchv1.JobCompleted,
+				Status:  v1.ConditionFalse,
+				Message: "job app-web-1-a failed",
+			},
+		},
+		{
+			name:     "completed",
+			jobs:     []runtime.Object{job("app-web-1-a", batchv1.JobComplete), hook},
+			wantJobs: []string{"app-web-1-a"},
+			wantCondition: &ketchv1.AppCondition{
+				Type:   ketchv1.JobCompleted,
+				Status: v1.ConditionTrue,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AppReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.jobs...),
+				Now:    func() time.Time { return now },
+			}
+			app := &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec:       ketchv1.AppSpec{Type: ketchv1.JobAppType},
+			}
+			err := r.updateJobStatus(context.Background(), app, "ketch-framework")
+			require.Nil(t, err)
+
+			gotJobs := []string{}
+			for _, status := range app.Status.Jobs {
+				gotJobs = append(gotJobs, status.Name)
+			}
+			require.Equal(t, tt.wantJobs, gotJobs)
+			if tt.wantCondition == nil {
+				require.Nil(t, app.Status.Condition(ketchv1.JobCompleted))
+				return
+			}
+			got := app.Status.Condition(ketchv1.JobCompleted)
+			require.NotNil(t, got)
+			require.Equal(t, tt.wantCondition.Status, got.Status)
+			require.Equal(t, tt.wantCondition.Message, got.Message)
+		})
+	}
+}
+
+func TestAppReconciler_runScheduledJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app-worker-1",
+			Namespace: "ketch-framework",
+			Labels:    map[string]string{"theketch.io/app-name": "app"},
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule: "*/5 * * * *",
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"theketch.io/app-name": "app"}},
+				Spec: batchv1.JobSpec{
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers:    []v1.Container{{Name: "app-worker-1", Image: "shipasoftware/job:v1"}},
+							RestartPolicy: v1.RestartPolicyNever,
+						},
+					},
+				},
+			},
+		},
+	}
+	otherApp := cronJob.DeepCopy()
+	otherApp.Name = "other-worker-1"
+	otherApp.Labels = map[string]string{"theketch.io/app-name": "other"}
+
+	tests := []struct {
+		name       string
+		job        *ketchv1.JobSpec
+		lastRun    int
+		wantJobs   []string
+		wantJobRun int
+	}{
+		{
+			name:       "no schedule",
+			job:        &ketchv1.JobSpec{Run: 2},
+			wantJobs:   []string{},
+			wantJobRun: 2,
+		},
+		{
+			name:       "run requested",
+			job:        &ketchv1.JobSpec{Schedule: "*/5 * * * *", Run: 2},
+			lastRun:    1,
+			wantJobs:   []string{"app-worker-1-run-2"},
+			wantJobRun: 2,
+		},
+		{
+			name:       "already run",
+			job:        &ketchv1.JobSpec{Schedule: "*/5 * * * *", Run: 2},
+			lastRun:    2,
+			wantJobs:   []string{},
+			wantJobRun: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AppReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme, cronJob, otherApp),
+			}
+			app := &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "app"},
+				Spec:       ketchv1.AppSpec{Type: ketchv1.JobAppType, Job: tt.job},
+				Status:     ketchv1.AppStatus{JobRun: tt.lastRun},
+			}
+			err := r.runScheduledJob(context.Background(), app, "ketch-framework")
+			require.Nil(t, err)
+			require.Equal(t, tt.wantJobRun, app.Status.JobRun)
+
+			jobs := batchv1.JobList{}
+			require.Nil(t, r.List(context.Background(), &jobs))
+			gotJobs := []string{}
+			for _, job := range jobs.Items {
+				gotJobs = append(gotJobs, job.Name)
+				require.Equal(t, cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers, job.Spec.Template.Spec.Containers)
+				require.Equal(t, "app", job.Labels["theketch.io/app-name"])
+				require.Equal(t, "manual", job.Annotations["cronjob.kubernetes.io/instantiate"])
+				require.Len(t, job.OwnerReferences, 1)
+				require.Equal(t, "app-worker-1", job.OwnerReferences[0].Name)
+			}
+			require.Equal(t, tt.wantJobs, gotJobs)
+
+			// a second reconciliation doesn't run the job again.
+			require.Nil(t, r.runScheduledJob(context.Background(), app, "ketch-framework"))
+			require.Nil(t, r.List(context.Background(), &jobs))
+			require.Len(t, jobs.Items, len(tt.wantJobs))
+		})
+	}
+}
+
+func TestAppReconciler_appsReferencingConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "myframework"},
+		Status:     ketchv1.FrameworkStatus{Namespace: &v1.ObjectReference{Name: "ketch-myframework"}},
+	}
+	app := func(name string, secret string) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: ketchv1.AppSpec{
+				Framework: "myframework",
+				EnvFrom:   []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: secret}}}},
+			},
+		}
+	}
+	r := &AppReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, framework, app("go-app", "credentials"), app("other-app", "other-credentials")),
+	}
+	mapFn := r.appsReferencingConfig(appSecretsField, (*ketchv1.App).SecretNames)
+
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: "ketch-myframework"}}
+	got := mapFn(handler.MapObject{Meta: secret, Object: secret})
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "go-app"}}}, got)
+
+	secret.Namespace = "default"
+	require.Empty(t, mapFn(handler.MapObject{Meta: secret, Object: secret}))
+}
@@ -6,4 +6,16 @@ const (
 	KetchNamespace = "ketch-system"
 	// reconcileTimeout is the default timeout to trigger Operator reconcile
 	reconcileTimeout = 10 * time.Minute
+	// deployHookPollInterval is how often the controller checks whether a deployment is available to run its post-deploy hook.
+	deployHookPollInterval = 10 * time.Second
+
+	// frameworkResourceQuotaName is the name of a ResourceQuota created in a framework's namespace.
+	frameworkResourceQuotaName = "ketch-resource-quota"
+	// frameworkLimitRangeName is the name of a LimitRange created in a framework's namespace.
+	frameworkLimitRangeName = "ketch-limit-range"
+
+	// appConfigMapsField is an index of apps by names of ConfigMaps they get environment variables from.
+	appConfigMapsField = "spec.configMapNames"
+	// appSecretsField is an index of apps by names of Secrets they get environment variables from.
+	appSecretsField = "spec.secretNames"
 )
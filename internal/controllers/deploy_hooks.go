@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+	"github.com/shipa-corp/ketch/internal/chart"
+	"github.com/shipa-corp/ketch/internal/utils"
+)
+
+// deployHook is a kind of hook that runs once per deployment in a kubernetes Job.
+type deployHook string
+
+const (
+	preDeployHook  deployHook = "pre-deploy"
+	postDeployHook deployHook = "post-deploy"
+)
+
+type deployHookStatus int
+
+const (
+	deployHookRunning deployHookStatus = iota
+	deployHookSucceeded
+	deployHookFailed
+)
+
+// deployHookCommands returns commands of the deployment's hook as configured in ketch.yaml.
+func deployHookCommands(deployment ketchv1.AppDeploymentSpec, hook deployHook) []string {
+	if deployment.KetchYaml == nil || deployment.KetchYaml.Hooks == nil {
+		return nil
+	}
+	if hook == preDeployHook {
+		return deployment.KetchYaml.Hooks.Deploy.Before
+	}
+	return deployment.KetchYaml.Hooks.Deploy.After
+}
+
+func hasDeployHooks(app *ketchv1.App) bool {
+	for _, deployment := range app.Spec.Deployments {
+		if len(deployHookCommands(deployment, preDeployHook))+len(deployHookCommands(deployment, postDeployHook)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func deployHookJobName(appName string, version ketchv1.DeploymentVersion, hook deployHook) string {
+	return fmt.Sprintf("%s-%s-%d", appName, hook, version)
+}
+
+// hookProcess returns the process whose environment, volumes, resources and scheduling are used by hooks of the deployment,
+// it's the routable process, usually "web".
+func hookProcess(deployment ketchv1.AppDeploymentSpec) ketchv1.ProcessSpec {
+	procfile, err := chart.ProcfileFromProcesses(deployment.Processes)
+	if err != nil {
+		return ketchv1.ProcessSpec{}
+	}
+	for _, process := range deployment.Processes {
+		if procfile.IsRoutable(process.Name) {
+			return process
+		}
+	}
+	return ketchv1.ProcessSpec{}
+}
+
+// newDeployHookJob returns a Job running the hook's commands with the deployment's image.
+// The Job's pod is built like a unit of the deployment's routable process, it gets the process' environment, volumes, resources and scheduling.
+func newDeployHookJob(app *ketchv1.App, framework *ketchv1.Framework, deployment ketchv1.AppDeploymentSpec, hook deployHook) *batchv1.Job {
+	labels := map[string]string{
+		utils.KetchAppNameLabel:           app.Name,
+		utils.KetchDeploymentVersionLabel: deployment.Version.String(),
+		utils.KetchDeployHookLabel:        string(hook),
+	}
+	process := hookProcess(deployment)
+	var env []v1.EnvVar
+	for _, e := range append(append([]ketchv1.Env{}, process.Env...), app.Spec.Env...) {
+		envVar := v1.EnvVar{Name: e.Name, Value: e.Value}
+		if e.IsSecret() {
+			envVar.ValueFrom = &v1.EnvVarSource{SecretKeyRef: e.ValueFrom.SecretKeyRef}
+		}
+		env = append(env, envVar)
+	}
+	var envFrom []v1.EnvFromSource
+	envFrom = append(envFrom, process.EnvFrom...)
+	envFrom = append(envFrom, app.Spec.EnvFrom...)
+	var volumes []v1.Volume
+	for _, volume := range app.PodVolumes() {
+		for _, mount := range process.VolumeMounts {
+			if mount.Name == volume.Name {
+				volumes = append(volumes, volume)
+				break
+			}
+		}
+	}
+	backoffLimit := int32(0)
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      deployHookJobName(app.Name, deployment.Version, hook),
+			Namespace: framework.Status.Namespace.Name,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			// hooks like database migrations are not safe to retry.
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					// pods of hooks are not units of the app, the deployment version is left out not to count them as units.
+					Labels: map[string]string{
+						utils.KetchAppNameLabel:     app.Name,
+						utils.KetchDeployHookLabel:  string(hook),
+						utils.KetchIsolatedRunLabel: "true",
+					},
+				},
+				Spec: v1.PodSpec{
+					RestartPolicy: v1.RestartPolicyNever,
+					Containers: []v1.Container{
+						{
+							Name:            fmt.Sprintf("%s-%s", app.Name, hook),
+							Image:           deployment.Image,
+							Command:         []string{"sh", "-c", strings.Join(deployHookCommands(deployment, hook), " && ")},
+							Env:             env,
+							EnvFrom:         envFrom,
+							VolumeMounts:    process.VolumeMounts,
+							SecurityContext: process.SecurityContext,
+						},
+					},
+					Volumes: volumes,
+				},
+			},
+		},
+	}
+	if process.Resources != nil {
+		job.Spec.Template.Spec.Containers[0].Resources = *process.Resources
+	}
+	// topology spread constraints are left out, they describe how units of the process are spread.
+	if scheduling := process.Scheduling.WithDefaults(framework.Spec.DefaultScheduling); scheduling != nil {
+		job.Spec.Template.Spec.NodeSelector = scheduling.NodeSelector
+		job.Spec.Template.Spec.Affinity = scheduling.Affinity
+		job.Spec.Template.Spec.Tolerations = scheduling.Tolerations
+		job.Spec.Template.Spec.PriorityClassName = scheduling.PriorityClassName
+	}
+	if len(app.Spec.DockerRegistry.SecretName) > 0 {
+		job.Spec.Template.Spec.ImagePullSecrets = []v1.LocalObjectReference{{Name: app.Spec.DockerRegistry.SecretName}}
+	}
+	return job
+}
+
+// runDeployHook starts a Job running the deployment's hook unless the Job exists and returns the Job's status.
+// A deployment without the hook is reported as succeeded.
+func (r *AppReconciler) runDeployHook(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework, deployment ketchv1.AppDeploymentSpec, hook deployHook) (deployHookStatus, error) {
+	if len(deployHookCommands(deployment, hook)) == 0 {
+		return deployHookSucceeded, nil
+	}
+	job := batchv1.Job{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: framework.Status.Namespace.Name, Name: deployHookJobName(app.Name, deployment.Version, hook)}, &job)
+	if apierrors.IsNotFound(err) {
+		newJob := newDeployHookJob(app, framework, deployment, hook)
+		// the Job is removed along with the app.
+		if err := ctrl.SetControllerReference(app, newJob, r.Scheme); err != nil {
+			return deployHookRunning, err
+		}
+		return deployHookRunning, r.Create(ctx, newJob)
+	}
+	if err != nil {
+		return deployHookRunning, err
+	}
+	switch {
+	case jobHasCondition(job, batchv1.JobFailed):
+		return deployHookFailed, nil
+	case jobHasCondition(job, batchv1.JobComplete):
+		return deployHookSucceeded, nil
+	}
+	return deployHookRunning, nil
+}
+
+// deploymentAvailable returns true once all units of all processes of the deployment are updated and available.
+func (r *AppReconciler) deploymentAvailable(ctx context.Context, app *ketchv1.App, version ketchv1.DeploymentVersion, namespace string) (bool, error) {
+	deployments := appsv1.DeploymentList{}
+	err := r.List(ctx, &deployments, client.InNamespace(namespace), client.MatchingLabels{
+		utils.KetchAppNameLabel:           app.Name,
+		utils.KetchDeploymentVersionLabel: version.String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(deployments.Items) == 0 {
+		return false, nil
+	}
+	for _, deployment := range deployments.Items {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		if deployment.Status.ObservedGeneration < deployment.Generation ||
+			deployment.Status.UpdatedReplicas < replicas ||
+			deployment.Status.AvailableReplicas < replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// removeDeployHookJobs removes Jobs of hooks of deployments the app doesn't have anymore.
+func (r *AppReconciler) removeDeployHookJobs(ctx context.Context, app *ketchv1.App, namespace string) error {
+	jobs := batchv1.JobList{}
+	if err := r.List(ctx, &jobs, client.InNamespace(namespace), client.MatchingLabels{utils.KetchAppNameLabel: app.Name}, client.HasLabels{utils.KetchDeployHookLabel}); err != nil {
+		return err
+	}
+	versions := make(map[string]bool, len(app.Spec.Deployments))
+	for _, deployment := range app.Spec.Deployments {
+		versions[deployment.Version.String()] = true
+	}
+	for i, job := range jobs.Items {
+		if versions[job.Labels[utils.KetchDeploymentVersionLabel]] {
+			continue
+		}
+		if err := r.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPreDeployHooks runs pre-deploy hooks of the app's deployments.
+// It returns a result to report unless all hooks have succeeded, the app's chart must not be updated in that case.
+func (r *AppReconciler) runPreDeployHooks(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework) *reconcileResult {
+	for _, deployment := range app.Spec.Deployments {
+		status, err := r.runDeployHook(ctx, app, framework, deployment, preDeployHook)
+		if err != nil {
+			return &reconcileResult{
+				status:  v1.ConditionFalse,
+				message: fmt.Sprintf("failed to run pre-deploy hook: %v", err),
+			}
+		}
+		switch status {
+		case deployHookFailed:
+			message := fmt.Sprintf("pre-deploy hook of version %d failed, see logs of job %s", deployment.Version, deployHookJobName(app.Name, deployment.Version, preDeployHook))
+			app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionFalse, message, metav1.NewTime(r.Now()))
+			return &reconcileResult{
+				status:  v1.ConditionFalse,
+				message: message,
+			}
+		case deployHookRunning:
+			message := fmt.Sprintf("pre-deploy hook of version %d is running", deployment.Version)
+			app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionUnknown, message, metav1.NewTime(r.Now()))
+			return &reconcileResult{
+				status:  v1.ConditionUnknown,
+				message: message,
+			}
+		}
+	}
+	return nil
+}
+
+// runPostDeployHooks runs post-deploy hooks of the app's deployments once the deployments are available
+// and reports the hooks with the DeployHooksCompleted condition.
+// It returns true if a hook is pending and the app has to be reconciled again.
+func (r *AppReconciler) runPostDeployHooks(ctx context.Context, app *ketchv1.App, framework *ketchv1.Framework) (bool, error) {
+	now := metav1.NewTime(r.Now())
+	if !hasDeployHooks(app) {
+		if app.Status.Condition(ketchv1.DeployHooksCompleted) != nil {
+			app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionTrue, "", now)
+		}
+		return false, nil
+	}
+	var pending, failed []string
+	if !app.IsJob() {
+		for _, deployment := range app.Spec.Deployments {
+			if len(deployHookCommands(deployment, postDeployHook)) == 0 {
+				continue
+			}
+			available, err := r.deploymentAvailable(ctx, app, deployment.Version, framework.Status.Namespace.Name)
+			if err != nil {
+				return false, err
+			}
+			if !available {
+				pending = append(pending, deployment.Version.String())
+				continue
+			}
+			status, err := r.runDeployHook(ctx, app, framework, deployment, postDeployHook)
+			if err != nil {
+				return false, err
+			}
+			switch status {
+			case deployHookFailed:
+				failed = append(failed, deployHookJobName(app.Name, deployment.Version, postDeployHook))
+			case deployHookRunning:
+				pending = append(pending, deployment.Version.String())
+			}
+		}
+	}
+	switch {
+	case len(failed) > 0:
+		app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionFalse, fmt.Sprintf("post-deploy hook failed, see logs of job %s", strings.Join(failed, ", ")), now)
+	case len(pending) > 0:
+		app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionUnknown, fmt.Sprintf("post-deploy hook of version %s is pending", strings.Join(pending, ", ")), now)
+	default:
+		app.SetCondition(ketchv1.DeployHooksCompleted, v1.ConditionTrue, "", now)
+	}
+	return len(pending) > 0, nil
+}
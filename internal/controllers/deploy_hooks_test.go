@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+func hookApp(versions ...ketchv1.DeploymentVersion) *ketchv1.App {
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", UID: "app-uid"},
+		TypeMeta:   metav1.TypeMeta{APIVersion: "theketch.io/v1beta1", Kind: "App"},
+		Spec: ketchv1.AppSpec{
+			Env: []ketchv1.Env{{Name: "DATABASE_URL", Value: "postgres://db"}},
+		},
+	}
+	for _, version := range versions {
+		app.Spec.Deployments = append(app.Spec.Deployments, ketchv1.AppDeploymentSpec{
+			Image:   "app:v" + version.String(),
+			Version: version,
+			KetchYaml: &ketchv1.KetchYamlData{
+				Hooks: &ketchv1.KetchYamlHooks{
+					Deploy: ketchv1.KetchYamlDeployHooks{
+						Before: []string{"rake db:migrate", "rake db:seed"},
+						After:  []string{"rake cache:warm"},
+					},
+				},
+			},
+		})
+	}
+	return app
+}
+
+func hookFramework() *ketchv1.Framework {
+	return &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Status:     ketchv1.FrameworkStatus{Namespace: &v1.ObjectReference{Name: "ketch-framework"}},
+	}
+}
+
+func hookJob(name string, conditionType batchv1.JobConditionType) *batchv1.Job {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "ketch-framework",
+			Labels: map[string]string{
+				"theketch.io/app-name":               "app",
+				"theketch.io/app-deployment-version": "1",
+				"theketch.io/deploy-hook":            "pre-deploy",
+			},
+		},
+	}
+	if len(conditionType) > 0 {
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: v1.ConditionTrue}}
+	}
+	return job
+}
+
+func TestAppReconciler_runPreDeployHooks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		app           *ketchv1.App
+		jobs          []runtime.Object
+		wantResult    *reconcileResult
+		wantCondition v1.ConditionStatus
+	}{
+		{
+			name: "no hooks",
+			app:  &ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: ketchv1.AppSpec{Deployments: []ketchv1.AppDeploymentSpec{{Version: 1}}}},
+		},
+		{
+			name: "job is created",
+			app:  hookApp(1),
+			wantResult: &reconcileResult{
+				status:  v1.ConditionUnknown,
+				message: "pre-deploy hook of version 1 is running",
+			},
+			wantCondition: v1.ConditionUnknown,
+		},
+		{
+			name: "job is running",
+			app:  hookApp(1),
+			jobs: []runtime.Object{hookJob("app-pre-deploy-1", "")},
+			wantResult: &reconcileResult{
+				status:  v1.ConditionUnknown,
+				message: "pre-deploy hook of version 1 is running",
+			},
+			wantCondition: v1.ConditionUnknown,
+		},
+		{
+			name: "job failed",
+			app:  hookApp(1),
+			jobs: []runtime.Object{hookJob("app-pre-deploy-1", batchv1.JobFailed)},
+			wantResult: &reconcileResult{
+				status:  v1.ConditionFalse,
+				message: "pre-deploy hook of version 1 failed, see logs of job app-pre-deploy-1",
+			},
+			wantCondition: v1.ConditionFalse,
+		},
+		{
+			name: "job completed",
+			app:  hookApp(1),
+			jobs: []runtime.Object{hookJob("app-pre-deploy-1", batchv1.JobComplete)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AppReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.jobs...),
+				Scheme: scheme,
+				Now:    func() time.Time { return now },
+			}
+			got := r.runPreDeployHooks(context.Background(), tt.app, hookFramework())
+			require.Equal(t, tt.wantResult, got)
+			if len(tt.wantCondition) == 0 {
+				require.Nil(t, tt.app.Status.Condition(ketchv1.DeployHooksCompleted))
+			} else {
+				require.Equal(t, tt.wantCondition, tt.app.Status.Condition(ketchv1.DeployHooksCompleted).Status)
+			}
+			if tt.app.Spec.Deployments[0].KetchYaml == nil {
+				return
+			}
+			job := batchv1.Job{}
+			err := r.Get(context.Background(), types.NamespacedName{Namespace: "ketch-framework", Name: "app-pre-deploy-1"}, &job)
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestNewDeployHookJob(t *testing.T) {
+	app := hookApp(2)
+	app.Spec.DockerRegistry.SecretName = "registry"
+	app.Spec.EnvFrom = []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}}}
+	app.Spec.Volumes = []ketchv1.Volume{
+		{Name: "credentials", Secret: &v1.SecretVolumeSource{SecretName: "db-credentials"}},
+		{Name: "cache", EmptyDir: &v1.EmptyDirVolumeSource{}},
+	}
+	resources := &v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")}}
+	app.Spec.Deployments[0].Processes = []ketchv1.ProcessSpec{
+		{Name: "worker", Env: []ketchv1.Env{{Name: "QUEUE", Value: "default"}}},
+		{
+			Name:         "web",
+			Env:          []ketchv1.Env{{Name: "PORT", Value: "8080"}},
+			EnvFrom:      []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-secrets"}}}},
+			VolumeMounts: []v1.VolumeMount{{Name: "credentials", MountPath: "/etc/credentials"}},
+			Resources:    resources,
+			Scheduling:   &ketchv1.SchedulingSpec{NodeSelector: map[string]string{"pool": "web"}},
+		},
+	}
+	framework := hookFramework()
+	framework.Spec.DefaultScheduling = &ketchv1.SchedulingSpec{Tolerations: []v1.Toleration{{Key: "apps", Operator: v1.TolerationOpExists}}}
+	job := newDeployHookJob(app, framework, app.Spec.Deployments[0], preDeployHook)
+
+	require.Equal(t, "app-pre-deploy-2", job.Name)
+	require.Equal(t, "ketch-framework", job.Namespace)
+	require.Equal(t, map[string]string{
+		"theketch.io/app-name":               "app",
+		"theketch.io/app-deployment-version": "2",
+		"theketch.io/deploy-hook":            "pre-deploy",
+	}, job.Labels)
+	require.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	require.NotContains(t, job.Spec.Template.Labels, "theketch.io/app-deployment-version")
+	podSpec := job.Spec.Template.Spec
+	require.Equal(t, v1.RestartPolicyNever, podSpec.RestartPolicy)
+	require.Equal(t, []v1.LocalObjectReference{{Name: "registry"}}, podSpec.ImagePullSecrets)
+	require.Equal(t, []v1.Volume{{Name: "credentials", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "db-credentials"}}}}, podSpec.Volumes)
+	require.Equal(t, map[string]string{"pool": "web"}, podSpec.NodeSelector)
+	require.Equal(t, []v1.Toleration{{Key: "apps", Operator: v1.TolerationOpExists}}, podSpec.Tolerations)
+	container := podSpec.Containers[0]
+	require.Equal(t, "app:v2", container.Image)
+	require.Equal(t, []string{"sh", "-c", "rake db:migrate && rake db:seed"}, container.Command)
+	require.Equal(t, []v1.EnvVar{{Name: "PORT", Value: "8080"}, {Name: "DATABASE_URL", Value: "postgres://db"}}, container.Env)
+	require.Equal(t, []v1.EnvFromSource{
+		{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-secrets"}}},
+		{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "settings"}}},
+	}, container.EnvFrom)
+	require.Equal(t, []v1.VolumeMount{{Name: "credentials", MountPath: "/etc/credentials"}}, container.VolumeMounts)
+	require.Equal(t, *resources, container.Resources)
+}
+
+func TestAppReconciler_runPostDeployHooks(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	now := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	deployment := func(available int32) *appsv1.Deployment {
+		replicas := int32(2)
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app-web-1",
+				Namespace: "ketch-framework",
+				Labels: map[string]string{
+					"theketch.io/app-name":               "app",
+					"theketch.io/app-deployment-version": "1",
+				},
+			},
+			Spec:   appsv1.DeploymentSpec{Replicas: &replicas},
+			Status: appsv1.DeploymentStatus{UpdatedReplicas: 2, AvailableReplicas: available},
+		}
+	}
+	postHookJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		job := hookJob("app-post-deploy-1", conditionType)
+		job.Labels["theketch.io/deploy-hook"] = "post-deploy"
+		return job
+	}
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		wantPending   bool
+		wantJob       bool
+		wantCondition v1.ConditionStatus
+	}{
+		{
+			name:          "deployment is not available",
+			objects:       []runtime.Object{deployment(1)},
+			wantPending:   true,
+			wantCondition: v1.ConditionUnknown,
+		},
+		{
+			name:          "job is created",
+			objects:       []runtime.Object{deployment(2)},
+			wantPending:   true,
+			wantJob:       true,
+			wantCondition: v1.ConditionUnknown,
+		},
+		{
+			name:          "job failed",
+			objects:       []runtime.Object{deployment(2), postHookJob(batchv1.JobFailed)},
+			wantJob:       true,
+			wantCondition: v1.ConditionFalse,
+		},
+		{
+			name:          "job completed",
+			objects:       []runtime.Object{deployment(2), postHookJob(batchv1.JobComplete)},
+			wantJob:       true,
+			wantCondition: v1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &AppReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.objects...),
+				Scheme: scheme,
+				Now:    func() time.Time { return now },
+			}
+			app := hookApp(1)
+			pending, err := r.runPostDeployHooks(context.Background(), app, hookFramework())
+			require.Nil(t, err)
+			require.Equal(t, tt.wantPending, pending)
+			require.Equal(t, tt.wantCondition, app.Status.Condition(ketchv1.DeployHooksCompleted).Status)
+
+			job := batchv1.Job{}
+			err = r.Get(context.Background(), types.NamespacedName{Namespace: "ketch-framework", Name: "app-post-deploy-1"}, &job)
+			require.Equal(t, tt.wantJob, err == nil)
+		})
+	}
+}
+
+func TestAppReconciler_removeDeployHookJobs(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	current := hookJob("app-pre-deploy-2", batchv1.JobComplete)
+	current.Labels["theketch.io/app-deployment-version"] = "2"
+	r := &AppReconciler{
+		Client: fake.NewFakeClientWithScheme(scheme, hookJob("app-pre-deploy-1", batchv1.JobComplete), current),
+	}
+	err := r.removeDeployHookJobs(context.Background(), hookApp(2), "ketch-framework")
+	require.Nil(t, err)
+
+	jobs := batchv1.JobList{}
+	require.Nil(t, r.List(context.Background(), &jobs))
+	require.Len(t, jobs.Items, 1)
+	require.Equal(t, "app-pre-deploy-2", jobs.Items[0].Name)
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 )
@@ -43,6 +44,8 @@ type FrameworkReconciler struct {
 
 // +kubebuilder:rbac:groups=theketch.io,resources=frameworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=theketch.io,resources=frameworks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch;create;update;patch;delete
 
 func (r *FrameworkReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -152,15 +155,92 @@ func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.
 			}
 		}
 	}
+	if err := r.reconcileLimitRange(ctx, framework); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to update limit range: %v", err),
+			Apps:      framework.Status.Apps,
+			Namespace: framework.Status.Namespace,
+		}
+	}
+	if err := framework.Spec.ValidateResourceQuota(); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to update resource quota: %v", err),
+			Apps:      framework.Status.Apps,
+			Namespace: framework.Status.Namespace,
+		}
+	}
+	quotaStatus, err := r.reconcileResourceQuota(ctx, framework)
+	if err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to update resource quota: %v", err),
+			Apps:      framework.Status.Apps,
+			Namespace: framework.Status.Namespace,
+		}
+	}
 	return ketchv1.FrameworkStatus{
-		Namespace: ref,
-		Phase:     ketchv1.FrameworkCreated,
-		Apps:      framework.Status.Apps,
+		Namespace:     ref,
+		Phase:         ketchv1.FrameworkCreated,
+		Apps:          framework.Status.Apps,
+		ResourceQuota: quotaStatus,
+	}
+}
+
+// reconcileResourceQuota creates or updates a ResourceQuota in the framework's namespace
+// and returns its status with the current usage.
+// The ResourceQuota is deleted if the framework has no quota.
+func (r *FrameworkReconciler) reconcileResourceQuota(ctx context.Context, framework *ketchv1.Framework) (*v1.ResourceQuotaStatus, error) {
+	quota := v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      frameworkResourceQuotaName,
+			Namespace: framework.Spec.NamespaceName,
+		},
+	}
+	if framework.Spec.ResourceQuota == nil {
+		return nil, client.IgnoreNotFound(r.Delete(ctx, &quota))
 	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &quota, func() error {
+		quota.Spec.Hard = framework.Spec.ResourceQuota.Hard()
+		return controllerutil.SetControllerReference(framework, &quota, r.Scheme)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &quota.Status, nil
+}
+
+// reconcileLimitRange creates or updates a LimitRange in the framework's namespace
+// to apply the framework's default resources to every container.
+// The LimitRange is deleted if the framework has no default resources.
+func (r *FrameworkReconciler) reconcileLimitRange(ctx context.Context, framework *ketchv1.Framework) error {
+	limitRange := v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      frameworkLimitRangeName,
+			Namespace: framework.Spec.NamespaceName,
+		},
+	}
+	if framework.Spec.DefaultResources == nil {
+		return client.IgnoreNotFound(r.Delete(ctx, &limitRange))
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &limitRange, func() error {
+		limitRange.Spec.Limits = []v1.LimitRangeItem{
+			{
+				Type:           v1.LimitTypeContainer,
+				Default:        framework.Spec.DefaultResources.Limits,
+				DefaultRequest: framework.Spec.DefaultResources.Requests,
+			},
+		}
+		return controllerutil.SetControllerReference(framework, &limitRange, r.Scheme)
+	})
+	return err
 }
 
 func (r *FrameworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ketchv1.Framework{}).
+		Owns(&v1.ResourceQuota{}).
+		Owns(&v1.LimitRange{}).
 		Complete(r)
 }
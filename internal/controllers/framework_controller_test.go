@@ -6,10 +6,15 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/utils/conversions"
@@ -130,3 +135,121 @@ func TestFrameworkReconciler_Reconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestFrameworkReconciler_reconcileResourceQuota(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme(scheme))
+
+	framework := func(quota *ketchv1.ResourceQuotaSpec, defaults *v1.ResourceRequirements) *ketchv1.Framework {
+		return &ketchv1.Framework{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "framework",
+				UID:  "framework-uid",
+			},
+			Spec: ketchv1.FrameworkSpec{
+				NamespaceName:    "ketch-framework",
+				ResourceQuota:    quota,
+				DefaultResources: defaults,
+			},
+		}
+	}
+	existingQuota := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: frameworkResourceQuotaName, Namespace: "ketch-framework"},
+		Spec: v1.ResourceQuotaSpec{
+			Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("5")},
+		},
+		Status: v1.ResourceQuotaStatus{
+			Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("5")},
+			Used: v1.ResourceList{v1.ResourcePods: resource.MustParse("2")},
+		},
+	}
+	existingLimitRange := &v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{Name: frameworkLimitRangeName, Namespace: "ketch-framework"},
+	}
+
+	tests := []struct {
+		name             string
+		objects          []runtime.Object
+		framework        *ketchv1.Framework
+		wantHard         v1.ResourceList
+		wantStatus       *v1.ResourceQuotaStatus
+		wantLimitRange   *v1.LimitRangeItem
+		wantNoQuota      bool
+		wantNoLimitRange bool
+	}{
+		{
+			name: "create quota and limit range",
+			framework: framework(
+				&ketchv1.ResourceQuotaSpec{CPU: quantityRef("4"), Memory: quantityRef("8Gi"), Pods: conversions.IntPtr(20)},
+				&v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+				}),
+			wantHard: v1.ResourceList{
+				v1.ResourceRequestsCPU:    resource.MustParse("4"),
+				v1.ResourceRequestsMemory: resource.MustParse("8Gi"),
+				v1.ResourcePods:           resource.MustParse("20"),
+			},
+			wantStatus: &v1.ResourceQuotaStatus{},
+			wantLimitRange: &v1.LimitRangeItem{
+				Type:           v1.LimitTypeContainer,
+				DefaultRequest: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m")},
+				Default:        v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+		{
+			name:             "update quota and report usage",
+			objects:          []runtime.Object{existingQuota.DeepCopy()},
+			framework:        framework(&ketchv1.ResourceQuotaSpec{Pods: conversions.IntPtr(10)}, nil),
+			wantHard:         v1.ResourceList{v1.ResourcePods: resource.MustParse("10")},
+			wantStatus:       &existingQuota.Status,
+			wantNoLimitRange: true,
+		},
+		{
+			name:             "delete quota and limit range",
+			objects:          []runtime.Object{existingQuota.DeepCopy(), existingLimitRange.DeepCopy()},
+			framework:        framework(nil, nil),
+			wantNoQuota:      true,
+			wantNoLimitRange: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &FrameworkReconciler{
+				Client: fake.NewFakeClientWithScheme(scheme, tt.objects...),
+				Scheme: scheme,
+			}
+			err := r.reconcileLimitRange(context.Background(), tt.framework)
+			require.Nil(t, err)
+			status, err := r.reconcileResourceQuota(context.Background(), tt.framework)
+			require.Nil(t, err)
+
+			quota := v1.ResourceQuota{}
+			err = r.Get(context.Background(), types.NamespacedName{Name: frameworkResourceQuotaName, Namespace: "ketch-framework"}, &quota)
+			if tt.wantNoQuota {
+				require.True(t, errors.IsNotFound(err))
+				require.Nil(t, status)
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, tt.wantHard, quota.Spec.Hard)
+				require.Equal(t, tt.wantStatus, status)
+				require.Equal(t, "framework", quota.OwnerReferences[0].Name)
+			}
+
+			limitRange := v1.LimitRange{}
+			err = r.Get(context.Background(), types.NamespacedName{Name: frameworkLimitRangeName, Namespace: "ketch-framework"}, &limitRange)
+			if tt.wantNoLimitRange {
+				require.True(t, errors.IsNotFound(err))
+			} else {
+				require.Nil(t, err)
+				require.Equal(t, []v1.LimitRangeItem{*tt.wantLimitRange}, limitRange.Spec.Limits)
+			}
+		})
+	}
+}
+
+func quantityRef(value string) *resource.Quantity {
+	q := resource.MustParse(value)
+	return &q
+}
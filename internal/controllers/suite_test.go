@@ -71,6 +71,7 @@ func setup(reader templates.Reader, helm Helm, objects []runtime.Object) (*testi
 			return helm, nil
 		},
 		Recorder: k8sManager.GetEventRecorderFor("App"),
+		Now:      time.Now,
 	}).SetupWithManager(k8sManager)
 	if err != nil {
 		return nil, err
@@ -78,6 +79,7 @@ func setup(reader templates.Reader, helm Helm, objects []runtime.Object) (*testi
 	err = (&FrameworkReconciler{
 		Client: k8sManager.GetClient(),
 		Log:    ctrl.Log.WithName("controllers").WithName("Framework"),
+		Scheme: scheme.Scheme,
 	}).SetupWithManager(k8sManager)
 	if err != nil {
 		return nil, err
@@ -20,6 +20,7 @@ import (
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/build"
+	"github.com/shipa-corp/ketch/internal/canary"
 	"github.com/shipa-corp/ketch/internal/chart"
 	"github.com/shipa-corp/ketch/internal/errors"
 )
@@ -168,7 +169,67 @@ func getUpdatedApp(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.
 
 		envs, err := cs.getEnvironments()
 		if err := assign(err, func() error {
-			app.Spec.Env = envs
+			app.Spec.Env = withSecretEnvs(app.Spec.Env, envs)
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		envFrom, err := cs.getEnvFrom()
+		if err := assign(err, func() error {
+			app.Spec.EnvFrom = envFrom
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		appType, err := cs.getAppType()
+		if err := assign(err, func() error {
+			if len(app.Spec.Deployments) > 0 && (appType == ketchv1.JobAppType) != app.IsJob() {
+				return fmt.Errorf("can't change type once app has been deployed")
+			}
+			app.Spec.Type = appType
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		schedule, err := cs.getSchedule()
+		if err := assign(err, func() error {
+			if app.Spec.Job == nil {
+				app.Spec.Job = &ketchv1.JobSpec{}
+			}
+			app.Spec.Job.Schedule = schedule
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		strategy, err := cs.getStrategy()
+		if err := assign(err, func() error {
+			app.Spec.Strategy.Type = strategy
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		keepPrevious, err := cs.getKeepPrevious()
+		if err := assign(err, func() error {
+			app.Spec.Strategy.BlueGreen.KeepPrevious = keepPrevious
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		previewCname, err := cs.getPreviewCname()
+		if err := assign(err, func() error {
+			app.Spec.Strategy.BlueGreen.PreviewCname = previewCname
 			changed = true
 			return nil
 		}); err != nil {
@@ -189,6 +250,35 @@ func getUpdatedApp(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.
 	return app, err
 }
 
+// withSecretEnvs returns the new environment variables extended with the secret-backed variables of the current ones.
+// Secret-backed variables are managed with "ketch env set --secret" and are kept unless a new variable with the same name
+// has a value. A masked value, as written by "ketch app export", keeps the secret-backed variable.
+func withSecretEnvs(current []ketchv1.Env, envs []ketchv1.Env) []ketchv1.Env {
+	secrets := make(map[string]ketchv1.Env)
+	for _, env := range current {
+		if env.IsSecret() {
+			secrets[env.Name] = env
+		}
+	}
+	result := make([]ketchv1.Env, 0, len(envs)+len(secrets))
+	for _, env := range envs {
+		if secret, ok := secrets[env.Name]; ok {
+			delete(secrets, env.Name)
+			if env.Value == ketchv1.MaskedEnvValue {
+				result = append(result, secret)
+				continue
+			}
+		}
+		result = append(result, env)
+	}
+	for _, env := range current {
+		if _, ok := secrets[env.Name]; ok {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
 func buildFromSource(ctx context.Context, svc *Services, app *ketchv1.App, appName, image, sourcePath string) error {
 	return svc.Builder(
 		ctx,
@@ -213,6 +303,17 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 		return errors.Wrap(err, "failed to get framework %q", app.Spec.Framework)
 	}
 
+	if analysis, err := params.getCanaryAnalysis(); err == nil {
+		if err := canary.ValidateQueries(*analysis, framework.Spec.IngressController.IngressType); err != nil {
+			return fmt.Errorf("canary deployment failed: %w", err)
+		}
+	}
+	if matches, err := params.getRouteMatches(); err == nil {
+		if err := ketchv1.ValidateRouteMatches(matches, framework.Spec.IngressController.IngressType); err != nil {
+			return fmt.Errorf("canary deployment failed: %w", err)
+		}
+	}
+
 	image, _ := params.getImage()
 
 	fromSource := params.sourcePath != nil
@@ -253,6 +354,10 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 	updateRequest.steps = steps
 	stepWeight, _ := params.getStepWeight()
 	updateRequest.stepWeight = stepWeight
+	analysis, _ := params.getCanaryAnalysis()
+	updateRequest.analysis = analysis
+	routeMatches, _ := params.getRouteMatches()
+	updateRequest.routeMatches = routeMatches
 	updateRequest.procFile = procfile
 	updateRequest.fromSource = fromSource
 	updateRequest.ketchYaml = ketchYaml
@@ -310,6 +415,8 @@ type updateAppCRDRequest struct {
 	image             string
 	steps             int
 	stepWeight        uint8
+	analysis          *ketchv1.CanaryAnalysis
+	routeMatches      []ketchv1.RouteMatch
 	procFile          *chart.Procfile
 	fromSource        bool
 	ketchYaml         *ketchv1.KetchYamlData
@@ -331,8 +438,8 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 		}
 		updated.Spec.Version = args.appVersion
 
-		if len(updated.Spec.Deployments) > 1 && !updated.Spec.Canary.Active {
-			return errors.New("cannot have more than one deployment per app, unless canary")
+		if len(updated.Spec.Deployments) > 1 && !updated.Spec.Canary.Active && !updated.Spec.Strategy.BlueGreen.Active {
+			return errors.New("cannot have more than one deployment per app, unless canary or blue-green")
 		}
 
 		// allow user to update units on canary deployments
@@ -356,6 +463,9 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 			}
 		}
 
+		// a blue-green deployment starts a new deployment next to the current one at the same scale
+		blueGreen := updated.Spec.Strategy.Type == ketchv1.BlueGreenStrategy && len(updated.Spec.Deployments) == 1 && args.steps <= 1
+
 		processes := make([]ketchv1.ProcessSpec, 0, len(args.procFile.Processes))
 		for _, processName := range args.procFile.SortedNames() {
 			cmd := args.procFile.Processes[processName]
@@ -364,7 +474,7 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 				Cmd:  cmd,
 			}
 
-			if usePreviousDeploymentSpecs {
+			if usePreviousDeploymentSpecs || blueGreen {
 				for _, previousProcess := range updated.Spec.Deployments[0].Processes {
 					// if the process names for the new and previous deployments match update units to
 					// reflect the previous deployment's value
@@ -374,6 +484,48 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 				}
 			}
 
+			// autoscaling, resources, envFrom, volume mounts, the termination grace period, extra containers, scheduling and the disruption budget are settings of a process rather than of an image,
+			// so they are kept by every new deployment
+			if len(updated.Spec.Deployments) > 0 {
+				for _, previousProcess := range updated.Spec.Deployments[0].Processes {
+					if previousProcess.Name == processName {
+						ps.Autoscaling = previousProcess.Autoscaling.DeepCopy()
+						ps.Resources = previousProcess.Resources.DeepCopy()
+						ps.EnvFrom = previousProcess.EnvFrom
+						ps.VolumeMounts = previousProcess.VolumeMounts
+						ps.TerminationGracePeriodSeconds = previousProcess.TerminationGracePeriodSeconds
+						ps.Sidecars = previousProcess.Sidecars
+						ps.InitContainers = previousProcess.InitContainers
+						ps.Scheduling = previousProcess.Scheduling.DeepCopy()
+						ps.DisruptionBudget = previousProcess.DisruptionBudget.DeepCopy()
+					}
+				}
+			}
+
+			// resources, envFrom, the termination grace period, extra containers, scheduling and the disruption budget specified in application.yaml take precedence over the previous deployment's ones,
+			// application.yaml is the only source of extra containers, scheduling and the disruption budget of its processes.
+			if args.processes != nil {
+				for _, process := range *args.processes {
+					if process.Name != processName {
+						continue
+					}
+					if process.Resources != nil {
+						ps.Resources = process.Resources.DeepCopy()
+					}
+					if process.EnvFrom != nil {
+						ps.EnvFrom = process.EnvFrom
+					}
+					if process.TerminationGracePeriodSeconds != nil {
+						ps.TerminationGracePeriodSeconds = process.TerminationGracePeriodSeconds
+					}
+					// extra containers removed from application.yaml are removed from the process.
+					ps.Sidecars = process.Sidecars
+					ps.InitContainers = process.InitContainers
+					ps.Scheduling = process.Scheduling.DeepCopy()
+					ps.DisruptionBudget = process.DisruptionBudget.DeepCopy()
+				}
+			}
+
 			processes = append(processes, ps)
 		}
 
@@ -400,7 +552,7 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 		}
 
 		// update deployment and version only for canary deployment or a new deployment
-		if !usePreviousDeploymentSpecs || args.steps > 1 {
+		if !usePreviousDeploymentSpecs || args.steps > 1 || blueGreen {
 			deploymentSpec.Version += 1
 			updated.Spec.DeploymentsCount += 1
 		}
@@ -416,16 +568,28 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 				CurrentStep:       1,
 				Active:            true,
 				Started:           &started,
+				Analysis:          args.analysis,
 			}
 
 			// set initial weight for canary deployment to zero.
 			// App controller will update the weight once all pods for canary will be on running state.
 			deploymentSpec.RoutingSettings.Weight = 0
+			deploymentSpec.RoutingSettings.Match = args.routeMatches
 
 			// For a canary deployment, canary should be enabled by adding another deployment to the deployment list.
 			updated.Spec.Deployments = append(updated.Spec.Deployments, deploymentSpec)
+		} else if blueGreen {
+			// the new deployment doesn't receive traffic until the app is switched,
+			// it is accessible with the preview cname.
+			deploymentSpec.RoutingSettings.Weight = 0
+			updated.Spec.Deployments = append(updated.Spec.Deployments, deploymentSpec)
+			updated.Spec.Strategy.BlueGreen.Active = true
+			updated.Spec.Strategy.BlueGreen.SwitchedAt = nil
 		} else {
+			// a replace deployment also replaces both deployments of an active blue-green deployment
 			updated.Spec.Deployments = []ketchv1.AppDeploymentSpec{deploymentSpec}
+			updated.Spec.Strategy.BlueGreen.Active = false
+			updated.Spec.Strategy.BlueGreen.SwitchedAt = nil
 		}
 
 		if args.units > 0 {
@@ -5,8 +5,12 @@ import (
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 	"github.com/shipa-corp/ketch/internal/chart"
+	"github.com/shipa-corp/ketch/internal/utils/conversions"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"testing"
 
@@ -306,6 +310,208 @@ func Test_updateAppCRD(t *testing.T) {
 				require.Equal(t, mock.app.Spec.Deployments[0].Version, ketchv1.DeploymentVersion(1))
 			},
 		},
+		{
+			name: "blue-green strategy, add an idle deployment at full scale",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:latest",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": []string{"web"}, "worker": []string{"worker"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Strategy.Type = ketchv1.BlueGreenStrategy
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:   "test/pack-test:latest",
+								Version: 1,
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name:  "web",
+										Cmd:   []string{"web"},
+										Units: intRef(5),
+									},
+								},
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.True(t, mock.app.Spec.Strategy.BlueGreen.Active)
+				require.Len(t, mock.app.Spec.Deployments, 2)
+				require.Equal(t, uint8(100), mock.app.Spec.Deployments[0].RoutingSettings.Weight)
+				idle := mock.app.Spec.Deployments[1]
+				require.Equal(t, ketchv1.DeploymentVersion(2), idle.Version)
+				require.Equal(t, uint8(0), idle.RoutingSettings.Weight)
+				// the idle deployment runs with the same number of units as the current one
+				require.Equal(t, "web", idle.Processes[0].Name)
+				require.Equal(t, intRef(5), idle.Processes[0].Units)
+				require.Nil(t, idle.Processes[1].Units)
+			},
+		},
+		{
+			name: "new image keeps autoscaling, resources, envFrom, volume mounts and termination grace period of processes, application.yaml settings take precedence",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": []string{"web"}, "worker": []string{"worker"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{
+						{
+							Name: "worker",
+							Resources: &v1.ResourceRequirements{
+								Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("512Mi")},
+							},
+							EnvFrom: []v1.EnvFromSource{
+								{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "worker-credentials"}}},
+							},
+							TerminationGracePeriodSeconds: conversions.Int64Ptr(120),
+							InitContainers:                []ketchv1.Container{{Name: "wait-for-queue", Image: "busybox:1.34"}},
+							Scheduling:                    &ketchv1.SchedulingSpec{NodeSelector: map[string]string{"pool": "batch"}},
+							DisruptionBudget:              &ketchv1.DisruptionBudgetSpec{MaxUnavailable: intOrStringRef(intstr.FromInt(2))},
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:   "test/pack-test:v1",
+								Version: 1,
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name:        "web",
+										Cmd:         []string{"web"},
+										Autoscaling: &ketchv1.AutoscalingSpec{MinReplicas: 2, MaxReplicas: 4},
+										Resources: &v1.ResourceRequirements{
+											Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("250m")},
+										},
+										EnvFrom: []v1.EnvFromSource{
+											{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-settings"}}},
+										},
+										VolumeMounts:                  []v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}},
+										TerminationGracePeriodSeconds: conversions.Int64Ptr(45),
+										Sidecars:                      []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}},
+										Scheduling:                    &ketchv1.SchedulingSpec{PriorityClassName: "high"},
+										DisruptionBudget:              &ketchv1.DisruptionBudgetSpec{MinAvailable: intOrStringRef(intstr.FromString("50%"))},
+									},
+									{
+										Name:                          "worker",
+										Cmd:                           []string{"worker"},
+										TerminationGracePeriodSeconds: conversions.Int64Ptr(60),
+										Resources: &v1.ResourceRequirements{
+											Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("128Mi")},
+										},
+									},
+								},
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				processes := mock.app.Spec.Deployments[0].Processes
+				require.Equal(t, "web", processes[0].Name)
+				require.Equal(t, &ketchv1.AutoscalingSpec{MinReplicas: 2, MaxReplicas: 4}, processes[0].Autoscaling)
+				require.Equal(t, resource.MustParse("250m"), processes[0].Resources.Requests[v1.ResourceCPU])
+				require.Equal(t, "worker", processes[1].Name)
+				require.Nil(t, processes[1].Autoscaling)
+				require.Equal(t, resource.MustParse("512Mi"), processes[1].Resources.Limits[v1.ResourceMemory])
+				require.Equal(t, "web-settings", processes[0].EnvFrom[0].ConfigMapRef.Name)
+				require.Equal(t, "worker-credentials", processes[1].EnvFrom[0].SecretRef.Name)
+				require.Equal(t, []v1.VolumeMount{{Name: "uploads", MountPath: "/uploads"}}, processes[0].VolumeMounts)
+				require.Nil(t, processes[1].VolumeMounts)
+				require.Equal(t, conversions.Int64Ptr(45), processes[0].TerminationGracePeriodSeconds)
+				require.Equal(t, conversions.Int64Ptr(120), processes[1].TerminationGracePeriodSeconds)
+				require.Equal(t, []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}}, processes[0].Sidecars)
+				require.Equal(t, []ketchv1.Container{{Name: "wait-for-queue", Image: "busybox:1.34"}}, processes[1].InitContainers)
+				require.Equal(t, &ketchv1.SchedulingSpec{PriorityClassName: "high"}, processes[0].Scheduling)
+				require.Equal(t, &ketchv1.SchedulingSpec{NodeSelector: map[string]string{"pool": "batch"}}, processes[1].Scheduling)
+				require.Equal(t, &ketchv1.DisruptionBudgetSpec{MinAvailable: intOrStringRef(intstr.FromString("50%"))}, processes[0].DisruptionBudget)
+				require.Equal(t, &ketchv1.DisruptionBudgetSpec{MaxUnavailable: intOrStringRef(intstr.FromInt(2))}, processes[1].DisruptionBudget)
+			},
+		},
+		{
+			name: "settings removed from application.yaml are removed from its processes",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": []string{"web"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{{Name: "web"}},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{
+								Image:   "test/pack-test:v1",
+								Version: 1,
+								Processes: []ketchv1.ProcessSpec{
+									{
+										Name:             "web",
+										Cmd:              []string{"web"},
+										Sidecars:         []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}},
+										InitContainers:   []ketchv1.Container{{Name: "wait-for-db", Image: "busybox:1.34"}},
+										Scheduling:       &ketchv1.SchedulingSpec{NodeSelector: map[string]string{"pool": "web"}},
+										DisruptionBudget: &ketchv1.DisruptionBudgetSpec{MinAvailable: intOrStringRef(intstr.FromString("50%"))},
+									},
+								},
+								RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+							},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Len(t, mock.app.Spec.Deployments, 1)
+				processes := mock.app.Spec.Deployments[0].Processes
+				require.Equal(t, "web", processes[0].Name)
+				require.Nil(t, processes[0].Sidecars)
+				require.Nil(t, processes[0].InitContainers)
+				require.Nil(t, processes[0].Scheduling)
+				require.Nil(t, processes[0].DisruptionBudget)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -327,6 +533,57 @@ func Test_updateAppCRD(t *testing.T) {
 	}
 }
 
+func Test_updateAppCRD_replaceActiveBlueGreen(t *testing.T) {
+	procFile := &chart.Procfile{
+		Processes:           map[string][]string{"web": []string{"web"}},
+		RoutableProcessName: "web",
+	}
+	configFile := &registryv1.ConfigFile{
+		Config: registryv1.Config{
+			ExposedPorts: make(map[string]struct{}),
+		},
+	}
+	switchedAt := metav1.Now()
+	m := newMockClient()
+	m.app.Spec.DeploymentsCount = 2
+	m.app.Spec.Strategy.Type = ketchv1.ReplaceStrategy
+	m.app.Spec.Strategy.BlueGreen.Active = true
+	m.app.Spec.Strategy.BlueGreen.SwitchedAt = &switchedAt
+	m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+		{
+			Image:           "test/pack-test:v1",
+			Version:         1,
+			Processes:       []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"web"}}},
+			RoutingSettings: ketchv1.RoutingSettings{Weight: 0},
+		},
+		{
+			Image:           "test/pack-test:v2",
+			Version:         2,
+			Processes:       []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"web"}}},
+			RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+		},
+	}
+	svc := &Services{Client: m}
+
+	// a replace deployment replaces both deployments and finishes the blue-green deployment
+	_, err := updateAppCRD(context.Background(), svc, "test-app", updateAppCRDRequest{image: "test/pack-test:v3", procFile: procFile, configFile: configFile})
+	require.Nil(t, err)
+	require.Len(t, m.app.Spec.Deployments, 1)
+	require.Equal(t, "test/pack-test:v3", m.app.Spec.Deployments[0].Image)
+	require.False(t, m.app.Spec.Strategy.BlueGreen.Active)
+	require.Nil(t, m.app.Spec.Strategy.BlueGreen.SwitchedAt)
+
+	// so the next blue-green deployment is accepted
+	image := "test/pack-test:v4"
+	strategy := string(ketchv1.BlueGreenStrategy)
+	require.Nil(t, validateDeploy(&ChangeSet{appName: "test-app", image: &image, strategy: &strategy}, m.app))
+	m.app.Spec.Strategy.Type = ketchv1.BlueGreenStrategy
+	_, err = updateAppCRD(context.Background(), svc, "test-app", updateAppCRDRequest{image: image, procFile: procFile, configFile: configFile})
+	require.Nil(t, err)
+	require.Len(t, m.app.Spec.Deployments, 2)
+	require.True(t, m.app.Spec.Strategy.BlueGreen.Active)
+}
+
 func Test_makeProcfile(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -398,3 +655,47 @@ func Test_makeProcfile(t *testing.T) {
 		})
 	}
 }
+
+func Test_withSecretEnvs(t *testing.T) {
+	secretEnv := func(name string) ketchv1.Env {
+		return ketchv1.Env{
+			Name: name,
+			ValueFrom: &ketchv1.EnvVarSource{
+				SecretKeyRef: &v1.SecretKeySelector{
+					LocalObjectReference: v1.LocalObjectReference{Name: "app-env"},
+					Key:                  name,
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		current []ketchv1.Env
+		envs    []ketchv1.Env
+		want    []ketchv1.Env
+	}{
+		{
+			name:    "secret envs are kept",
+			current: []ketchv1.Env{{Name: "FOO", Value: "bar"}, secretEnv("DB_PASSWORD")},
+			envs:    []ketchv1.Env{{Name: "LOG_LEVEL", Value: "info"}},
+			want:    []ketchv1.Env{{Name: "LOG_LEVEL", Value: "info"}, secretEnv("DB_PASSWORD")},
+		},
+		{
+			name:    "masked value keeps the secret env",
+			current: []ketchv1.Env{secretEnv("DB_PASSWORD")},
+			envs:    []ketchv1.Env{{Name: "DB_PASSWORD", Value: ketchv1.MaskedEnvValue}, {Name: "FOO", Value: "bar"}},
+			want:    []ketchv1.Env{secretEnv("DB_PASSWORD"), {Name: "FOO", Value: "bar"}},
+		},
+		{
+			name:    "plain value replaces the secret env",
+			current: []ketchv1.Env{secretEnv("DB_PASSWORD")},
+			envs:    []ketchv1.Env{{Name: "DB_PASSWORD", Value: "password"}},
+			want:    []ketchv1.Env{{Name: "DB_PASSWORD", Value: "password"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, withSecretEnvs(tt.current, tt.envs))
+		})
+	}
+}
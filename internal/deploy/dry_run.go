@@ -0,0 +1,77 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+// DryRun returns the app as it would be after the deployment without changing anything in the cluster.
+// Deploying from source code isn't supported because it builds and pushes an image.
+func (r Runner) DryRun(ctx context.Context, svc *Services) (*ketchv1.App, error) {
+	if r.params.sourcePath != nil {
+		return nil, fmt.Errorf("dry run of a deployment from source code is not supported, deploy an image instead")
+	}
+	params := *r.params
+	params.wait = nil
+
+	dryRunClient := newDryRunClient(svc.Client)
+	dryRunSvc := *svc
+	dryRunSvc.Client = dryRunClient
+
+	app, err := getUpdatedApp(ctx, dryRunSvc.Client, &params)
+	if err != nil {
+		return nil, err
+	}
+	if err := deployImage(ctx, &dryRunSvc, app, &params); err != nil {
+		return nil, err
+	}
+	return dryRunClient.apps[params.appName], nil
+}
+
+// dryRunClient reads objects from the cluster and keeps created and updated apps in memory.
+type dryRunClient struct {
+	Client
+	apps map[string]*ketchv1.App
+}
+
+func newDryRunClient(c Client) *dryRunClient {
+	return &dryRunClient{
+		Client: c,
+		apps:   map[string]*ketchv1.App{},
+	}
+}
+
+func (c *dryRunClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if app, ok := obj.(*ketchv1.App); ok {
+		if stored, ok := c.apps[key.Name]; ok {
+			stored.DeepCopyInto(app)
+			return nil
+		}
+	}
+	return c.Client.Get(ctx, key, obj)
+}
+
+func (c *dryRunClient) Create(_ context.Context, obj runtime.Object, _ ...client.CreateOption) error {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return fmt.Errorf("dry run can't create %T", obj)
+	}
+	app.Generation = 1
+	c.apps[app.Name] = app.DeepCopy()
+	return nil
+}
+
+func (c *dryRunClient) Update(_ context.Context, obj runtime.Object, _ ...client.UpdateOption) error {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return fmt.Errorf("dry run can't update %T", obj)
+	}
+	app.Generation++
+	c.apps[app.Name] = app.DeepCopy()
+	return nil
+}
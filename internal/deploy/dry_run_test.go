@@ -0,0 +1,110 @@
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
+)
+
+func TestRunner_DryRun(t *testing.T) {
+	image := "shipasoftware/go-app:v2"
+	source := "src"
+	description := "new description"
+	prometheusURL := "http://prometheus:9090"
+	steps := 4
+	stepInterval := "1m"
+	successRate := 99
+	getImageConfig := func(ctx context.Context, args ImageConfigRequest) (*registryv1.ConfigFile, error) {
+		return &registryv1.ConfigFile{
+			Config: registryv1.Config{
+				Cmd: []string{"/app"},
+			},
+		}, nil
+	}
+	tests := []struct {
+		name        string
+		changeSet   ChangeSet
+		deployments []ketchv1.AppDeploymentSpec
+		ingressType ketchv1.IngressControllerType
+		want        func(t *testing.T, app *ketchv1.App)
+		wantErr     string
+	}{
+		{
+			name:      "deploy an image",
+			changeSet: ChangeSet{appName: "dashboard", image: &image, description: &description},
+			want: func(t *testing.T, app *ketchv1.App) {
+				require.Equal(t, "new description", app.Spec.Description)
+				require.Len(t, app.Spec.Deployments, 1)
+				require.Equal(t, image, app.Spec.Deployments[0].Image)
+				require.Equal(t, ketchv1.DeploymentVersion(1), app.Spec.Deployments[0].Version)
+				require.Equal(t, []string{"/app"}, app.Spec.Deployments[0].Processes[0].Cmd)
+			},
+		},
+		{
+			name:      "deploy from source",
+			changeSet: ChangeSet{appName: "dashboard", image: &image, sourcePath: &source},
+			wantErr:   "dry run of a deployment from source code is not supported, deploy an image instead",
+		},
+		{
+			name:      "no image",
+			changeSet: ChangeSet{appName: "dashboard"},
+			wantErr:   `"image" missing image is required`,
+		},
+		{
+			name: "canary analysis without queries on a gateway-api framework",
+			changeSet: ChangeSet{
+				appName:             "dashboard",
+				image:               &image,
+				steps:               &steps,
+				stepTimeInterval:    &stepInterval,
+				analysisURL:         &prometheusURL,
+				analysisSuccessRate: &successRate,
+			},
+			deployments: []ketchv1.AppDeploymentSpec{{Image: "shipasoftware/go-app:v1", Version: 1}},
+			ingressType: ketchv1.GatewayAPIIngressControllerType,
+			wantErr:     "canary deployment failed: success rate query is not defined, gateway-api ingress controller has no default queries",
+		},
+		{
+			name: "canary with a header and a cookie rule on an nginx framework",
+			changeSet: ChangeSet{
+				appName:          "dashboard",
+				image:            &image,
+				steps:            &steps,
+				stepTimeInterval: &stepInterval,
+				canaryHeaders:    &[]string{"X-Canary=true"},
+				canaryCookies:    &[]string{"canary=always"},
+			},
+			deployments: []ketchv1.AppDeploymentSpec{{Image: "shipasoftware/go-app:v1", Version: 1}},
+			ingressType: ketchv1.NginxIngressControllerType,
+			wantErr:     "canary deployment failed: nginx ingress controller supports only one canary header or cookie rule",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := newMockClient()
+			mock.app.Name = "dashboard"
+			mock.app.Spec.Deployments = tt.deployments
+			mock.framework.Spec.IngressController.IngressType = tt.ingressType
+			svc := &Services{
+				Client:         mock,
+				GetImageConfig: getImageConfig,
+				Writer:         &bytes.Buffer{},
+			}
+			got, err := New(&tt.changeSet).DryRun(context.Background(), svc)
+			require.Zero(t, mock.createCounter)
+			require.Zero(t, mock.updateCounter)
+			if len(tt.wantErr) > 0 {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "foo", mock.app.Spec.Description)
+			tt.want(t, got)
+		})
+	}
+}
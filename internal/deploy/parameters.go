@@ -7,9 +7,11 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
+	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -40,6 +42,17 @@ const (
 	FlagVersion        = "unit-version"
 	FlagProcess        = "unit-process"
 
+	FlagAnalysisPrometheusURL = "analysis-prometheus-url"
+	FlagAnalysisSuccessRate   = "analysis-success-rate"
+	FlagAnalysisMaxLatency    = "analysis-max-latency"
+	FlagAnalysisFailureLimit  = "analysis-failure-limit"
+	FlagCanaryHeader          = "canary-header"
+	FlagCanaryCookie          = "canary-cookie"
+	FlagStrategy              = "strategy"
+	FlagKeepPrevious          = "keep-previous"
+	FlagPreviewCname          = "preview-cname"
+	FlagDryRun                = "dry-run"
+
 	FlagAppShort         = "a"
 	FlagImageShort       = "i"
 	FlagDescriptionShort = "d"
@@ -95,6 +108,17 @@ type Options struct {
 	Units   int
 	Version int
 	Process string
+
+	AnalysisPrometheusURL string
+	AnalysisSuccessRate   int
+	AnalysisMaxLatency    int
+	AnalysisFailureLimit  int
+	CanaryHeaders         []string
+	CanaryCookies         []string
+
+	Strategy     string
+	KeepPrevious string
+	PreviewCname string
 }
 
 type ChangeSet struct {
@@ -110,12 +134,14 @@ type ChangeSet struct {
 	subPaths             *[]string
 	description          *string
 	envs                 *[]string
+	envFrom              *[]v1.EnvFromSource
 	framework            *string
 	dockerRegistrySecret *string
 	builder              *string
 	buildPacks           *[]string
 	appVersion           *string
 	appType              *string
+	schedule             *string
 	appUnit              *int
 	processes            *[]ketchv1.ProcessSpec
 	ketchYamlData        *ketchv1.KetchYamlData
@@ -123,6 +149,15 @@ type ChangeSet struct {
 	units                *int
 	version              *int
 	process              *string
+	analysisURL          *string
+	analysisSuccessRate  *int
+	analysisMaxLatency   *int
+	analysisFailureLimit *int
+	canaryHeaders        *[]string
+	canaryCookies        *[]string
+	strategy             *string
+	keepPrevious         *string
+	previewCname         *string
 }
 
 func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
@@ -182,6 +217,33 @@ func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
 		FlagProcess: func(c *ChangeSet) {
 			c.process = &o.Process
 		},
+		FlagAnalysisPrometheusURL: func(c *ChangeSet) {
+			c.analysisURL = &o.AnalysisPrometheusURL
+		},
+		FlagAnalysisSuccessRate: func(c *ChangeSet) {
+			c.analysisSuccessRate = &o.AnalysisSuccessRate
+		},
+		FlagAnalysisMaxLatency: func(c *ChangeSet) {
+			c.analysisMaxLatency = &o.AnalysisMaxLatency
+		},
+		FlagAnalysisFailureLimit: func(c *ChangeSet) {
+			c.analysisFailureLimit = &o.AnalysisFailureLimit
+		},
+		FlagCanaryHeader: func(c *ChangeSet) {
+			c.canaryHeaders = &o.CanaryHeaders
+		},
+		FlagCanaryCookie: func(c *ChangeSet) {
+			c.canaryCookies = &o.CanaryCookies
+		},
+		FlagStrategy: func(c *ChangeSet) {
+			c.strategy = &o.Strategy
+		},
+		FlagKeepPrevious: func(c *ChangeSet) {
+			c.keepPrevious = &o.KeepPrevious
+		},
+		FlagPreviewCname: func(c *ChangeSet) {
+			c.previewCname = &o.PreviewCname
+		},
 	}
 	for k, f := range m {
 		if flags.Changed(k) {
@@ -276,6 +338,110 @@ func (c *ChangeSet) getStepWeight() (uint8, error) {
 	return uint8(100 / steps), nil
 }
 
+// getCanaryAnalysis returns analysis rules for a canary deployment.
+func (c *ChangeSet) getCanaryAnalysis() (*ketchv1.CanaryAnalysis, error) {
+	if c.analysisURL == nil && c.analysisSuccessRate == nil && c.analysisMaxLatency == nil && c.analysisFailureLimit == nil {
+		return nil, newMissingError(FlagAnalysisPrometheusURL)
+	}
+	if c.steps == nil {
+		return nil, fmt.Errorf("%w analysis flags must be used with %s flag",
+			newInvalidUsageError(FlagAnalysisPrometheusURL), FlagSteps)
+	}
+	if c.analysisURL == nil || len(*c.analysisURL) == 0 {
+		return nil, fmt.Errorf("%w %s is required to run a canary analysis",
+			newInvalidUsageError(FlagAnalysisPrometheusURL), FlagAnalysisPrometheusURL)
+	}
+	if c.analysisSuccessRate == nil && c.analysisMaxLatency == nil {
+		return nil, fmt.Errorf("%w %s or %s is required to run a canary analysis",
+			newInvalidUsageError(FlagAnalysisPrometheusURL), FlagAnalysisSuccessRate, FlagAnalysisMaxLatency)
+	}
+	analysis := ketchv1.CanaryAnalysis{
+		PrometheusURL: *c.analysisURL,
+		SuccessRate:   c.analysisSuccessRate,
+		MaxLatency:    c.analysisMaxLatency,
+	}
+	if c.analysisSuccessRate != nil && (*c.analysisSuccessRate < 0 || *c.analysisSuccessRate > 100) {
+		return nil, fmt.Errorf("%w %s must be between 0 and 100",
+			newInvalidValueError(FlagAnalysisSuccessRate), FlagAnalysisSuccessRate)
+	}
+	if c.analysisMaxLatency != nil && *c.analysisMaxLatency < 1 {
+		return nil, fmt.Errorf("%w %s must be 1 or greater",
+			newInvalidValueError(FlagAnalysisMaxLatency), FlagAnalysisMaxLatency)
+	}
+	if c.analysisFailureLimit != nil {
+		if *c.analysisFailureLimit < 1 {
+			return nil, fmt.Errorf("%w %s must be 1 or greater",
+				newInvalidValueError(FlagAnalysisFailureLimit), FlagAnalysisFailureLimit)
+		}
+		analysis.FailureLimit = *c.analysisFailureLimit
+	}
+	return &analysis, nil
+}
+
+// getRouteMatches returns rules to route requests to a canary deployment regardless of the traffic weights.
+func (c *ChangeSet) getRouteMatches() ([]ketchv1.RouteMatch, error) {
+	if c.canaryHeaders == nil && c.canaryCookies == nil {
+		return nil, newMissingError(FlagCanaryHeader)
+	}
+	if c.steps == nil {
+		return nil, fmt.Errorf("%w %s and %s must be used with %s flag",
+			newInvalidUsageError(FlagCanaryHeader), FlagCanaryHeader, FlagCanaryCookie, FlagSteps)
+	}
+	var matches []ketchv1.RouteMatch
+	rules := []struct {
+		flag      string
+		values    *[]string
+		matchType ketchv1.RouteMatchType
+	}{
+		{flag: FlagCanaryHeader, values: c.canaryHeaders, matchType: ketchv1.RouteMatchHeader},
+		{flag: FlagCanaryCookie, values: c.canaryCookies, matchType: ketchv1.RouteMatchCookie},
+	}
+	for _, rule := range rules {
+		if rule.values == nil {
+			continue
+		}
+		for _, value := range *rule.values {
+			parts := strings.SplitN(value, "=", 2)
+			if len(parts) != 2 || len(parts[0]) == 0 {
+				return nil, fmt.Errorf("%w %s must be in NAME=VALUE format",
+					newInvalidValueError(rule.flag), rule.flag)
+			}
+			matches = append(matches, ketchv1.RouteMatch{Type: rule.matchType, Name: parts[0], Value: parts[1]})
+		}
+	}
+	return matches, nil
+}
+
+func (c *ChangeSet) getStrategy() (ketchv1.StrategyType, error) {
+	if c.strategy == nil {
+		return "", newMissingError(FlagStrategy)
+	}
+	switch strategy := ketchv1.StrategyType(*c.strategy); strategy {
+	case ketchv1.ReplaceStrategy, ketchv1.BlueGreenStrategy:
+		return strategy, nil
+	}
+	return "", fmt.Errorf("%w %s must be either %s or %s",
+		newInvalidValueError(FlagStrategy), FlagStrategy, ketchv1.ReplaceStrategy, ketchv1.BlueGreenStrategy)
+}
+
+func (c *ChangeSet) getKeepPrevious() (time.Duration, error) {
+	if c.keepPrevious == nil {
+		return 0, newMissingError(FlagKeepPrevious)
+	}
+	d, err := time.ParseDuration(*c.keepPrevious)
+	if err != nil || d <= 0 {
+		return 0, newInvalidValueError(FlagKeepPrevious)
+	}
+	return d, nil
+}
+
+func (c *ChangeSet) getPreviewCname() (string, error) {
+	if c.previewCname == nil {
+		return "", newMissingError(FlagPreviewCname)
+	}
+	return *c.previewCname, nil
+}
+
 func (c *ChangeSet) getEnvironments() ([]ketchv1.Env, error) {
 	if c.envs == nil {
 		return nil, newMissingError(FlagEnvironment)
@@ -287,6 +453,40 @@ func (c *ChangeSet) getEnvironments() ([]ketchv1.Env, error) {
 	return envs, nil
 }
 
+func (c *ChangeSet) getEnvFrom() ([]v1.EnvFromSource, error) {
+	if c.envFrom == nil {
+		return nil, newMissingError("envFrom")
+	}
+	return *c.envFrom, nil
+}
+
+func (c *ChangeSet) getAppType() (ketchv1.AppType, error) {
+	if c.appType == nil {
+		return "", newMissingError("type")
+	}
+	switch appType := ketchv1.AppType(*c.appType); appType {
+	case ketchv1.ApplicationAppType, ketchv1.JobAppType:
+		return appType, nil
+	}
+	return "", fmt.Errorf("%w type must be either %s or %s",
+		newInvalidValueError("type"), ketchv1.ApplicationAppType, ketchv1.JobAppType)
+}
+
+// getSchedule returns a schedule of a Job application in Cron format, an empty schedule means the job isn't periodic.
+func (c *ChangeSet) getSchedule() (string, error) {
+	if c.schedule == nil {
+		return "", newMissingError("schedule")
+	}
+	schedule := strings.TrimSpace(*c.schedule)
+	if schedule == "" || strings.HasPrefix(schedule, "@") {
+		return schedule, nil
+	}
+	if len(strings.Fields(schedule)) != 5 {
+		return "", fmt.Errorf("%w schedule must be in Cron format, ex. \"*/5 * * * *\"", newInvalidValueError("schedule"))
+	}
+	return schedule, nil
+}
+
 func (c *ChangeSet) getWait() (bool, error) {
 	if c.wait == nil {
 		return false, newMissingError(FlagWait)
@@ -409,6 +609,9 @@ func (c *ChangeSet) getKetchYaml() (*ketchv1.KetchYamlData, error) {
 	if err = yaml.Unmarshal(content, data, decodeOpts...); err != nil {
 		return nil, err
 	}
+	if err := data.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", newInvalidValueError(FlagKetchYaml), err)
+	}
 	return data, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
 )
 
 func intRef(i int) *int {
@@ -48,3 +50,185 @@ func TestChangeSet_getStepWeight(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeSet_getCanaryAnalysis(t *testing.T) {
+	url := "http://prometheus:9090"
+	emptyURL := ""
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    *ketchv1.CanaryAnalysis
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			set:  ChangeSet{steps: intRef(4), analysisURL: &url, analysisSuccessRate: intRef(99), analysisMaxLatency: intRef(500), analysisFailureLimit: intRef(2)},
+			want: &ketchv1.CanaryAnalysis{PrometheusURL: url, SuccessRate: intRef(99), MaxLatency: intRef(500), FailureLimit: 2},
+		},
+		{
+			name:    "error - no analysis flags",
+			set:     ChangeSet{steps: intRef(4)},
+			wantErr: `"analysis-prometheus-url" missing`,
+		},
+		{
+			name:    "error - no steps",
+			set:     ChangeSet{analysisURL: &url, analysisSuccessRate: intRef(99)},
+			wantErr: `"analysis-prometheus-url" used improperly analysis flags must be used with steps flag`,
+		},
+		{
+			name:    "error - no prometheus url",
+			set:     ChangeSet{steps: intRef(4), analysisURL: &emptyURL, analysisSuccessRate: intRef(99)},
+			wantErr: `"analysis-prometheus-url" used improperly analysis-prometheus-url is required to run a canary analysis`,
+		},
+		{
+			name:    "error - no thresholds",
+			set:     ChangeSet{steps: intRef(4), analysisURL: &url},
+			wantErr: `"analysis-prometheus-url" used improperly analysis-success-rate or analysis-max-latency is required to run a canary analysis`,
+		},
+		{
+			name:    "error - invalid success rate",
+			set:     ChangeSet{steps: intRef(4), analysisURL: &url, analysisSuccessRate: intRef(101)},
+			wantErr: `"analysis-success-rate" invalid value analysis-success-rate must be between 0 and 100`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := tt.set.getCanaryAnalysis()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, analysis)
+		})
+	}
+}
+
+func TestChangeSet_getRouteMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    []ketchv1.RouteMatch
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			set:  ChangeSet{steps: intRef(4), canaryHeaders: &[]string{"X-Canary=true"}, canaryCookies: &[]string{"canary=always", "beta="}},
+			want: []ketchv1.RouteMatch{
+				{Type: ketchv1.RouteMatchHeader, Name: "X-Canary", Value: "true"},
+				{Type: ketchv1.RouteMatchCookie, Name: "canary", Value: "always"},
+				{Type: ketchv1.RouteMatchCookie, Name: "beta", Value: ""},
+			},
+		},
+		{
+			name:    "error - no match flags",
+			set:     ChangeSet{steps: intRef(4)},
+			wantErr: `"canary-header" missing`,
+		},
+		{
+			name:    "error - no steps",
+			set:     ChangeSet{canaryHeaders: &[]string{"X-Canary=true"}},
+			wantErr: `"canary-header" used improperly canary-header and canary-cookie must be used with steps flag`,
+		},
+		{
+			name:    "error - invalid format",
+			set:     ChangeSet{steps: intRef(4), canaryCookies: &[]string{"canary"}},
+			wantErr: `"canary-cookie" invalid value canary-cookie must be in NAME=VALUE format`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := tt.set.getRouteMatches()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, matches)
+		})
+	}
+}
+
+func TestChangeSet_getStrategy(t *testing.T) {
+	blueGreen := "blue-green"
+	unknown := "rolling"
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    ketchv1.StrategyType
+		wantErr string
+	}{
+		{
+			name: "happy path",
+			set:  ChangeSet{strategy: &blueGreen},
+			want: ketchv1.BlueGreenStrategy,
+		},
+		{
+			name:    "error - no strategy",
+			set:     ChangeSet{},
+			wantErr: `"strategy" missing`,
+		},
+		{
+			name:    "error - unknown strategy",
+			set:     ChangeSet{strategy: &unknown},
+			wantErr: `"strategy" invalid value strategy must be either replace or blue-green`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := tt.set.getStrategy()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, strategy)
+		})
+	}
+}
+
+func TestChangeSet_getSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule string
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "cron format",
+			schedule: " */5 * * * * ",
+			want:     "*/5 * * * *",
+		},
+		{
+			name:     "predefined schedule",
+			schedule: "@hourly",
+			want:     "@hourly",
+		},
+		{
+			name:     "no schedule",
+			schedule: "",
+			want:     "",
+		},
+		{
+			name:     "error - invalid schedule",
+			schedule: "every 5 minutes",
+			wantErr:  `"schedule" invalid value schedule must be in Cron format, ex. "*/5 * * * *"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := ChangeSet{schedule: &tt.schedule}
+			schedule, err := set.getSchedule()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, schedule)
+		})
+	}
+}
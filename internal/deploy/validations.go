@@ -97,6 +97,66 @@ func validateDeploy(cs *ChangeSet, app *ketchv1.App) error {
 		}
 	}
 
+	_, err = cs.getCanaryAnalysis()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
+	_, err = cs.getRouteMatches()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
+	strategy, err := cs.getStrategy()
+	if !isMissing(err) && !isValid(err) {
+		return err
+	}
+	if isMissing(err) {
+		strategy = app.Spec.Strategy.Type
+	}
+	if strategy == ketchv1.BlueGreenStrategy {
+		if _, err := cs.getSteps(); !isMissing(err) {
+			return fmt.Errorf("%w %s can't be used with %s strategy",
+				newInvalidUsageError(FlagSteps), FlagSteps, ketchv1.BlueGreenStrategy)
+		}
+		if app.Spec.Strategy.BlueGreen.Active {
+			return fmt.Errorf("blue-green deployment failed. The app has an active blue-green deployment, switch it or wait until the idle deployment is removed")
+		}
+	}
+
+	appType, err := cs.getAppType()
+	if !isMissing(err) && !isValid(err) {
+		return err
+	}
+	if appType == ketchv1.JobAppType || app.IsJob() {
+		if _, err := cs.getSteps(); !isMissing(err) {
+			return fmt.Errorf("%w %s can't be used with a %s app",
+				newInvalidUsageError(FlagSteps), FlagSteps, ketchv1.JobAppType)
+		}
+		if strategy == ketchv1.BlueGreenStrategy {
+			return fmt.Errorf("%w %s strategy can't be used with a %s app",
+				newInvalidUsageError(FlagStrategy), ketchv1.BlueGreenStrategy, ketchv1.JobAppType)
+		}
+	}
+
+	_, err = cs.getSchedule()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
+	_, err = cs.getKeepPrevious()
+	if !isMissing(err) {
+		if !isValid(err) {
+			return err
+		}
+	}
+
 	_, err = cs.getUnits()
 	if !isMissing(err) {
 		if !isValid(err) {
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 
 	ketchv1 "github.com/shipa-corp/ketch/internal/api/v1beta1"
@@ -16,27 +17,37 @@ import (
 // Application represents the fields in an application.yaml file that will be
 // transitioned to a ChangeSet.
 type Application struct {
-	Version        *string   `json:"version"`
-	Type           *string   `json:"type"`
-	Name           *string   `json:"name"`
-	Image          *string   `json:"image,omitempty"`
-	Framework      *string   `json:"framework"`
-	Description    *string   `json:"description,omitempty"`
-	Environment    []string  `json:"environment,omitempty"`
-	RegistrySecret *string   `json:"registrySecret,omitempty"`
-	Builder        *string   `json:"builder,omitempty"`
-	BuildPacks     []string  `json:"buildPacks,omitempty"`
-	Processes      []Process `json:"processes,omitempty"`
-	CName          *CName    `json:"cname,omitempty"`
-	AppUnit        *int      `json:"appUnit,omitempty"`
+	Version        *string            `json:"version"`
+	Type           *string            `json:"type"`
+	Schedule       *string            `json:"schedule,omitempty"`
+	Name           *string            `json:"name"`
+	Image          *string            `json:"image,omitempty"`
+	Framework      *string            `json:"framework"`
+	Description    *string            `json:"description,omitempty"`
+	Environment    []string           `json:"environment,omitempty"`
+	EnvFrom        []v1.EnvFromSource `json:"envFrom,omitempty"`
+	RegistrySecret *string            `json:"registrySecret,omitempty"`
+	Builder        *string            `json:"builder,omitempty"`
+	BuildPacks     []string           `json:"buildPacks,omitempty"`
+	Processes      []Process          `json:"processes,omitempty"`
+	CName          *CName             `json:"cname,omitempty"`
+	AppUnit        *int               `json:"appUnit,omitempty"`
 }
 
 type Process struct {
-	Name  string `json:"name"`  // required
-	Cmd   string `json:"cmd"`   // required
-	Units *int   `json:"units"` // unset? get from AppUnit
-	Ports []Port `json:"ports"` // appDeploymentSpec
-	Hooks Hooks  `json:"hooks"`
+	Name                          string                        `json:"name"`  // required
+	Cmd                           string                        `json:"cmd"`   // required
+	Units                         *int                          `json:"units"` // unset? get from AppUnit
+	Ports                         []Port                        `json:"ports"` // appDeploymentSpec
+	Hooks                         Hooks                         `json:"hooks"`
+	Resources                     *v1.ResourceRequirements      `json:"resources,omitempty"` // unset? get from framework's defaultResources
+	EnvFrom                       []v1.EnvFromSource            `json:"envFrom,omitempty"`
+	TerminationGracePeriodSeconds *int64                        `json:"terminationGracePeriodSeconds,omitempty"` // includes the time of hooks.restart.before
+	Probes                        *ketchv1.ProcessProbes        `json:"probes,omitempty"`
+	Sidecars                      []ketchv1.Container           `json:"sidecars,omitempty"`
+	InitContainers                []ketchv1.Container           `json:"initContainers,omitempty"`
+	Scheduling                    *ketchv1.SchedulingSpec       `json:"scheduling,omitempty"`       // complemented with the framework's defaultScheduling
+	DisruptionBudget              *ketchv1.DisruptionBudgetSpec `json:"disruptionBudget,omitempty"` // unset? one unit at a time for processes with more than one unit
 }
 
 type Port struct {
@@ -89,21 +100,40 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 	var processes []ketchv1.ProcessSpec
 	var ketchYamlData ketchv1.KetchYamlData
 	if application.Processes != nil {
-		var beforeHooks []string
-		var afterHooks []string
 		ketchYamlProcessConfig := make(map[string]ketchv1.KetchYamlProcessConfig)
 		for _, process := range application.Processes {
-			processes = append(processes, ketchv1.ProcessSpec{
-				Name:  process.Name,
-				Cmd:   strings.Split(process.Cmd, " "),
-				Units: process.Units,
-				Env:   envs,
-			})
-			if process.Hooks.Restart.Before != "" {
-				beforeHooks = append(beforeHooks, process.Hooks.Restart.Before)
+			processSpec := ketchv1.ProcessSpec{
+				Name:      process.Name,
+				Cmd:       strings.Split(process.Cmd, " "),
+				Units:     process.Units,
+				Env:       envs,
+				Resources: process.Resources,
+				EnvFrom:   process.EnvFrom,
+
+				TerminationGracePeriodSeconds: process.TerminationGracePeriodSeconds,
+				Sidecars:                      process.Sidecars,
+				InitContainers:                process.InitContainers,
+				Scheduling:                    process.Scheduling,
+				DisruptionBudget:              process.DisruptionBudget,
+			}
+			if err := processSpec.ValidateContainers(*application.Name); err != nil {
+				return nil, fmt.Errorf("process %s: %w", process.Name, err)
+			}
+			if processSpec.DisruptionBudget != nil {
+				if err := processSpec.DisruptionBudget.Validate(); err != nil {
+					return nil, fmt.Errorf("process %s: %w", process.Name, err)
+				}
 			}
-			if process.Hooks.Restart.After != "" {
-				afterHooks = append(afterHooks, process.Hooks.Restart.After)
+			processes = append(processes, processSpec)
+			var restartHooks *ketchv1.KetchYamlRestartHooks
+			if process.Hooks.Restart.Before != "" || process.Hooks.Restart.After != "" {
+				restartHooks = &ketchv1.KetchYamlRestartHooks{}
+				if process.Hooks.Restart.Before != "" {
+					restartHooks.Before = []string{process.Hooks.Restart.Before}
+				}
+				if process.Hooks.Restart.After != "" {
+					restartHooks.After = []string{process.Hooks.Restart.After}
+				}
 			}
 
 			var ports []ketchv1.KetchYamlProcessPortConfig
@@ -114,27 +144,24 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 					TargetPort: port.TargetPort,
 				})
 			}
-			if len(process.Ports) > 0 {
+			if len(process.Ports) > 0 || process.Probes != nil || restartHooks != nil {
 				ketchYamlProcessConfig[process.Name] = ketchv1.KetchYamlProcessConfig{
-					Ports: ports,
+					Ports:        ports,
+					Probes:       process.Probes,
+					RestartHooks: restartHooks,
 				}
 			}
 		}
 
 		// assign hooks and ports (kubernetes processConfig) to ketch yaml data
-		// NOTE: there is a disparity in that the yaml file format implies that hooks and ports
-		// are per-process, while the AppSpec makes them per-deployment.
 		ketchYamlData = ketchv1.KetchYamlData{
-			Hooks: &ketchv1.KetchYamlHooks{
-				Restart: ketchv1.KetchYamlRestartHooks{
-					Before: beforeHooks,
-					After:  afterHooks,
-				},
-			},
 			Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
 				Processes: ketchYamlProcessConfig,
 			},
 		}
+		if err := ketchYamlData.Validate(); err != nil {
+			return nil, err
+		}
 	}
 	c := &ChangeSet{
 		appName:              *application.Name,
@@ -152,12 +179,23 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 	if o.AppSourcePath != "" {
 		c.sourcePath = &o.AppSourcePath
 	}
+	if application.Type != nil && *application.Type == typeJob {
+		// a job without a schedule isn't periodic, the schedule of a previous deployment is removed.
+		schedule := ""
+		if application.Schedule != nil {
+			schedule = *application.Schedule
+		}
+		c.schedule = &schedule
+	}
 	if application.CName != nil {
 		c.cname = &ketchv1.CnameList{application.CName.DNSName}
 	}
 	if application.Environment != nil {
 		c.envs = &application.Environment
 	}
+	if application.EnvFrom != nil {
+		c.envFrom = &application.EnvFrom
+	}
 	if application.BuildPacks != nil {
 		c.buildPacks = &application.BuildPacks
 	}
@@ -218,24 +256,28 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 		Framework: &app.Spec.Framework,
 	}
 
+	if app.IsJob() {
+		application.Type = conversions.StrPtr(typeJob)
+		if app.Spec.Job != nil && app.Spec.Job.Schedule != "" {
+			application.Schedule = &app.Spec.Job.Schedule
+		}
+	}
+
 	deployment := getLatestDeployment(app.Spec.Deployments)
 	if deployment != nil {
 		application.Image = &deployment.Image
 		var processes []Process
 		if deployment.KetchYaml != nil {
-			var hooks Hooks
+			var deploymentHooks Hooks
 			if deployment.KetchYaml.Hooks != nil {
-				hooks = Hooks{
-					Restart: Restart{
-						Before: strings.Join(deployment.KetchYaml.Hooks.Restart.Before, " "),
-						After:  strings.Join(deployment.KetchYaml.Hooks.Restart.After, " "),
-					},
-				}
+				deploymentHooks = restartHooks(deployment.KetchYaml.Hooks.Restart)
 			}
 
 			if deployment.KetchYaml.Kubernetes != nil && deployment.KetchYaml.Kubernetes.Processes != nil {
 				for _, process := range deployment.Processes {
 					var ports []Port
+					var probes *ketchv1.ProcessProbes
+					hooks := deploymentHooks
 					if processConfig, ok := deployment.KetchYaml.Kubernetes.Processes[process.Name]; ok {
 						for _, port := range processConfig.Ports {
 							ports = append(ports, Port{
@@ -244,13 +286,26 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 								TargetPort: port.TargetPort,
 							})
 						}
+						probes = processConfig.Probes
+						if processConfig.RestartHooks != nil {
+							hooks = restartHooks(*processConfig.RestartHooks)
+						}
 					}
 					processes = append(processes, Process{
-						Name:  process.Name,
-						Cmd:   strings.Join(process.Cmd, " "),
-						Units: process.Units,
-						Ports: ports,
-						Hooks: hooks,
+						Name:      process.Name,
+						Cmd:       strings.Join(process.Cmd, " "),
+						Units:     process.Units,
+						Ports:     ports,
+						Hooks:     hooks,
+						Resources: process.Resources,
+						EnvFrom:   process.EnvFrom,
+
+						TerminationGracePeriodSeconds: process.TerminationGracePeriodSeconds,
+						Probes:                        probes,
+						Sidecars:                      process.Sidecars,
+						InitContainers:                process.InitContainers,
+						Scheduling:                    process.Scheduling,
+						DisruptionBudget:              process.DisruptionBudget,
 					})
 				}
 				application.Processes = processes
@@ -275,15 +330,28 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 	if len(app.Spec.BuildPacks) > 0 {
 		application.BuildPacks = app.Spec.BuildPacks
 	}
+	if len(app.Spec.EnvFrom) > 0 {
+		application.EnvFrom = app.Spec.EnvFrom
+	}
 	var environment []string
 	for _, env := range app.Spec.Env {
-		environment = append(environment, fmt.Sprintf("%s=%s", env.Name, env.Value))
+		environment = append(environment, fmt.Sprintf("%s=%s", env.Name, env.DisplayValue()))
 		application.Environment = environment
 	}
 
 	return application
 }
 
+// restartHooks returns restart hooks in the application.yaml format.
+func restartHooks(hooks ketchv1.KetchYamlRestartHooks) Hooks {
+	return Hooks{
+		Restart: Restart{
+			Before: strings.Join(hooks.Before, " "),
+			After:  strings.Join(hooks.After, " "),
+		},
+	}
+}
+
 // getLatestDeployment returns the AppDeploymentSpec of the highest Version or nil
 func getLatestDeployment(deployments []ketchv1.AppDeploymentSpec) *ketchv1.AppDeploymentSpec {
 	if len(deployments) == 0 {
@@ -4,7 +4,10 @@ import (
 	"os"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/stretchr/testify/require"
 
@@ -12,6 +15,10 @@ import (
 	"github.com/shipa-corp/ketch/internal/utils/conversions"
 )
 
+func intOrStringRef(v intstr.IntOrString) *intstr.IntOrString {
+	return &v
+}
+
 func TestGetChangeSetFromYaml(t *testing.T) {
 	tests := []struct {
 		description string
@@ -34,6 +41,9 @@ buildPacks:
 environment:
   - PORT=6666
   - FOO=bar
+envFrom:
+  - configMapRef:
+      name: settings
 processes:
   - name: web
     cmd: python app.py
@@ -46,6 +56,20 @@ processes:
       restart:
         before: pwd
         after: echo "test"
+    terminationGracePeriodSeconds: 45
+    sidecars:
+      - name: cloudsql-proxy
+        image: gcr.io/cloudsql-docker/gce-proxy:1.28.0
+        args: ["-instances=project:region:db=tcp:5432"]
+        volumeMounts:
+          - name: credentials
+            mountPath: /secrets
+    initContainers:
+      - name: migrate
+        image: gcr.io/kubernetes/sample-app:latest
+        command: ["python", "migrate.py"]
+    disruptionBudget:
+      minAvailable: 50%
   - name: worker
     cmd: python app.py
     units: 1
@@ -53,6 +77,36 @@ processes:
       - targetPort: 6666
         port: 8888
         protocol: TCP
+    resources:
+      requests:
+        cpu: 250m
+        memory: 128Mi
+      limits:
+        memory: 256Mi
+    envFrom:
+      - prefix: WORKER_
+        secretRef:
+          name: worker-credentials
+    probes:
+      liveness:
+        exec:
+          command: ["celery", "inspect", "ping"]
+      startup:
+        httpGet:
+          path: /health
+          port: 6666
+          httpHeaders:
+            - name: X-Probe
+              value: startup
+        failureThreshold: 30
+    scheduling:
+      nodeSelector:
+        pool: batch
+      tolerations:
+        - key: spot
+          operator: Exists
+          effect: NoSchedule
+      priorityClassName: low
 appUnit: 2
 cname:
   dnsName: test.10.10.10.20`,
@@ -62,13 +116,16 @@ cname:
 				AppSourcePath: ".",
 			},
 			changeSet: &ChangeSet{
-				appName:              "test",
-				appUnit:              conversions.IntPtr(2),
-				yamlStrictDecoding:   true,
-				sourcePath:           conversions.StrPtr("."),
-				image:                conversions.StrPtr("gcr.io/kubernetes/sample-app:latest"),
-				description:          conversions.StrPtr("a test"),
-				envs:                 &[]string{"PORT=6666", "FOO=bar"},
+				appName:            "test",
+				appUnit:            conversions.IntPtr(2),
+				yamlStrictDecoding: true,
+				sourcePath:         conversions.StrPtr("."),
+				image:              conversions.StrPtr("gcr.io/kubernetes/sample-app:latest"),
+				description:        conversions.StrPtr("a test"),
+				envs:               &[]string{"PORT=6666", "FOO=bar"},
+				envFrom: &[]corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+				},
 				framework:            conversions.StrPtr("myframework"),
 				dockerRegistrySecret: nil,
 				builder:              conversions.StrPtr("heroku/buildpacks:20"),
@@ -91,6 +148,19 @@ cname:
 								Value: "bar",
 							},
 						},
+						TerminationGracePeriodSeconds: conversions.Int64Ptr(45),
+						Sidecars: []ketchv1.Container{
+							{
+								Name:         "cloudsql-proxy",
+								Image:        "gcr.io/cloudsql-docker/gce-proxy:1.28.0",
+								Args:         []string{"-instances=project:region:db=tcp:5432"},
+								VolumeMounts: []corev1.VolumeMount{{Name: "credentials", MountPath: "/secrets"}},
+							},
+						},
+						InitContainers: []ketchv1.Container{
+							{Name: "migrate", Image: "gcr.io/kubernetes/sample-app:latest", Command: []string{"python", "migrate.py"}},
+						},
+						DisruptionBudget: &ketchv1.DisruptionBudgetSpec{MinAvailable: intOrStringRef(intstr.FromString("50%"))},
 					},
 					{
 						Name:  "worker",
@@ -106,6 +176,23 @@ cname:
 								Value: "bar",
 							},
 						},
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("250m"),
+								corev1.ResourceMemory: resource.MustParse("128Mi"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceMemory: resource.MustParse("256Mi"),
+							},
+						},
+						EnvFrom: []corev1.EnvFromSource{
+							{Prefix: "WORKER_", SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "worker-credentials"}}},
+						},
+						Scheduling: &ketchv1.SchedulingSpec{
+							NodeSelector:      map[string]string{"pool": "batch"},
+							Tolerations:       []corev1.Toleration{{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+							PriorityClassName: "low",
+						},
 					},
 				},
 				ketchYamlData: &ketchv1.KetchYamlData{
@@ -119,6 +206,10 @@ cname:
 										TargetPort: 6666,
 									},
 								},
+								RestartHooks: &ketchv1.KetchYamlRestartHooks{
+									Before: []string{"pwd"},
+									After:  []string{"echo \"test\""},
+								},
 							},
 							"worker": ketchv1.KetchYamlProcessConfig{
 								Ports: []ketchv1.KetchYamlProcessPortConfig{
@@ -128,15 +219,22 @@ cname:
 										TargetPort: 6666,
 									},
 								},
+								Probes: &ketchv1.ProcessProbes{
+									Liveness: &ketchv1.Probe{
+										Exec: &corev1.ExecAction{Command: []string{"celery", "inspect", "ping"}},
+									},
+									Startup: &ketchv1.Probe{
+										HTTPGet: &corev1.HTTPGetAction{
+											Path:        "/health",
+											Port:        intstr.FromInt(6666),
+											HTTPHeaders: []corev1.HTTPHeader{{Name: "X-Probe", Value: "startup"}},
+										},
+										FailureThreshold: 30,
+									},
+								},
 							},
 						},
 					},
-					Hooks: &ketchv1.KetchYamlHooks{
-						Restart: ketchv1.KetchYamlRestartHooks{
-							Before: []string{"pwd"},
-							After:  []string{"echo \"test\""},
-						},
-					},
 				},
 				appVersion: conversions.StrPtr("v1"),
 				appType:    conversions.StrPtr("Application"),
@@ -160,6 +258,27 @@ image: gcr.io/kubernetes/sample-app:latest`,
 				wait:               conversions.BoolPtr(false),
 			},
 		},
+		{
+			description: "success - scheduled job",
+			yaml: `name: backup
+type: Job
+schedule: "0 3 * * *"
+framework: myframework
+image: gcr.io/kubernetes/backup:latest`,
+			options: &Options{},
+			changeSet: &ChangeSet{
+				appName:            "backup",
+				appUnit:            conversions.IntPtr(1),
+				yamlStrictDecoding: true,
+				image:              conversions.StrPtr("gcr.io/kubernetes/backup:latest"),
+				framework:          conversions.StrPtr("myframework"),
+				appVersion:         conversions.StrPtr("v1"),
+				appType:            conversions.StrPtr("Job"),
+				schedule:           conversions.StrPtr("0 3 * * *"),
+				timeout:            conversions.StrPtr(""),
+				wait:               conversions.BoolPtr(false),
+			},
+		},
 		{
 			description: "validation error - framework",
 			yaml: `name: test
@@ -178,6 +297,51 @@ processes:
 			options: &Options{},
 			errStr:  "running defined processes require a sourcePath",
 		},
+		{
+			description: "validation error - probe without a handler",
+			yaml: `name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest
+processes:
+  - name: web
+    cmd: python app.py
+    probes:
+      readiness:
+        periodSeconds: 5`,
+			options: &Options{AppSourcePath: "."},
+			errStr:  "process web: probe must have exactly one of exec, httpGet, tcpSocket and grpc",
+		},
+		{
+			description: "validation error - duplicate container names",
+			yaml: `name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest
+processes:
+  - name: web
+    cmd: python app.py
+    sidecars:
+      - name: proxy
+        image: envoyproxy/envoy:v1.20.0
+    initContainers:
+      - name: proxy
+        image: busybox:1.34`,
+			options: &Options{AppSourcePath: "."},
+			errStr:  "process web: names of sidecars and init containers of a process must be unique",
+		},
+		{
+			description: "validation error - disruption budget with both fields",
+			yaml: `name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest
+processes:
+  - name: web
+    cmd: python app.py
+    disruptionBudget:
+      minAvailable: 1
+      maxUnavailable: 1`,
+			options: &Options{AppSourcePath: "."},
+			errStr:  "process web: disruption budget must have exactly one of minAvailable and maxUnavailable",
+		},
 		{
 			description: "success - use appUnits as process.units when units are not specified",
 			yaml: `version: v1
@@ -223,9 +387,6 @@ processes:
 				appVersion: conversions.StrPtr("v1"),
 				appType:    conversions.StrPtr("Application"),
 				ketchYamlData: &ketchv1.KetchYamlData{
-					Hooks: &ketchv1.KetchYamlHooks{
-						Restart: ketchv1.KetchYamlRestartHooks{},
-					},
 					Kubernetes: &ketchv1.KetchYamlKubernetesConfig{Processes: map[string]ketchv1.KetchYamlProcessConfig{}},
 				},
 			},
@@ -310,10 +471,16 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 					Name: "test",
 				},
 				Spec: ketchv1.AppSpec{
-					Framework:      "myframework",
-					Version:        conversions.StrPtr("v1"),
-					Description:    "a test",
-					Env:            []ketchv1.Env{{Name: "TEST_KEY", Value: "TEST_VALUE"}},
+					Framework:   "myframework",
+					Version:     conversions.StrPtr("v1"),
+					Description: "a test",
+					Env: []ketchv1.Env{
+						{Name: "TEST_KEY", Value: "TEST_VALUE"},
+						{Name: "DB_PASSWORD", ValueFrom: &ketchv1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "DB_PASSWORD"}}},
+					},
+					EnvFrom: []corev1.EnvFromSource{
+						{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+					},
 					DockerRegistry: ketchv1.DockerRegistrySpec{SecretName: "a_secret"},
 					Builder:        "builder",
 					BuildPacks:     []string{"test/buildpack"},
@@ -343,15 +510,21 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 											Ports: []ketchv1.KetchYamlProcessPortConfig{
 												{Port: 9000, Protocol: "UDP", TargetPort: 9000},
 											},
+											Probes: &ketchv1.ProcessProbes{
+												Readiness: &ketchv1.Probe{GRPC: &ketchv1.GRPCAction{Port: 9000}},
+											},
+											RestartHooks: &ketchv1.KetchYamlRestartHooks{
+												Before: []string{"echo drain"},
+											},
 										},
 									},
 								},
 							},
 
 							Processes: []ketchv1.ProcessSpec{
-								{Name: "process-1", Cmd: []string{"python", "app.py"}, Units: conversions.IntPtr(1)},
-								{Name: "process-2", Cmd: []string{"go", "run", "main.go"}, Units: conversions.IntPtr(2)},
-								{Name: "process-3", Cmd: []string{"./bin/test"}, Units: conversions.IntPtr(1)},
+								{Name: "process-1", Cmd: []string{"python", "app.py"}, Units: conversions.IntPtr(1), TerminationGracePeriodSeconds: conversions.Int64Ptr(60)},
+								{Name: "process-2", Cmd: []string{"go", "run", "main.go"}, Units: conversions.IntPtr(2), Scheduling: &ketchv1.SchedulingSpec{PriorityClassName: "high"}},
+								{Name: "process-3", Cmd: []string{"./bin/test"}, Units: conversions.IntPtr(1), Sidecars: []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}}, DisruptionBudget: &ketchv1.DisruptionBudgetSpec{MaxUnavailable: intOrStringRef(intstr.FromInt(0))}},
 							},
 						},
 						{
@@ -363,13 +536,16 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 				},
 			},
 			application: &Application{
-				Version:        conversions.StrPtr("v1"),
-				Type:           conversions.StrPtr(typeApplication),
-				Name:           conversions.StrPtr("test"),
-				Image:          conversions.StrPtr("gcr.io/shipa-ci/sample-go-app:latest"),
-				Framework:      conversions.StrPtr("myframework"),
-				Description:    conversions.StrPtr("a test"),
-				Environment:    []string{"TEST_KEY=TEST_VALUE"},
+				Version:     conversions.StrPtr("v1"),
+				Type:        conversions.StrPtr(typeApplication),
+				Name:        conversions.StrPtr("test"),
+				Image:       conversions.StrPtr("gcr.io/shipa-ci/sample-go-app:latest"),
+				Framework:   conversions.StrPtr("myframework"),
+				Description: conversions.StrPtr("a test"),
+				Environment: []string{"TEST_KEY=TEST_VALUE", "DB_PASSWORD=*****"},
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}},
+				},
 				RegistrySecret: conversions.StrPtr("a_secret"),
 				Builder:        conversions.StrPtr("builder"),
 				BuildPacks:     []string{"test/buildpack"},
@@ -388,6 +564,7 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 							Before: "echo before",
 							After:  "echo after",
 						}},
+						TerminationGracePeriodSeconds: conversions.Int64Ptr(60),
 					},
 					{
 						Name:  "process-2",
@@ -397,9 +574,12 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 							{Port: 9000, Protocol: "UDP", TargetPort: 9000},
 						},
 						Hooks: Hooks{Restart: Restart{
-							Before: "echo before",
-							After:  "echo after",
+							Before: "echo drain",
 						}},
+						Probes: &ketchv1.ProcessProbes{
+							Readiness: &ketchv1.Probe{GRPC: &ketchv1.GRPCAction{Port: 9000}},
+						},
+						Scheduling: &ketchv1.SchedulingSpec{PriorityClassName: "high"},
 					},
 					{
 						Name:  "process-3",
@@ -409,6 +589,8 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 							Before: "echo before",
 							After:  "echo after",
 						}},
+						Sidecars:         []ketchv1.Container{{Name: "proxy", Image: "envoyproxy/envoy:v1.20.0"}},
+						DisruptionBudget: &ketchv1.DisruptionBudgetSpec{MaxUnavailable: intOrStringRef(intstr.FromInt(0))},
 					},
 				},
 			},
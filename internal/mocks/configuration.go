@@ -6,6 +6,7 @@ import (
 	dynamicFake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	kubeFake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -30,6 +31,7 @@ type Configuration struct {
 	StorageInstance      templates.Client
 
 	ctrlClient client.Client
+	kubeClient kubernetes.Interface
 }
 
 func (cfg *Configuration) Client() client.Client {
@@ -45,10 +47,18 @@ func (cfg *Configuration) Storage() templates.Client {
 
 // KubernetesClient returns kubernetes typed client. It's used to work with standard kubernetes types.
 func (cfg *Configuration) KubernetesClient() kubernetes.Interface {
-	return kubeFake.NewSimpleClientset(cfg.KubeClientObjects...)
+	if cfg.kubeClient == nil {
+		cfg.kubeClient = kubeFake.NewSimpleClientset(cfg.KubeClientObjects...)
+	}
+	return cfg.kubeClient
 }
 
 // DynamicClient returns kubernetes dynamic client. It's used to work with CRDs for which we don't have go types like ClusterIssuer.
 func (cfg *Configuration) DynamicClient() dynamic.Interface {
 	return dynamicFake.NewSimpleDynamicClient(runtime.NewScheme(), cfg.DynamicClientObjects...)
 }
+
+// RESTConfig returns a config to connect to the kubernetes API server. It's used to stream to and from containers.
+func (cfg *Configuration) RESTConfig() *rest.Config {
+	return &rest.Config{}
+}
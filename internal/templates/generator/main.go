@@ -12,11 +12,13 @@ import (
 )
 
 type YamlFile struct {
-	Name    string
-	Traefik bool
-	Istio   bool
-	Common  bool
-	Content string
+	Name       string
+	Traefik    bool
+	Istio      bool
+	Nginx      bool
+	GatewayAPI bool
+	Common     bool
+	Content    string
 }
 
 type context struct {
@@ -32,6 +34,8 @@ package templates
 type Yamls struct {
 	TraefikYamls map[string]string
 	IstioYamls map[string]string
+	NginxYamls map[string]string
+	GatewayAPIYamls map[string]string
 }
 
 var GeneratedYamls = Yamls{
@@ -50,6 +54,22 @@ var GeneratedYamls = Yamls{
 {{ $yaml.Content }},
 {{- end }}
 {{- end }}
+},
+  NginxYamls: map[string]string {
+{{- range $_, $yaml := .Yamls }}
+{{- if or $yaml.Nginx $yaml.Common }} 
+    "{{ $yaml.Name }}": 
+{{ $yaml.Content }},
+{{- end }}
+{{- end }}
+},
+  GatewayAPIYamls: map[string]string {
+{{- range $_, $yaml := .Yamls }}
+{{- if or $yaml.GatewayAPI $yaml.Common }} 
+    "{{ $yaml.Name }}": 
+{{ $yaml.Content }},
+{{- end }}
+{{- end }}
 },
 }
 `
@@ -60,6 +80,8 @@ func main() {
 	yamls := readDir("common")
 	yamls = append(yamls, readDir("traefik")...)
 	yamls = append(yamls, readDir("istio")...)
+	yamls = append(yamls, readDir("nginx")...)
+	yamls = append(yamls, readDir("gateway-api")...)
 
 	tmpl, err := template.New("tpl").Parse(yamlsTemplate)
 
@@ -101,11 +123,13 @@ func readDir(dir string) []YamlFile {
 			panic(err)
 		}
 		yamls = append(yamls, YamlFile{
-			Name:    info.Name(),
-			Traefik: dir == "traefik",
-			Istio:   dir == "istio",
-			Common:  dir == "common",
-			Content: fmt.Sprintf("`%s`", string(content)),
+			Name:       info.Name(),
+			Traefik:    dir == "traefik",
+			Istio:      dir == "istio",
+			Nginx:      dir == "nginx",
+			GatewayAPI: dir == "gateway-api",
+			Common:     dir == "common",
+			Content:    fmt.Sprintf("`%s`", string(content)),
 		})
 	}
 	return yamls
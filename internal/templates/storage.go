@@ -42,7 +42,7 @@ type Storage struct {
 
 // Updater knows how to update and delete templates.
 type Updater interface {
-	Update(name string, templates Templates) error
+	Update(name string, templates Templates, owners ...metav1.OwnerReference) error
 }
 
 // Reader knows how to get templates.
@@ -71,6 +71,12 @@ var (
 	TraefikDefaultTemplates = Templates{
 		Yamls: GeneratedYamls.TraefikYamls,
 	}
+	NginxDefaultTemplates = Templates{
+		Yamls: GeneratedYamls.NginxYamls,
+	}
+	GatewayAPIDefaultTemplates = Templates{
+		Yamls: GeneratedYamls.GatewayAPIYamls,
+	}
 )
 
 // IngressConfigMapName returns a name of a configmap to store the ingress' templates to render helm chart.
@@ -78,6 +84,12 @@ func IngressConfigMapName(ingress string) string {
 	return fmt.Sprintf("ingress-%s-templates", ingress)
 }
 
+// FrameworkConfigMapName returns a name of a configmap to store custom templates of the framework.
+// Unlike configmaps of ingress controllers, it's not overwritten with the default templates when ketch starts.
+func FrameworkConfigMapName(framework string) string {
+	return fmt.Sprintf("framework-%s-templates", framework)
+}
+
 // Get returns templates stored in a configmap with the provided name.
 func (s *Storage) Get(name string) (*Templates, error) {
 	ctx := context.TODO()
@@ -90,24 +102,26 @@ func (s *Storage) Get(name string) (*Templates, error) {
 }
 
 // Update creates or updates a configmap with the new templates.
-func (s *Storage) Update(name string, templates Templates) error {
+// The owners replace owner references of the configmap, so the configmap is garbage collected with its owners.
+func (s *Storage) Update(name string, templates Templates, owners ...metav1.OwnerReference) error {
 	namespacedName := types.NamespacedName{Name: name, Namespace: s.namespace}
 	ctx := context.TODO()
 	cm := v1.ConfigMap{}
 	if err := s.client.Get(ctx, namespacedName, &cm); err != nil {
 		if apierrors.IsNotFound(err) {
-			return s.client.Create(ctx, templates.toConfigMap(name, s.namespace))
+			return s.client.Create(ctx, templates.toConfigMap(name, s.namespace, owners))
 		}
 	}
-	return s.client.Update(ctx, templates.toConfigMap(name, s.namespace))
+	return s.client.Update(ctx, templates.toConfigMap(name, s.namespace, owners))
 }
 
-func (tpl Templates) toConfigMap(name string, namespace string) *v1.ConfigMap {
+func (tpl Templates) toConfigMap(name string, namespace string, owners []metav1.OwnerReference) *v1.ConfigMap {
 	cm := v1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: namespace,
+			Name:            name,
+			Namespace:       namespace,
+			OwnerReferences: owners,
 		},
 		Data: make(map[string]string, len(tpl.Yamls)),
 	}
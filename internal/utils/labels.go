@@ -6,4 +6,9 @@ const (
 	KetchProcessNameLabel       = KetchLabelPrefix + "app-process"
 	KetchDeploymentVersionLabel = KetchLabelPrefix + "app-deployment-version"
 	V1betaPrefix                = KetchLabelPrefix + "v1beta1"
+
+	// KetchIsolatedRunLabel is "true" on pods that are not units of an application, like pods of "ketch app run" and deploy hooks.
+	KetchIsolatedRunLabel = KetchLabelPrefix + "is-isolated-run"
+	// KetchDeployHookLabel is set on Jobs of deploy hooks to the kind of the hook.
+	KetchDeployHookLabel = KetchLabelPrefix + "deploy-hook"
 )